@@ -17,6 +17,7 @@ limitations under the License.
 package v1
 
 import (
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -78,6 +79,46 @@ type ImageBasedUpgradeSpec struct {
 	ExtraManifests []ConfigMapRef `json:"extraManifests,omitempty"`
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Auto Rollback On Failure"
 	AutoRollbackOnFailure *AutoRollbackOnFailure `json:"autoRollbackOnFailure,omitempty"`
+	// Schedule defines the maintenance windows in which the gated stages are allowed to start. A gated stage
+	// requested outside a window waits until the next window opens.
+	// +optional
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Schedule"
+	Schedule *Schedule `json:"schedule,omitempty"`
+	// AutoProgress enables the automatic progression from Prep to Upgrade and from Upgrade to Idle, once the current
+	// stage completes and the cluster health checks pass.
+	// +optional
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Auto Progress"
+	AutoProgress *AutoProgress `json:"autoProgress,omitempty"`
+	// Timeouts defines the maximum durations of the stages and of their phases. A stage that exceeds one of them fails
+	// with the TimedOut reason.
+	// +optional
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Timeouts"
+	Timeouts *Timeouts `json:"timeouts,omitempty"`
+	// LocalBackup defines the namespaced resources that are backed up to the new stateroot without OADP, and restored
+	// once the cluster is rebooted to it.
+	// +optional
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Local Backup"
+	LocalBackup *LocalBackup `json:"localBackup,omitempty"`
+	// BackupRetention defines whether the OADP backups of the upgrade are kept once it is finalized. If not defined,
+	// they are deleted.
+	// +optional
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Backup Retention"
+	BackupRetention *BackupRetention `json:"backupRetention,omitempty"`
+	// Quiesce defines the workloads stopped before the reboot to the new stateroot, once the applications are backed
+	// up. The scaled down workloads get their replicas back once the upgrade completes, or when it is rolled back.
+	// +optional
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Quiesce"
+	Quiesce *Quiesce `json:"quiesce,omitempty"`
+	// PostUpgradeValidation defines the list of ConfigMap resources that contain the Jobs validating the applications
+	// once they are restored. The upgrade completes only once all of the Jobs succeed.
+	// +optional
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Post-Upgrade Validation"
+	PostUpgradeValidation []ConfigMapRef `json:"postUpgradeValidation,omitempty"`
+	// InventoryCheck defines the parts of the cluster inventory that must not regress during the upgrade. The inventory
+	// is always compared before and after the reboot, and the differences are reported in status.inventoryReport.
+	// +optional
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Inventory Check"
+	InventoryCheck *InventoryCheck `json:"inventoryCheck,omitempty"`
 }
 
 // SeedImageRef defines the seed image and OCP version for the upgrade
@@ -93,6 +134,30 @@ type SeedImageRef struct {
 	// PullSecretRef defines the reference to a secret with credentials to pull container images.
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Pull Secret Reference"
 	PullSecretRef *PullSecretRef `json:"pullSecretRef,omitempty"`
+	// Verification defines how the signature of the seed image is verified. If not set, the seed image signature is
+	// not verified.
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Verification"
+	Verification *SeedImageVerification `json:"verification,omitempty"`
+}
+
+// SeedImageVerification defines the keys trusted to sign the seed image. The seed image must be signed with cosign by
+// one of them, the signature is verified against the digest of the seed image manifest before its labels are
+// inspected and before it is pulled.
+type SeedImageVerification struct {
+	// PublicKeysRef defines the reference to a secret holding the PEM encoded public keys trusted to sign the seed
+	// image. Every key found in the secret data is trusted.
+	// +kubebuilder:validation:Required
+	// +required
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Public Keys Reference"
+	PublicKeysRef PublicKeysRef `json:"publicKeysRef"`
+}
+
+// PublicKeysRef defines a reference to a secret holding PEM encoded public keys
+type PublicKeysRef struct {
+	// +kubebuilder:validation:Required
+	// +required
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	Name string `json:"name"`
 }
 
 // AutoRollbackOnFailure defines automatic rollback settings if the upgrade fails or if the upgrade does not
@@ -105,6 +170,232 @@ type AutoRollbackOnFailure struct {
 	InitMonitorTimeoutSeconds int `json:"initMonitorTimeoutSeconds,omitempty"` // LCA Init Monitor watchdog timeout, in seconds. Value = 0 is treated as "use default" when writing config file in Prep stage
 }
 
+// Schedule defines the maintenance windows for the stage transitions
+type Schedule struct {
+	// Stages defines the stages that are only allowed to start within a maintenance window.
+	// If not defined, only the Upgrade stage is gated.
+	// +optional
+	Stages []ScheduledStage `json:"stages,omitempty"`
+	// TimeZone defines the IANA time zone name, e.g. "America/New_York", used to evaluate the recurring windows.
+	// If not defined, UTC is used.
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+	// Windows defines explicit maintenance windows with a fixed start and end time
+	// +optional
+	Windows []MaintenanceWindow `json:"windows,omitempty"`
+	// RecurringWindows defines maintenance windows that open according to a cron expression
+	// +optional
+	RecurringWindows []RecurringMaintenanceWindow `json:"recurringWindows,omitempty"`
+}
+
+// ScheduledStage defines the type for the stages that can be gated by a schedule
+// +kubebuilder:validation:Enum=Prep;Upgrade
+type ScheduledStage ImageBasedUpgradeStage
+
+// MaintenanceWindow defines a maintenance window with a fixed start and end time
+// +kubebuilder:validation:XValidation:message="end must be after start", rule="self.end > self.start"
+type MaintenanceWindow struct {
+	// Start defines the time at which the window opens
+	// +kubebuilder:validation:Required
+	// +required
+	Start metav1.Time `json:"start"`
+	// End defines the time at which the window closes
+	// +kubebuilder:validation:Required
+	// +required
+	End metav1.Time `json:"end"`
+}
+
+// RecurringMaintenanceWindow defines a maintenance window that opens according to a cron expression
+type RecurringMaintenanceWindow struct {
+	// Cron defines when the window opens, using the standard 5-field cron format, e.g. "0 2 * * 6"
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	// +required
+	Cron string `json:"cron"`
+	// Duration defines how long the window stays open, e.g. "4h"
+	// +kubebuilder:validation:Required
+	// +required
+	Duration metav1.Duration `json:"duration"`
+}
+
+// AutoProgress defines the policy for automatically progressing to the next stage
+type AutoProgress struct {
+	// SoakDuration defines how long the cluster must stay healthy after the Upgrade stage completes before it is
+	// automatically finalized to Idle, e.g. "2h". If not defined, the upgrade is finalized once the health checks pass.
+	// A failure during the soak period stops the automatic progression and, if autoRollbackOnFailure is set,
+	// rolls back the upgrade.
+	// +optional
+	SoakDuration *metav1.Duration `json:"soakDuration,omitempty"`
+}
+
+// BackupRetentionPolicy defines what happens to the OADP backups of an upgrade once it is finalized
+// +kubebuilder:validation:Enum=Delete;KeepWithTTL;KeepLast
+type BackupRetentionPolicy string
+
+// BackupRetentionPolicies defines the supported backup retention policies
+var BackupRetentionPolicies = struct {
+	Delete      BackupRetentionPolicy
+	KeepWithTTL BackupRetentionPolicy
+	KeepLast    BackupRetentionPolicy
+}{
+	Delete:      "Delete",
+	KeepWithTTL: "KeepWithTTL",
+	KeepLast:    "KeepLast",
+}
+
+// BackupRetention defines the retention of the OADP backups once the upgrade is finalized. The backups are always
+// deleted when the upgrade is rolled back or aborted.
+// +kubebuilder:validation:XValidation:message="ttl must be set with the KeepWithTTL policy",rule="self.policy != 'KeepWithTTL' || has(self.ttl)"
+// +kubebuilder:validation:XValidation:message="count must be set with the KeepLast policy",rule="self.policy != 'KeepLast' || has(self.count)"
+type BackupRetention struct {
+	// Policy defines whether the backups are deleted when the upgrade is finalized (Delete), kept until their TTL
+	// expires (KeepWithTTL), or kept along with the backups of the previous upgrades, up to count upgrades (KeepLast).
+	Policy BackupRetentionPolicy `json:"policy"`
+	// TTL defines how long Velero keeps the backups after they are created, e.g. "720h". It is required with the
+	// KeepWithTTL policy. With the KeepLast policy, the backups do not expire unless it is defined.
+	// +optional
+	TTL *metav1.Duration `json:"ttl,omitempty"`
+	// Count defines the number of upgrades whose backups are kept with the KeepLast policy
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Count *int `json:"count,omitempty"`
+}
+
+// LocalBackup selects the namespaced resources, e.g. deployments, services or configmaps, that are exported as YAML
+// into the new stateroot before the pivot and reapplied in dependency order after it. Persistent volume data is not
+// backed up, so it is only suited to stateless applications.
+// +kubebuilder:validation:XValidation:message="at least one of namespaces or labelSelector must be set",rule="has(self.namespaces) || has(self.labelSelector)"
+type LocalBackup struct {
+	// Namespaces defines the namespaces whose resources are backed up
+	// +optional
+	Namespaces []string `json:"namespaces,omitempty"`
+	// LabelSelector selects the resources to back up, within the namespaces if defined and in all the application
+	// namespaces otherwise
+	// +optional
+	LabelSelector *metav1.LabelSelector `json:"labelSelector,omitempty"`
+}
+
+// Quiesce defines the steps run in order during the Upgrade stage, after the backups and before the reboot, so that
+// stateful workloads are stopped gracefully
+type Quiesce struct {
+	// Steps defines the steps to run. A step starts once the previous one completes, and a step that fails or times
+	// out fails the Upgrade stage.
+	// +kubebuilder:validation:MinItems=1
+	Steps []QuiesceStep `json:"steps"`
+}
+
+// QuiesceStep either scales down workloads or runs a Job
+// +kubebuilder:validation:XValidation:message="exactly one of scaleDown or job must be set",rule="has(self.scaleDown) != has(self.job)"
+type QuiesceStep struct {
+	// Name identifies the step, and names its Job
+	// +kubebuilder:validation:MaxLength=50
+	// +kubebuilder:validation:Pattern="^[a-z0-9]([-a-z0-9]*[a-z0-9])?$"
+	Name string `json:"name"`
+	// ScaleDown defines the workloads scaled to zero replicas. The step completes once their pods are deleted.
+	// +optional
+	ScaleDown *QuiesceScaleDown `json:"scaleDown,omitempty"`
+	// Job defines a Job run as a pre-pivot hook, e.g. to flush the data of an application. The step completes once the
+	// Job succeeds.
+	// +optional
+	Job *QuiesceJob `json:"job,omitempty"`
+	// Timeout defines the maximum duration of the step, e.g. "10m". Defaults to 5m.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+// QuiesceScaleDown selects the Deployments and StatefulSets of a namespace, by name or with a label selector
+// +kubebuilder:validation:XValidation:message="at least one of deployments, statefulSets or labelSelector must be set",rule="has(self.deployments) || has(self.statefulSets) || has(self.labelSelector)"
+type QuiesceScaleDown struct {
+	// Namespace defines the namespace of the workloads
+	// +kubebuilder:validation:MinLength=1
+	Namespace string `json:"namespace"`
+	// Deployments defines the names of the Deployments to scale down
+	// +optional
+	Deployments []string `json:"deployments,omitempty"`
+	// StatefulSets defines the names of the StatefulSets to scale down
+	// +optional
+	StatefulSets []string `json:"statefulSets,omitempty"`
+	// LabelSelector selects the Deployments and StatefulSets to scale down
+	// +optional
+	LabelSelector *metav1.LabelSelector `json:"labelSelector,omitempty"`
+}
+
+// QuiesceJob defines a Job with a single container
+type QuiesceJob struct {
+	// Namespace defines the namespace of the Job
+	// +kubebuilder:validation:MinLength=1
+	Namespace string `json:"namespace"`
+	// Image defines the image of the container
+	// +kubebuilder:validation:MinLength=1
+	Image string `json:"image"`
+	// Command defines the command run in the container. The image entrypoint is run if not defined.
+	// +optional
+	Command []string `json:"command,omitempty"`
+	// ServiceAccountName defines the service account of the Job pod. Defaults to the default service account of the
+	// namespace.
+	// +optional
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+}
+
+// InventoryCategory defines a part of the cluster inventory
+// +kubebuilder:validation:Enum=ClusterServiceVersions;ClusterOperators;CustomResourceDefinitions;Namespaces;Workloads
+type InventoryCategory string
+
+// InventoryCategories defines the parts of the cluster inventory compared before and after the reboot
+var InventoryCategories = struct {
+	ClusterServiceVersions    InventoryCategory
+	ClusterOperators          InventoryCategory
+	CustomResourceDefinitions InventoryCategory
+	Namespaces                InventoryCategory
+	Workloads                 InventoryCategory
+}{
+	ClusterServiceVersions:    "ClusterServiceVersions",
+	ClusterOperators:          "ClusterOperators",
+	CustomResourceDefinitions: "CustomResourceDefinitions",
+	Namespaces:                "Namespaces",
+	Workloads:                 "Workloads",
+}
+
+// InventoryCheck defines the checks of the cluster inventory compared before and after the reboot
+type InventoryCheck struct {
+	// MustNotRegress defines the parts of the inventory whose regressions block the completion of the upgrade until
+	// they recover. A ClusterServiceVersion regresses when it is removed, downgraded or no longer succeeded, a
+	// ClusterOperator when it is removed or no longer available, a CustomResourceDefinition or a Namespace when it is
+	// removed, and the workloads of a namespace when fewer of their replicas are ready.
+	// +kubebuilder:validation:MinItems=1
+	MustNotRegress []InventoryCategory `json:"mustNotRegress"`
+	// Timeout defines the maximum duration to wait for the regressions to recover, e.g. "1h", after which the upgrade
+	// fails. Defaults to 30m.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+// Timeouts defines the maximum durations of the stages and of their phases, e.g. "1h30m". The durations are measured
+// from the start times recorded in status.history. A timeout that is not defined does not expire.
+type Timeouts struct {
+	// Prep defines the maximum duration of the Prep stage
+	// +optional
+	Prep *metav1.Duration `json:"prep,omitempty"`
+	// StaterootSetup defines the maximum duration of the stateroot setup job of the Prep stage
+	// +optional
+	StaterootSetup *metav1.Duration `json:"staterootSetup,omitempty"`
+	// Precache defines the maximum duration of the precache job of the Prep stage
+	// +optional
+	Precache *metav1.Duration `json:"precache,omitempty"`
+	// Upgrade defines the maximum duration of the Upgrade stage, including the reboot to the new stateroot
+	// +optional
+	Upgrade *metav1.Duration `json:"upgrade,omitempty"`
+	// PrePivot defines the maximum duration of the Upgrade steps run before the reboot, such as waiting for the
+	// cluster to stabilize and backing up the applications
+	// +optional
+	PrePivot *metav1.Duration `json:"prePivot,omitempty"`
+	// PostPivot defines the maximum duration of the Upgrade steps run after the reboot, such as waiting for the cluster
+	// to stabilize and restoring the applications. When it, or the Upgrade timeout, expires after the reboot, the
+	// upgrade is automatically rolled back unless disabled with the upgrade-completion auto-rollback annotation.
+	// +optional
+	PostPivot *metav1.Duration `json:"postPivot,omitempty"`
+}
+
 // ConfigMapRef defines a reference to a config map
 type ConfigMapRef struct {
 	// +kubebuilder:validation:Required
@@ -139,6 +430,221 @@ type ImageBasedUpgradeStatus struct {
 	// History stores timing info of different IBU stages and their important phases
 	// +optional
 	History []*History `json:"history,omitempty"`
+	// AutoProgress reports the state of the automatic stage progression
+	// +optional
+	AutoProgress *AutoProgressStatus `json:"autoProgress,omitempty"`
+	// Preflight reports the results of the last preflight run requested with the lca.openshift.io/preflight annotation
+	// +optional
+	Preflight *PreflightStatus `json:"preflight,omitempty"`
+	// SeedCompatibility reports the result of every seed image compatibility check run during Prep
+	// +optional
+	SeedCompatibility []SeedCompatibilityCheck `json:"seedCompatibility,omitempty"`
+	// RetainedBackupsSuffix is the suffix added to the names of the OADP backups kept once the upgrade is finalized,
+	// made of the target OCP version and the time the backups were first created, so that the backups of each upgrade
+	// are kept apart. It is empty when the backups are deleted.
+	// +optional
+	RetainedBackupsSuffix string `json:"retainedBackupsSuffix,omitempty"`
+	// SeedImageDigest is the digest of the seed image manifest resolved at the start of Prep. Every later operation on
+	// the seed image uses this digest, even if the seed image tag is updated.
+	// +optional
+	SeedImageDigest string `json:"seedImageDigest,omitempty"`
+	// Precache reports the progress of the image precaching done during Prep
+	// +optional
+	Precache *PrecacheStatus `json:"precache,omitempty"`
+	// BackupEstimate reports the estimated size and duration of the OADP backups, computed during Prep from the Backup
+	// CRs of spec.oadpContent
+	// +optional
+	BackupEstimate *BackupEstimateStatus `json:"backupEstimate,omitempty"`
+	// ToleratedRestoreErrors reports the errors of the partially failed OADP restores that were tolerated by the
+	// error tolerance annotations of their Restore CR
+	// +optional
+	ToleratedRestoreErrors []ToleratedRestoreErrors `json:"toleratedRestoreErrors,omitempty"`
+	// InventoryReport reports the differences between the cluster inventories taken before and after the reboot
+	// +optional
+	InventoryReport *InventoryReport `json:"inventoryReport,omitempty"`
+	// SelectedPolicies reports the policies whose manifests are selected for the target OCP version, along with the
+	// target OCP versions and version ranges that selected them
+	// +optional
+	SelectedPolicies []SelectedPolicy `json:"selectedPolicies,omitempty"`
+	// ExtraManifestsDiff reports the changes the extra manifests and the manifests extracted from policies make to the
+	// cluster, computed with a dry-run during the prep stage
+	// +optional
+	ExtraManifestsDiff *ExtraManifestsDiff `json:"extraManifestsDiff,omitempty"`
+}
+
+// SelectedPolicy defines a policy whose manifests are extracted for the target OCP version
+type SelectedPolicy struct {
+	// Name is the name of the policy
+	Name string `json:"name"`
+	// Namespace is the namespace of the policy
+	Namespace string `json:"namespace"`
+	// Manifests is the number of manifests extracted from the policy
+	Manifests int `json:"manifests"`
+	// TargetOcpVersions lists the lca.openshift.io/target-ocp-version label values and the
+	// lca.openshift.io/target-ocp-version-range annotation values that selected the manifests
+	// +optional
+	TargetOcpVersions []string `json:"targetOcpVersions,omitempty"`
+}
+
+// ToleratedRestoreErrors defines the tolerated errors of a partially failed restore
+type ToleratedRestoreErrors struct {
+	// Restore is the name of the Restore CR
+	Restore string `json:"restore"`
+	// Count is the number of item errors reported by Velero for the restore
+	Count int `json:"count"`
+	// Errors are the first item errors reported by Velero for the restore, prefixed with the namespace of the item if
+	// any. At most 10 errors are listed, each truncated to 256 characters.
+	Errors []string `json:"errors"`
+}
+
+// InventoryReport defines the summary of the inventory comparison, whose details are stored in a ConfigMap
+type InventoryReport struct {
+	// ConfigMap references the ConfigMap containing the differences between the inventories
+	ConfigMap ConfigMapRef `json:"configMap"`
+	// Changes is the number of items added, removed or changed
+	Changes int `json:"changes"`
+	// Regressions lists the regressions of the parts of the inventory defined in spec.inventoryCheck.mustNotRegress
+	// +optional
+	Regressions []string `json:"regressions,omitempty"`
+	// RegressedSince is the time the regressions were first found, from which spec.inventoryCheck.timeout is measured
+	// +optional
+	RegressedSince *metav1.Time `json:"regressedSince,omitempty"`
+}
+
+// ExtraManifestsDiff defines the summary of the extra manifests diff, whose details are stored in a ConfigMap
+type ExtraManifestsDiff struct {
+	// ConfigMap references the ConfigMap containing the diff of each manifest
+	ConfigMap ConfigMapRef `json:"configMap"`
+	// Created is the number of objects that will be created
+	Created int `json:"created"`
+	// Updated is the number of objects that will be updated
+	Updated int `json:"updated"`
+	// Deleted is the number of objects that will be deleted
+	Deleted int `json:"deleted"`
+	// Unchanged is the number of objects that will not change
+	Unchanged int `json:"unchanged"`
+	// Unknown is the number of manifests whose dry-run could not be done on the current cluster
+	Unknown int `json:"unknown"`
+}
+
+// BackupEstimateStatus defines the estimated size and duration of the OADP backups done before the pivot
+type BackupEstimateStatus struct {
+	// Resources is the number of resources matched by the backups
+	Resources int `json:"resources"`
+	// Volumes is the number of persistent volume claims whose data is backed up, with volume snapshots or file-system
+	// backup
+	Volumes int `json:"volumes"`
+	// VolumeSize is the sum of the capacities of the persistent volume claims whose data is uploaded to the object
+	// storage, with file-system backup or when the snapshot data is moved
+	VolumeSize resource.Quantity `json:"volumeSize"`
+	// Duration is the estimated duration of the backups. It is a rough estimate, based on typical Velero throughputs.
+	Duration metav1.Duration `json:"duration"`
+	// Backups reports the estimate of each backup
+	// +optional
+	Backups []BackupEstimate `json:"backups,omitempty"`
+}
+
+// BackupEstimate defines the estimated size and duration of a backup
+type BackupEstimate struct {
+	// Name is the name of the Backup CR
+	Name string `json:"name"`
+	// Resources is the number of resources matched by the backup
+	Resources int `json:"resources"`
+	// Volumes is the number of persistent volume claims whose data is backed up
+	Volumes int `json:"volumes"`
+	// VolumeSize is the sum of the capacities of the persistent volume claims whose data is uploaded to the object
+	// storage, with file-system backup or when the snapshot data is moved
+	VolumeSize resource.Quantity `json:"volumeSize"`
+	// Duration is the estimated duration of the backup
+	Duration metav1.Duration `json:"duration"`
+}
+
+// PrecacheStatus defines the progress of the image precaching
+type PrecacheStatus struct {
+	// Total is the number of images to precache
+	Total int `json:"total"`
+	// Pulled is the number of images pulled successfully
+	Pulled int `json:"pulled"`
+	// Failed is the number of images that failed to be pulled
+	Failed int `json:"failed"`
+	// BytesDownloaded is the number of bytes downloaded from the registries so far, the compressed size of the image
+	// layers added to the container storage since the precaching started. The layers already present, or shared with
+	// an image pulled earlier, are not downloaded again and are not counted.
+	BytesDownloaded int64 `json:"bytesDownloaded"`
+	// EstimatedCompletionTime is the estimated time the precaching will complete at, based on the time taken to
+	// process the images so far. It is not set once every image has been processed.
+	// +optional
+	EstimatedCompletionTime *metav1.Time `json:"estimatedCompletionTime,omitempty"`
+	// FailedImages lists the images that failed to be pulled, along with the error of their last pull attempt
+	// +optional
+	FailedImages []PrecacheFailedImage `json:"failedImages,omitempty"`
+}
+
+// PrecacheFailedImage defines an image that failed to be pulled
+type PrecacheFailedImage struct {
+	// Image is the pull-spec of the image
+	Image string `json:"image"`
+	// Error is the error of the last pull attempt
+	Error string `json:"error,omitempty"`
+}
+
+// SeedCompatibilityVerdict defines the type for the result of a seed image compatibility check
+type SeedCompatibilityVerdict string
+
+// SeedCompatibilityVerdicts defines the string values for the seed image compatibility check results
+var SeedCompatibilityVerdicts = struct {
+	Compatible   SeedCompatibilityVerdict
+	Incompatible SeedCompatibilityVerdict
+	Skipped      SeedCompatibilityVerdict
+}{
+	Compatible:   "Compatible",
+	Incompatible: "Incompatible",
+	Skipped:      "Skipped",
+}
+
+// SeedCompatibilityCheck defines the result of a seed image compatibility check
+type SeedCompatibilityCheck struct {
+	// Name of the check
+	Name string `json:"name"`
+	// SeedValue is the value read from the seed image
+	SeedValue string `json:"seedValue,omitempty"`
+	// ClusterValue is the value expected from the seed image by the cluster being upgraded
+	ClusterValue string `json:"clusterValue,omitempty"`
+	// Verdict is the result of the check
+	// +kubebuilder:validation:Enum=Compatible;Incompatible;Skipped
+	Verdict SeedCompatibilityVerdict `json:"verdict"`
+	// Message describes why the seed image is incompatible, or why the check was skipped
+	Message string `json:"message,omitempty"`
+}
+
+// AutoProgressStatus defines the observed state of the automatic stage progression
+type AutoProgressStatus struct {
+	// Halted indicates that the automatic progression was stopped due to a failure and requires manual intervention
+	Halted bool `json:"halted,omitempty"`
+	// Message describes the current state of the automatic progression
+	Message string `json:"message,omitempty"`
+}
+
+// PreflightStatus defines the results of a preflight run, which runs the Prep validations without side effects
+type PreflightStatus struct {
+	// Request is the value of the preflight annotation that triggered this run
+	Request string `json:"request,omitempty"`
+	// CompletionTime A timestamp indicating the preflight run completed
+	CompletionTime metav1.Time `json:"completionTime,omitempty"`
+	// Passed indicates whether all checks passed
+	Passed bool `json:"passed"`
+	// Checks lists the result of each check
+	Checks []PreflightCheck `json:"checks,omitempty"`
+}
+
+// PreflightCheck defines the result of a single preflight check
+type PreflightCheck struct {
+	// Name of the check
+	Name string `json:"name"`
+	// Passed indicates whether the check passed
+	Passed bool `json:"passed"`
+	// Message describes the failure, or any warning reported by a check that passed
+	Message string `json:"message,omitempty"`
 }
 
 type History struct {
@@ -25,6 +25,41 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutoProgress) DeepCopyInto(out *AutoProgress) {
+	*out = *in
+	if in.SoakDuration != nil {
+		in, out := &in.SoakDuration, &out.SoakDuration
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoProgress.
+func (in *AutoProgress) DeepCopy() *AutoProgress {
+	if in == nil {
+		return nil
+	}
+	out := new(AutoProgress)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutoProgressStatus) DeepCopyInto(out *AutoProgressStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoProgressStatus.
+func (in *AutoProgressStatus) DeepCopy() *AutoProgressStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AutoProgressStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AutoRollbackOnFailure) DeepCopyInto(out *AutoRollbackOnFailure) {
 	*out = *in
@@ -40,6 +75,72 @@ func (in *AutoRollbackOnFailure) DeepCopy() *AutoRollbackOnFailure {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupEstimate) DeepCopyInto(out *BackupEstimate) {
+	*out = *in
+	out.VolumeSize = in.VolumeSize.DeepCopy()
+	out.Duration = in.Duration
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupEstimate.
+func (in *BackupEstimate) DeepCopy() *BackupEstimate {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupEstimate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupEstimateStatus) DeepCopyInto(out *BackupEstimateStatus) {
+	*out = *in
+	out.VolumeSize = in.VolumeSize.DeepCopy()
+	out.Duration = in.Duration
+	if in.Backups != nil {
+		in, out := &in.Backups, &out.Backups
+		*out = make([]BackupEstimate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupEstimateStatus.
+func (in *BackupEstimateStatus) DeepCopy() *BackupEstimateStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupEstimateStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupRetention) DeepCopyInto(out *BackupRetention) {
+	*out = *in
+	if in.TTL != nil {
+		in, out := &in.TTL, &out.TTL
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Count != nil {
+		in, out := &in.Count, &out.Count
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupRetention.
+func (in *BackupRetention) DeepCopy() *BackupRetention {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupRetention)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ConfigMapRef) DeepCopyInto(out *ConfigMapRef) {
 	*out = *in
@@ -55,6 +156,22 @@ func (in *ConfigMapRef) DeepCopy() *ConfigMapRef {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExtraManifestsDiff) DeepCopyInto(out *ExtraManifestsDiff) {
+	*out = *in
+	out.ConfigMap = in.ConfigMap
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExtraManifestsDiff.
+func (in *ExtraManifestsDiff) DeepCopy() *ExtraManifestsDiff {
+	if in == nil {
+		return nil
+	}
+	out := new(ExtraManifestsDiff)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *History) DeepCopyInto(out *History) {
 	*out = *in
@@ -161,6 +278,46 @@ func (in *ImageBasedUpgradeSpec) DeepCopyInto(out *ImageBasedUpgradeSpec) {
 		*out = new(AutoRollbackOnFailure)
 		**out = **in
 	}
+	if in.Schedule != nil {
+		in, out := &in.Schedule, &out.Schedule
+		*out = new(Schedule)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AutoProgress != nil {
+		in, out := &in.AutoProgress, &out.AutoProgress
+		*out = new(AutoProgress)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Timeouts != nil {
+		in, out := &in.Timeouts, &out.Timeouts
+		*out = new(Timeouts)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LocalBackup != nil {
+		in, out := &in.LocalBackup, &out.LocalBackup
+		*out = new(LocalBackup)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BackupRetention != nil {
+		in, out := &in.BackupRetention, &out.BackupRetention
+		*out = new(BackupRetention)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Quiesce != nil {
+		in, out := &in.Quiesce, &out.Quiesce
+		*out = new(Quiesce)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PostUpgradeValidation != nil {
+		in, out := &in.PostUpgradeValidation, &out.PostUpgradeValidation
+		*out = make([]ConfigMapRef, len(*in))
+		copy(*out, *in)
+	}
+	if in.InventoryCheck != nil {
+		in, out := &in.InventoryCheck, &out.InventoryCheck
+		*out = new(InventoryCheck)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageBasedUpgradeSpec.
@@ -200,6 +357,55 @@ func (in *ImageBasedUpgradeStatus) DeepCopyInto(out *ImageBasedUpgradeStatus) {
 			}
 		}
 	}
+	if in.AutoProgress != nil {
+		in, out := &in.AutoProgress, &out.AutoProgress
+		*out = new(AutoProgressStatus)
+		**out = **in
+	}
+	if in.Preflight != nil {
+		in, out := &in.Preflight, &out.Preflight
+		*out = new(PreflightStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SeedCompatibility != nil {
+		in, out := &in.SeedCompatibility, &out.SeedCompatibility
+		*out = make([]SeedCompatibilityCheck, len(*in))
+		copy(*out, *in)
+	}
+	if in.Precache != nil {
+		in, out := &in.Precache, &out.Precache
+		*out = new(PrecacheStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BackupEstimate != nil {
+		in, out := &in.BackupEstimate, &out.BackupEstimate
+		*out = new(BackupEstimateStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ToleratedRestoreErrors != nil {
+		in, out := &in.ToleratedRestoreErrors, &out.ToleratedRestoreErrors
+		*out = make([]ToleratedRestoreErrors, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.InventoryReport != nil {
+		in, out := &in.InventoryReport, &out.InventoryReport
+		*out = new(InventoryReport)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SelectedPolicies != nil {
+		in, out := &in.SelectedPolicies, &out.SelectedPolicies
+		*out = make([]SelectedPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ExtraManifestsDiff != nil {
+		in, out := &in.ExtraManifestsDiff, &out.ExtraManifestsDiff
+		*out = new(ExtraManifestsDiff)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageBasedUpgradeStatus.
@@ -212,6 +418,98 @@ func (in *ImageBasedUpgradeStatus) DeepCopy() *ImageBasedUpgradeStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InventoryCheck) DeepCopyInto(out *InventoryCheck) {
+	*out = *in
+	if in.MustNotRegress != nil {
+		in, out := &in.MustNotRegress, &out.MustNotRegress
+		*out = make([]InventoryCategory, len(*in))
+		copy(*out, *in)
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InventoryCheck.
+func (in *InventoryCheck) DeepCopy() *InventoryCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(InventoryCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InventoryReport) DeepCopyInto(out *InventoryReport) {
+	*out = *in
+	out.ConfigMap = in.ConfigMap
+	if in.Regressions != nil {
+		in, out := &in.Regressions, &out.Regressions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RegressedSince != nil {
+		in, out := &in.RegressedSince, &out.RegressedSince
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InventoryReport.
+func (in *InventoryReport) DeepCopy() *InventoryReport {
+	if in == nil {
+		return nil
+	}
+	out := new(InventoryReport)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LocalBackup) DeepCopyInto(out *LocalBackup) {
+	*out = *in
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LabelSelector != nil {
+		in, out := &in.LabelSelector, &out.LabelSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LocalBackup.
+func (in *LocalBackup) DeepCopy() *LocalBackup {
+	if in == nil {
+		return nil
+	}
+	out := new(LocalBackup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceWindow) DeepCopyInto(out *MaintenanceWindow) {
+	*out = *in
+	in.Start.DeepCopyInto(&out.Start)
+	in.End.DeepCopyInto(&out.End)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceWindow.
+func (in *MaintenanceWindow) DeepCopy() *MaintenanceWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Phase) DeepCopyInto(out *Phase) {
 	*out = *in
@@ -229,6 +527,96 @@ func (in *Phase) DeepCopy() *Phase {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrecacheFailedImage) DeepCopyInto(out *PrecacheFailedImage) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrecacheFailedImage.
+func (in *PrecacheFailedImage) DeepCopy() *PrecacheFailedImage {
+	if in == nil {
+		return nil
+	}
+	out := new(PrecacheFailedImage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrecacheStatus) DeepCopyInto(out *PrecacheStatus) {
+	*out = *in
+	if in.EstimatedCompletionTime != nil {
+		in, out := &in.EstimatedCompletionTime, &out.EstimatedCompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.FailedImages != nil {
+		in, out := &in.FailedImages, &out.FailedImages
+		*out = make([]PrecacheFailedImage, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrecacheStatus.
+func (in *PrecacheStatus) DeepCopy() *PrecacheStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PrecacheStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PreflightCheck) DeepCopyInto(out *PreflightCheck) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PreflightCheck.
+func (in *PreflightCheck) DeepCopy() *PreflightCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(PreflightCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PreflightStatus) DeepCopyInto(out *PreflightStatus) {
+	*out = *in
+	in.CompletionTime.DeepCopyInto(&out.CompletionTime)
+	if in.Checks != nil {
+		in, out := &in.Checks, &out.Checks
+		*out = make([]PreflightCheck, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PreflightStatus.
+func (in *PreflightStatus) DeepCopy() *PreflightStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PreflightStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PublicKeysRef) DeepCopyInto(out *PublicKeysRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PublicKeysRef.
+func (in *PublicKeysRef) DeepCopy() *PublicKeysRef {
+	if in == nil {
+		return nil
+	}
+	out := new(PublicKeysRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PullSecretRef) DeepCopyInto(out *PullSecretRef) {
 	*out = *in
@@ -244,6 +632,171 @@ func (in *PullSecretRef) DeepCopy() *PullSecretRef {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Quiesce) DeepCopyInto(out *Quiesce) {
+	*out = *in
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]QuiesceStep, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Quiesce.
+func (in *Quiesce) DeepCopy() *Quiesce {
+	if in == nil {
+		return nil
+	}
+	out := new(Quiesce)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QuiesceJob) DeepCopyInto(out *QuiesceJob) {
+	*out = *in
+	if in.Command != nil {
+		in, out := &in.Command, &out.Command
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QuiesceJob.
+func (in *QuiesceJob) DeepCopy() *QuiesceJob {
+	if in == nil {
+		return nil
+	}
+	out := new(QuiesceJob)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QuiesceScaleDown) DeepCopyInto(out *QuiesceScaleDown) {
+	*out = *in
+	if in.Deployments != nil {
+		in, out := &in.Deployments, &out.Deployments
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.StatefulSets != nil {
+		in, out := &in.StatefulSets, &out.StatefulSets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LabelSelector != nil {
+		in, out := &in.LabelSelector, &out.LabelSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QuiesceScaleDown.
+func (in *QuiesceScaleDown) DeepCopy() *QuiesceScaleDown {
+	if in == nil {
+		return nil
+	}
+	out := new(QuiesceScaleDown)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QuiesceStep) DeepCopyInto(out *QuiesceStep) {
+	*out = *in
+	if in.ScaleDown != nil {
+		in, out := &in.ScaleDown, &out.ScaleDown
+		*out = new(QuiesceScaleDown)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Job != nil {
+		in, out := &in.Job, &out.Job
+		*out = new(QuiesceJob)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QuiesceStep.
+func (in *QuiesceStep) DeepCopy() *QuiesceStep {
+	if in == nil {
+		return nil
+	}
+	out := new(QuiesceStep)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RecurringMaintenanceWindow) DeepCopyInto(out *RecurringMaintenanceWindow) {
+	*out = *in
+	out.Duration = in.Duration
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RecurringMaintenanceWindow.
+func (in *RecurringMaintenanceWindow) DeepCopy() *RecurringMaintenanceWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(RecurringMaintenanceWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Schedule) DeepCopyInto(out *Schedule) {
+	*out = *in
+	if in.Stages != nil {
+		in, out := &in.Stages, &out.Stages
+		*out = make([]ScheduledStage, len(*in))
+		copy(*out, *in)
+	}
+	if in.Windows != nil {
+		in, out := &in.Windows, &out.Windows
+		*out = make([]MaintenanceWindow, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.RecurringWindows != nil {
+		in, out := &in.RecurringWindows, &out.RecurringWindows
+		*out = make([]RecurringMaintenanceWindow, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Schedule.
+func (in *Schedule) DeepCopy() *Schedule {
+	if in == nil {
+		return nil
+	}
+	out := new(Schedule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SeedCompatibilityCheck) DeepCopyInto(out *SeedCompatibilityCheck) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SeedCompatibilityCheck.
+func (in *SeedCompatibilityCheck) DeepCopy() *SeedCompatibilityCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(SeedCompatibilityCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SeedImageRef) DeepCopyInto(out *SeedImageRef) {
 	*out = *in
@@ -252,6 +805,11 @@ func (in *SeedImageRef) DeepCopyInto(out *SeedImageRef) {
 		*out = new(PullSecretRef)
 		**out = **in
 	}
+	if in.Verification != nil {
+		in, out := &in.Verification, &out.Verification
+		*out = new(SeedImageVerification)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SeedImageRef.
@@ -263,3 +821,104 @@ func (in *SeedImageRef) DeepCopy() *SeedImageRef {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SeedImageVerification) DeepCopyInto(out *SeedImageVerification) {
+	*out = *in
+	out.PublicKeysRef = in.PublicKeysRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SeedImageVerification.
+func (in *SeedImageVerification) DeepCopy() *SeedImageVerification {
+	if in == nil {
+		return nil
+	}
+	out := new(SeedImageVerification)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SelectedPolicy) DeepCopyInto(out *SelectedPolicy) {
+	*out = *in
+	if in.TargetOcpVersions != nil {
+		in, out := &in.TargetOcpVersions, &out.TargetOcpVersions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SelectedPolicy.
+func (in *SelectedPolicy) DeepCopy() *SelectedPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(SelectedPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Timeouts) DeepCopyInto(out *Timeouts) {
+	*out = *in
+	if in.Prep != nil {
+		in, out := &in.Prep, &out.Prep
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.StaterootSetup != nil {
+		in, out := &in.StaterootSetup, &out.StaterootSetup
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Precache != nil {
+		in, out := &in.Precache, &out.Precache
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Upgrade != nil {
+		in, out := &in.Upgrade, &out.Upgrade
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.PrePivot != nil {
+		in, out := &in.PrePivot, &out.PrePivot
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.PostPivot != nil {
+		in, out := &in.PostPivot, &out.PostPivot
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Timeouts.
+func (in *Timeouts) DeepCopy() *Timeouts {
+	if in == nil {
+		return nil
+	}
+	out := new(Timeouts)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ToleratedRestoreErrors) DeepCopyInto(out *ToleratedRestoreErrors) {
+	*out = *in
+	if in.Errors != nil {
+		in, out := &in.Errors, &out.Errors
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ToleratedRestoreErrors.
+func (in *ToleratedRestoreErrors) DeepCopy() *ToleratedRestoreErrors {
+	if in == nil {
+		return nil
+	}
+	out := new(ToleratedRestoreErrors)
+	in.DeepCopyInto(out)
+	return out
+}
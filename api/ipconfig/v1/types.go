@@ -190,6 +190,12 @@ type IPConfigSpec struct {
 	// +optional
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Auto Rollback On Failure",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:fieldGroup:Auto Rollback On Failure"}
 	AutoRollbackOnFailure *AutoRollbackOnFailure `json:"autoRollbackOnFailure,omitempty"`
+
+	// Timeouts defines the maximum durations of the Config stage and of its phases. A stage that exceeds one of
+	// them fails with the TimedOut reason.
+	// +optional
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Timeouts"
+	Timeouts *Timeouts `json:"timeouts,omitempty"`
 }
 
 // AutoRollbackOnFailure defines automatic rollback settings if the IP configuration does not
@@ -203,6 +209,22 @@ type AutoRollbackOnFailure struct {
 	InitMonitorTimeoutSeconds int `json:"initMonitorTimeoutSeconds,omitempty"`
 }
 
+// Timeouts defines the maximum durations of the Config stage and of its phases, e.g. "45m". The durations are
+// measured from the start times recorded in status.history. A timeout that is not defined does not expire.
+type Timeouts struct {
+	// Config defines the maximum duration of the Config stage
+	// +optional
+	Config *metav1.Duration `json:"config,omitempty"`
+	// PrePivot defines the maximum duration of the Config steps run before the reboot, such as waiting for the
+	// cluster to stabilize
+	// +optional
+	PrePivot *metav1.Duration `json:"prePivot,omitempty"`
+	// PostPivot defines the maximum duration of the Config steps run after the reboot, such as waiting for the
+	// cluster to stabilize and for the node IPs to match the spec
+	// +optional
+	PostPivot *metav1.Duration `json:"postPivot,omitempty"`
+}
+
 // IPConfigStatus defines the observed state of IPConfig
 type IPConfigStatus struct {
 	// +operator-sdk:csv:customresourcedefinitions:type=status,displayName="Observed Generation"
@@ -122,6 +122,11 @@ func (in *IPConfigSpec) DeepCopyInto(out *IPConfigSpec) {
 		*out = new(AutoRollbackOnFailure)
 		**out = **in
 	}
+	if in.Timeouts != nil {
+		in, out := &in.Timeouts, &out.Timeouts
+		*out = new(Timeouts)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IPConfigSpec.
@@ -292,3 +297,33 @@ func (in *IPv6Status) DeepCopy() *IPv6Status {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Timeouts) DeepCopyInto(out *Timeouts) {
+	*out = *in
+	if in.Config != nil {
+		in, out := &in.Config, &out.Config
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.PrePivot != nil {
+		in, out := &in.PrePivot, &out.PrePivot
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.PostPivot != nil {
+		in, out := &in.PostPivot, &out.PostPivot
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Timeouts.
+func (in *Timeouts) DeepCopy() *Timeouts {
+	if in == nil {
+		return nil
+	}
+	out := new(Timeouts)
+	in.DeepCopyInto(out)
+	return out
+}
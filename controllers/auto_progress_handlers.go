@@ -0,0 +1,131 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	ibuv1 "github.com/openshift-kni/lifecycle-agent/api/imagebasedupgrade/v1"
+	"github.com/openshift-kni/lifecycle-agent/controllers/utils"
+	"github.com/openshift-kni/lifecycle-agent/internal/common"
+	"github.com/samber/lo"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// getAutoProgressNextStage returns the stage that spec.autoProgress moves to from the current state, or an
+// empty stage if there is nothing to progress to
+func getAutoProgressNextStage(ibu *ibuv1.ImageBasedUpgrade) ibuv1.ImageBasedUpgradeStage {
+	if ibu.Spec.AutoProgress == nil || utils.GetInProgressStage(ibu) != "" {
+		return ""
+	}
+
+	switch ibu.Spec.Stage {
+	case ibuv1.Stages.Prep:
+		if utils.IsStageCompleted(ibu, ibuv1.Stages.Prep) {
+			return ibuv1.Stages.Upgrade
+		}
+	case ibuv1.Stages.Upgrade:
+		if utils.IsStageCompleted(ibu, ibuv1.Stages.Upgrade) {
+			return ibuv1.Stages.Idle
+		}
+	}
+	return ""
+}
+
+// isAutoRollbackOnSoakFailureEnabled checks whether a failure during the soak period should roll back the upgrade
+func isAutoRollbackOnSoakFailureEnabled(ibu *ibuv1.ImageBasedUpgrade) bool {
+	if ibu.Spec.AutoRollbackOnFailure == nil {
+		return false
+	}
+	if val, exists := ibu.GetAnnotations()[common.AutoRollbackOnFailureUpgradeCompletionAnnotation]; exists {
+		return val != common.AutoRollbackDisableValue
+	}
+	return true
+}
+
+// handleAutoProgress moves spec.stage to the next stage once the current stage has completed and the cluster is
+// healthy. The new stage is only requested if it is a valid next stage, and the transition itself is then
+// validated by the next reconcile as if it was requested by the user.
+//
+// After the Upgrade stage completes, the cluster must stay healthy for the soak period before it is finalized.
+// A failure during the soak period halts the automatic progression and, if enabled, requests a rollback.
+func (r *ImageBasedUpgradeReconciler) handleAutoProgress(ctx context.Context, ibu *ibuv1.ImageBasedUpgrade, isAfterPivot bool) (ctrl.Result, error) {
+	if ibu.Status.AutoProgress != nil && ibu.Status.AutoProgress.Halted {
+		return doNotRequeue(), nil
+	}
+
+	nextStage := getAutoProgressNextStage(ibu)
+	if nextStage == "" || !lo.Contains(getValidNextStageList(ibu, isAfterPivot), nextStage) {
+		return doNotRequeue(), nil
+	}
+
+	if err := CheckHealth(ctx, r.NoncachedClient, r.Log); err != nil {
+		if nextStage != ibuv1.Stages.Idle {
+			msg := fmt.Sprintf("Waiting for system to stabilize before moving to %s: %s", nextStage, err.Error())
+			return r.updateAutoProgressStatus(ctx, ibu, false, msg, requeueWithHealthCheckInterval())
+		}
+
+		// The upgraded cluster is in its soak period
+		msg := fmt.Sprintf("Health check failed during the soak period: %s", err.Error())
+		if !isAutoRollbackOnSoakFailureEnabled(ibu) || !lo.Contains(getValidNextStageList(ibu, isAfterPivot), ibuv1.Stages.Rollback) {
+			r.Log.Info("Halting automatic progression", "reason", msg)
+			return r.updateAutoProgressStatus(ctx, ibu, true, msg, doNotRequeue())
+		}
+
+		r.Log.Info("Automatically rolling back due to failure during the soak period", "reason", msg)
+		if _, err := r.updateAutoProgressStatus(ctx, ibu, true, fmt.Sprintf("Rollback requested. %s", msg), doNotRequeue()); err != nil {
+			return requeueWithError(err)
+		}
+		return r.requestStage(ctx, ibu, ibuv1.Stages.Rollback)
+	}
+
+	if nextStage == ibuv1.Stages.Idle && ibu.Spec.AutoProgress.SoakDuration != nil {
+		completedCondition := utils.GetCompletedCondition(ibu, ibuv1.Stages.Upgrade)
+		soakEnd := completedCondition.LastTransitionTime.Add(ibu.Spec.AutoProgress.SoakDuration.Duration)
+		if remaining := time.Until(soakEnd); remaining > 0 {
+			msg := fmt.Sprintf("Finalizing after the soak period ends at %s", soakEnd.UTC().Format(time.RFC3339))
+			// Keep checking the cluster health while soaking
+			return r.updateAutoProgressStatus(ctx, ibu, false, msg, requeueWithCustomInterval(min(remaining, time.Minute)))
+		}
+	}
+
+	if _, err := r.updateAutoProgressStatus(ctx, ibu, false, fmt.Sprintf("Moved to %s", nextStage), doNotRequeue()); err != nil {
+		return requeueWithError(err)
+	}
+	return r.requestStage(ctx, ibu, nextStage)
+}
+
+// requestStage updates spec.stage of the IBU CR to the given stage
+func (r *ImageBasedUpgradeReconciler) requestStage(ctx context.Context, ibu *ibuv1.ImageBasedUpgrade, stage ibuv1.ImageBasedUpgradeStage) (ctrl.Result, error) {
+	r.Log.Info("Automatically progressing to the next stage", "from", ibu.Spec.Stage, "to", stage)
+	ibu.Spec.Stage = stage
+	if err := r.Client.Update(ctx, ibu); err != nil {
+		return requeueWithError(fmt.Errorf("failed to update IBU CR stage to %s: %w", stage, err))
+	}
+	return requeueImmediately(), nil
+}
+
+// updateAutoProgressStatus sets the automatic progression status and returns the given result
+func (r *ImageBasedUpgradeReconciler) updateAutoProgressStatus(ctx context.Context, ibu *ibuv1.ImageBasedUpgrade, halted bool, msg string, result ctrl.Result) (ctrl.Result, error) {
+	ibu.Status.AutoProgress = &ibuv1.AutoProgressStatus{Halted: halted, Message: msg}
+	if err := utils.UpdateIBUStatus(ctx, r.Client, ibu); err != nil {
+		return requeueWithError(err)
+	}
+	return result, nil
+}
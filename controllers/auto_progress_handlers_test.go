@@ -0,0 +1,188 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	ibuv1 "github.com/openshift-kni/lifecycle-agent/api/imagebasedupgrade/v1"
+	"github.com/openshift-kni/lifecycle-agent/controllers/utils"
+	"github.com/openshift-kni/lifecycle-agent/internal/common"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func completedConditions(stage ibuv1.ImageBasedUpgradeStage, completedAt time.Time) []metav1.Condition {
+	return []metav1.Condition{
+		{Type: string(utils.ConditionTypes.Idle), Status: metav1.ConditionFalse, Reason: string(utils.ConditionReasons.InProgress)},
+		{Type: string(utils.ConditionTypes.PrepInProgress), Status: metav1.ConditionFalse, Reason: string(utils.ConditionReasons.Completed)},
+		{Type: string(utils.ConditionTypes.PrepCompleted), Status: metav1.ConditionTrue, Reason: string(utils.ConditionReasons.Completed)},
+		{Type: string(utils.GetInProgressConditionType(stage)), Status: metav1.ConditionFalse, Reason: string(utils.ConditionReasons.Completed)},
+		{Type: string(utils.GetCompletedConditionType(stage)), Status: metav1.ConditionTrue, Reason: string(utils.ConditionReasons.Completed),
+			LastTransitionTime: metav1.NewTime(completedAt)},
+	}
+}
+
+func TestImageBasedUpgradeReconciler_handleAutoProgress(t *testing.T) {
+	healthy := func(ctx context.Context, c client.Reader, l logr.Logger) error { return nil }
+	unhealthy := func(ctx context.Context, c client.Reader, l logr.Logger) error { return fmt.Errorf("node not ready") }
+
+	testcases := []struct {
+		name            string
+		ibu             *ibuv1.ImageBasedUpgrade
+		isAfterPivot    bool
+		healthCheck     func(ctx context.Context, c client.Reader, l logr.Logger) error
+		expectedStage   ibuv1.ImageBasedUpgradeStage
+		expectedHalted  bool
+		expectedRequeue bool
+	}{
+		{
+			name: "prep completed moves to upgrade",
+			ibu: &ibuv1.ImageBasedUpgrade{
+				Spec:   ibuv1.ImageBasedUpgradeSpec{Stage: ibuv1.Stages.Prep, AutoProgress: &ibuv1.AutoProgress{}},
+				Status: ibuv1.ImageBasedUpgradeStatus{Conditions: completedConditions(ibuv1.Stages.Prep, time.Now())},
+			},
+			healthCheck:     healthy,
+			expectedStage:   ibuv1.Stages.Upgrade,
+			expectedRequeue: true,
+		},
+		{
+			name: "prep completed waits for health checks",
+			ibu: &ibuv1.ImageBasedUpgrade{
+				Spec:   ibuv1.ImageBasedUpgradeSpec{Stage: ibuv1.Stages.Prep, AutoProgress: &ibuv1.AutoProgress{}},
+				Status: ibuv1.ImageBasedUpgradeStatus{Conditions: completedConditions(ibuv1.Stages.Prep, time.Now())},
+			},
+			healthCheck:     unhealthy,
+			expectedStage:   ibuv1.Stages.Prep,
+			expectedRequeue: true,
+		},
+		{
+			name: "upgrade completed moves to idle without soak period",
+			ibu: &ibuv1.ImageBasedUpgrade{
+				Spec:   ibuv1.ImageBasedUpgradeSpec{Stage: ibuv1.Stages.Upgrade, AutoProgress: &ibuv1.AutoProgress{}},
+				Status: ibuv1.ImageBasedUpgradeStatus{Conditions: completedConditions(ibuv1.Stages.Upgrade, time.Now())},
+			},
+			isAfterPivot:    true,
+			healthCheck:     healthy,
+			expectedStage:   ibuv1.Stages.Idle,
+			expectedRequeue: true,
+		},
+		{
+			name: "upgrade completed soaks before moving to idle",
+			ibu: &ibuv1.ImageBasedUpgrade{
+				Spec: ibuv1.ImageBasedUpgradeSpec{
+					Stage:        ibuv1.Stages.Upgrade,
+					AutoProgress: &ibuv1.AutoProgress{SoakDuration: &metav1.Duration{Duration: time.Hour}},
+				},
+				Status: ibuv1.ImageBasedUpgradeStatus{Conditions: completedConditions(ibuv1.Stages.Upgrade, time.Now())},
+			},
+			isAfterPivot:    true,
+			healthCheck:     healthy,
+			expectedStage:   ibuv1.Stages.Upgrade,
+			expectedRequeue: true,
+		},
+		{
+			name: "upgrade completed moves to idle after soak period",
+			ibu: &ibuv1.ImageBasedUpgrade{
+				Spec: ibuv1.ImageBasedUpgradeSpec{
+					Stage:        ibuv1.Stages.Upgrade,
+					AutoProgress: &ibuv1.AutoProgress{SoakDuration: &metav1.Duration{Duration: time.Hour}},
+				},
+				Status: ibuv1.ImageBasedUpgradeStatus{Conditions: completedConditions(ibuv1.Stages.Upgrade, time.Now().Add(-2*time.Hour))},
+			},
+			isAfterPivot:    true,
+			healthCheck:     healthy,
+			expectedStage:   ibuv1.Stages.Idle,
+			expectedRequeue: true,
+		},
+		{
+			name: "failure during soak period rolls back",
+			ibu: &ibuv1.ImageBasedUpgrade{
+				Spec: ibuv1.ImageBasedUpgradeSpec{
+					Stage:                 ibuv1.Stages.Upgrade,
+					AutoProgress:          &ibuv1.AutoProgress{SoakDuration: &metav1.Duration{Duration: time.Hour}},
+					AutoRollbackOnFailure: &ibuv1.AutoRollbackOnFailure{},
+				},
+				Status: ibuv1.ImageBasedUpgradeStatus{Conditions: completedConditions(ibuv1.Stages.Upgrade, time.Now())},
+			},
+			isAfterPivot:    true,
+			healthCheck:     unhealthy,
+			expectedStage:   ibuv1.Stages.Rollback,
+			expectedHalted:  true,
+			expectedRequeue: true,
+		},
+		{
+			name: "failure during soak period halts when auto rollback is disabled",
+			ibu: &ibuv1.ImageBasedUpgrade{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{common.AutoRollbackOnFailureUpgradeCompletionAnnotation: common.AutoRollbackDisableValue},
+				},
+				Spec: ibuv1.ImageBasedUpgradeSpec{
+					Stage:                 ibuv1.Stages.Upgrade,
+					AutoProgress:          &ibuv1.AutoProgress{SoakDuration: &metav1.Duration{Duration: time.Hour}},
+					AutoRollbackOnFailure: &ibuv1.AutoRollbackOnFailure{},
+				},
+				Status: ibuv1.ImageBasedUpgradeStatus{Conditions: completedConditions(ibuv1.Stages.Upgrade, time.Now())},
+			},
+			isAfterPivot:   true,
+			healthCheck:    unhealthy,
+			expectedStage:  ibuv1.Stages.Upgrade,
+			expectedHalted: true,
+		},
+		{
+			name: "halted progression does nothing",
+			ibu: &ibuv1.ImageBasedUpgrade{
+				Spec: ibuv1.ImageBasedUpgradeSpec{Stage: ibuv1.Stages.Upgrade, AutoProgress: &ibuv1.AutoProgress{}},
+				Status: ibuv1.ImageBasedUpgradeStatus{
+					Conditions:   completedConditions(ibuv1.Stages.Upgrade, time.Now()),
+					AutoProgress: &ibuv1.AutoProgressStatus{Halted: true},
+				},
+			},
+			isAfterPivot:   true,
+			healthCheck:    healthy,
+			expectedStage:  ibuv1.Stages.Upgrade,
+			expectedHalted: true,
+		},
+		{
+			name: "stage in progress does nothing",
+			ibu: &ibuv1.ImageBasedUpgrade{
+				Spec: ibuv1.ImageBasedUpgradeSpec{Stage: ibuv1.Stages.Prep, AutoProgress: &ibuv1.AutoProgress{}},
+				Status: ibuv1.ImageBasedUpgradeStatus{Conditions: []metav1.Condition{
+					{Type: string(utils.ConditionTypes.Idle), Status: metav1.ConditionFalse, Reason: string(utils.ConditionReasons.InProgress)},
+					{Type: string(utils.ConditionTypes.PrepInProgress), Status: metav1.ConditionTrue, Reason: string(utils.ConditionReasons.InProgress)},
+				}},
+			},
+			healthCheck:   healthy,
+			expectedStage: ibuv1.Stages.Prep,
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			oldHC := CheckHealth
+			defer func() { CheckHealth = oldHC }()
+			CheckHealth = tc.healthCheck
+
+			tc.ibu.Name = utils.IBUName
+			fakeClient, err := getFakeClientFromObjects(tc.ibu)
+			assert.NoError(t, err)
+
+			r := &ImageBasedUpgradeReconciler{
+				Client:          fakeClient,
+				NoncachedClient: fakeClient,
+				Log:             logr.Discard(),
+			}
+			result, err := r.handleAutoProgress(context.TODO(), tc.ibu, tc.isAfterPivot)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedRequeue, result.RequeueAfter > 0)
+
+			ibu := &ibuv1.ImageBasedUpgrade{}
+			assert.NoError(t, fakeClient.Get(context.TODO(), types.NamespacedName{Name: utils.IBUName}, ibu))
+			assert.Equal(t, tc.expectedStage, ibu.Spec.Stage)
+			assert.Equal(t, tc.expectedHalted, ibu.Status.AutoProgress != nil && ibu.Status.AutoProgress.Halted)
+		})
+	}
+}
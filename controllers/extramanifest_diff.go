@@ -0,0 +1,62 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	ibuv1 "github.com/openshift-kni/lifecycle-agent/api/imagebasedupgrade/v1"
+	"github.com/openshift-kni/lifecycle-agent/internal/common"
+	"github.com/openshift-kni/lifecycle-agent/internal/extramanifest"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	extraManifestsDiffConfigMapName = "lca-extra-manifests-diff"
+	extraManifestsDiffKey           = "diff.yaml"
+)
+
+// reportExtraManifestsDiff saves the diffs of the extra manifests and of the manifests extracted from policies in the
+// extra manifests diff configmap, and summarizes them in the IBU status
+func (r *ImageBasedUpgradeReconciler) reportExtraManifestsDiff(ctx context.Context, ibu *ibuv1.ImageBasedUpgrade, diffs []extramanifest.ManifestDiff) error {
+	ibu.Status.ExtraManifestsDiff = nil
+	if len(diffs) == 0 {
+		return nil
+	}
+
+	data, err := yaml.Marshal(diffs)
+	if err != nil {
+		return fmt.Errorf("failed to marshal extra manifests diff: %w", err)
+	}
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: extraManifestsDiffConfigMapName, Namespace: common.LcaNamespace},
+		Data:       map[string]string{extraManifestsDiffKey: string(data)},
+	}
+	if err := createOrUpdateConfigMap(ctx, r.Client, cm); err != nil {
+		return fmt.Errorf("failed to save extra manifests diff: %w", err)
+	}
+
+	summary := extramanifest.SummarizeManifestDiffs(diffs)
+	summary.ConfigMap = ibuv1.ConfigMapRef{Name: cm.Name, Namespace: cm.Namespace}
+	ibu.Status.ExtraManifestsDiff = &summary
+	r.Log.Info("Extra manifests diff reported", "created", summary.Created, "updated", summary.Updated,
+		"deleted", summary.Deleted, "unchanged", summary.Unchanged, "unknown", summary.Unknown)
+	return nil
+}
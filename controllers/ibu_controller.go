@@ -30,6 +30,7 @@ import (
 	"github.com/openshift-kni/lifecycle-agent/internal/backuprestore"
 	"github.com/openshift-kni/lifecycle-agent/internal/extramanifest"
 	"github.com/openshift-kni/lifecycle-agent/internal/imagemgmt"
+	"github.com/openshift-kni/lifecycle-agent/internal/metrics"
 	"github.com/openshift-kni/lifecycle-agent/internal/reboot"
 	kbatch "k8s.io/api/batch/v1"
 
@@ -107,7 +108,6 @@ func requeueWithMediumInterval() ctrl.Result {
 	return requeueWithCustomInterval(1 * time.Minute)
 }
 
-//nolint:unused
 func requeueWithLongInterval() ctrl.Result {
 	return requeueWithCustomInterval(5 * time.Minute)
 }
@@ -191,6 +191,11 @@ func (r *ImageBasedUpgradeReconciler) Reconcile(ctx context.Context, req ctrl.Re
 	ibu.Status.ValidNextStages = getValidNextStageList(ibu, isAfterPivot)
 
 	if isTransitionRequested(ibu) {
+		nextReconcile, err = r.gateStageBySchedule(ctx, ibu, isAfterPivot)
+		if err != nil || nextReconcile.RequeueAfter > 0 {
+			return
+		}
+
 		if validateStageTransition(ibu, isAfterPivot) {
 			// The transition has occurred, regenerate the list of valid next stages
 			ibu.Status.ValidNextStages = getValidNextStageList(ibu, isAfterPivot)
@@ -220,6 +225,18 @@ func (r *ImageBasedUpgradeReconciler) Reconcile(ctx context.Context, req ctrl.Re
 	// Update status
 	if err = utils.UpdateIBUStatus(ctx, r.Client, ibu); err != nil {
 		r.Log.Error(err, "failed to update IBU CR status")
+		return
+	}
+
+	if nextReconcile.IsZero() && ibu.Spec.AutoProgress != nil {
+		nextReconcile, err = r.handleAutoProgress(ctx, ibu, isAfterPivot)
+		if err != nil {
+			return
+		}
+	}
+
+	if nextReconcile.IsZero() && isPreflightRequested(ibu) {
+		nextReconcile, err = r.handlePreflight(ctx, ibu)
 	}
 
 	return
@@ -281,6 +298,56 @@ func (r *ImageBasedUpgradeReconciler) gateIBUByIPConfig(
 	return requeueWithShortInterval(), nil
 }
 
+// gateStageBySchedule holds a valid transition to a stage gated by spec.schedule until a maintenance window opens.
+//
+// While the stage is held, its in-progress condition is set to Scheduled and the reconcile is requeued for when
+// the next window opens. Once a window is open, the transition proceeds as usual.
+func (r *ImageBasedUpgradeReconciler) gateStageBySchedule(
+	ctx context.Context,
+	ibu *ibuv1.ImageBasedUpgrade,
+	isAfterPivot bool,
+) (ctrl.Result, error) {
+	if !lo.Contains(getValidNextStageList(ibu, isAfterPivot), ibu.Spec.Stage) {
+		return doNotRequeue(), nil
+	}
+
+	result, msg := checkMaintenanceWindow(ibu, ibu.Spec.Stage, time.Now())
+	if result.IsZero() {
+		return doNotRequeue(), nil
+	}
+
+	r.Log.Info("Stage transition held by schedule", "stage", ibu.Spec.Stage, "reason", msg)
+	utils.SetIBUStatusScheduled(ibu, msg)
+	if err := utils.UpdateIBUStatus(ctx, r.Client, ibu); err != nil {
+		return requeueWithError(err)
+	}
+
+	return result, nil
+}
+
+// checkMaintenanceWindow checks whether the stage may run at the given time according to spec.schedule.
+// It returns a zero result if the stage is not gated or a maintenance window is open. Otherwise, it returns
+// the requeue result for when the next window opens, along with a message describing the wait.
+func checkMaintenanceWindow(ibu *ibuv1.ImageBasedUpgrade, stage ibuv1.ImageBasedUpgradeStage, now time.Time) (ctrl.Result, string) {
+	if !utils.IsStageScheduled(ibu, stage) {
+		return doNotRequeue(), ""
+	}
+
+	open, nextOpen, err := utils.GetMaintenanceWindowState(ibu.Spec.Schedule, now)
+	switch {
+	case err != nil:
+		// can only be fixed by the user updating spec.schedule, which triggers a new reconcile
+		return requeueWithLongInterval(), fmt.Sprintf("Waiting for a valid schedule: %s", err.Error())
+	case open:
+		return doNotRequeue(), ""
+	case nextOpen.IsZero():
+		return requeueWithLongInterval(), "Waiting for a maintenance window: no upcoming window in spec.schedule"
+	}
+
+	return requeueWithCustomInterval(nextOpen.Sub(now)),
+		fmt.Sprintf("Waiting for the maintenance window opening at %s", nextOpen.Format(time.RFC3339))
+}
+
 func getValidNextStageList(ibu *ibuv1.ImageBasedUpgrade, isAfterPivot bool) []ibuv1.ImageBasedUpgradeStage {
 	inProgressStage := utils.GetInProgressStage(ibu)
 	if inProgressStage == ibuv1.Stages.Idle || inProgressStage == ibuv1.Stages.Rollback || utils.IsStageFailed(ibu, ibuv1.Stages.Rollback) {
@@ -416,6 +483,8 @@ func validateStageTransition(ibu *ibuv1.ImageBasedUpgrade, isAfterPivot bool) bo
 
 		switch ibu.Spec.Stage {
 		case ibuv1.Stages.Prep:
+			// A new upgrade is starting, clear any state left by the automatic progression of a previous one
+			ibu.Status.AutoProgress = nil
 			utils.SetIdleStatusInProgress(ibu, utils.ConditionReasons.InProgress, utils.InProgress)
 			utils.SetPrepStatusInProgress(ibu, utils.InProgress)
 		case ibuv1.Stages.Upgrade:
@@ -423,6 +492,7 @@ func validateStageTransition(ibu *ibuv1.ImageBasedUpgrade, isAfterPivot bool) bo
 		case ibuv1.Stages.Rollback:
 			utils.SetUpgradeStatusRollbackRequested(ibu)
 			utils.SetRollbackStatusInProgress(ibu, utils.InProgress)
+			metrics.RecordIBURollback()
 		case ibuv1.Stages.Idle:
 			idleReason := isFinalizeOrAbort(ibu, isAfterPivot)
 			switch idleReason {
@@ -495,6 +565,13 @@ func (r *ImageBasedUpgradeReconciler) SetupWithManager(mgr ctrl.Manager) error {
 					return true
 				}
 
+				// trigger reconcile upon adding or updating PreflightAnnotation
+				oldValue, oldExist = e.ObjectOld.GetAnnotations()[utils.PreflightAnnotation]
+				newValue, newExist = e.ObjectNew.GetAnnotations()[utils.PreflightAnnotation]
+				if !oldExist && newExist || (oldExist && newExist && oldValue != newValue) {
+					return true
+				}
+
 				return false
 			},
 			CreateFunc:  func(ce event.CreateEvent) bool { return true },
@@ -20,6 +20,7 @@ import (
 	"context"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/go-logr/logr"
 	ibuv1 "github.com/openshift-kni/lifecycle-agent/api/imagebasedupgrade/v1"
@@ -860,6 +861,51 @@ func TestImageBasedUpgradeReconciler_Reconcile(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "upgrade requested outside maintenance window",
+			ibu: &ibuv1.ImageBasedUpgrade{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: utils.IBUName,
+				},
+				Spec: ibuv1.ImageBasedUpgradeSpec{
+					Stage: ibuv1.Stages.Upgrade,
+					Schedule: &ibuv1.Schedule{
+						Windows: []ibuv1.MaintenanceWindow{
+							{
+								Start: metav1.NewTime(time.Now().Add(2 * time.Hour)),
+								End:   metav1.NewTime(time.Now().Add(4 * time.Hour)),
+							},
+						},
+					},
+				},
+				Status: ibuv1.ImageBasedUpgradeStatus{
+					Conditions: []metav1.Condition{
+						{Type: string(utils.ConditionTypes.Idle), Status: metav1.ConditionFalse, Reason: string(utils.ConditionReasons.InProgress)},
+						{Type: string(utils.ConditionTypes.PrepInProgress), Status: metav1.ConditionFalse, Reason: string(utils.ConditionReasons.Completed)},
+						{Type: string(utils.ConditionTypes.PrepCompleted), Status: metav1.ConditionTrue, Reason: string(utils.ConditionReasons.Completed)},
+					},
+				},
+			},
+			ipc: &ipcv1.IPConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: common.IPConfigName,
+				},
+			},
+			request: reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name: utils.IBUName,
+				},
+			},
+			validateFunc: func(t *testing.T, result ctrl.Result, ibu *ibuv1.ImageBasedUpgrade) {
+				upgradeCondition := meta.FindStatusCondition(ibu.Status.Conditions, string(utils.ConditionTypes.UpgradeInProgress))
+				if assert.NotNil(t, upgradeCondition) {
+					assert.Equal(t, metav1.ConditionFalse, upgradeCondition.Status)
+					assert.Equal(t, string(utils.ConditionReasons.Scheduled), upgradeCondition.Reason)
+				}
+				assert.Greater(t, result.RequeueAfter, time.Hour)
+				assert.Equal(t, []ibuv1.ImageBasedUpgradeStage{ibuv1.Stages.Idle, ibuv1.Stages.Upgrade}, ibu.Status.ValidNextStages)
+			},
+		},
 	}
 	for _, tc := range testcases {
 		t.TempDir()
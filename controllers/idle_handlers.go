@@ -45,6 +45,14 @@ var osRemoveAll = os.RemoveAll
 
 func (r *ImageBasedUpgradeReconciler) resetStatusFields(ibu *ibuv1.ImageBasedUpgrade) {
 	ibu.Status.RollbackAvailabilityExpiration.Reset()
+	ibu.Status.SeedImageDigest = ""
+	ibu.Status.RetainedBackupsSuffix = ""
+	ibu.Status.Precache = nil
+	ibu.Status.BackupEstimate = nil
+	ibu.Status.ToleratedRestoreErrors = nil
+	ibu.Status.InventoryReport = nil
+	ibu.Status.SelectedPolicies = nil
+	ibu.Status.ExtraManifestsDiff = nil
 	utils.ResetStatusConditions(&ibu.Status.Conditions, ibu.Generation)
 }
 
@@ -168,10 +176,31 @@ func (r *ImageBasedUpgradeReconciler) cleanup(ctx context.Context, ibu *ibuv1.Im
 	}
 
 	r.Log.Info("Cleaning up OADP resources")
-	if err := r.cleanupOADPResources(ctx); err != nil {
+	if err := r.cleanupOADPResources(ctx, ibu); err != nil {
 		handleError(err, "failed to cleanup OADP resources")
 	}
 
+	// The workloads are still scaled down if the upgrade failed after quiescing them, or was rolled back without LCA
+	r.Log.Info("Restoring quiesced workloads")
+	if err := restoreQuiescedWorkloads(ctx, r.Client, r.Log); err != nil {
+		handleError(err, "failed to restore quiesced workloads")
+	}
+
+	r.Log.Info("Cleaning up post-upgrade validation Jobs")
+	if err := cleanupPostUpgradeValidationJobs(ctx, r.Client, r.NoncachedClient); err != nil {
+		handleError(err, "failed to cleanup post-upgrade validation Jobs")
+	}
+
+	r.Log.Info("Cleaning up inventory report")
+	if err := deleteConfigMap(ctx, r.Client, inventoryReportConfigMapName); err != nil {
+		handleError(err, "failed to cleanup inventory report")
+	}
+
+	r.Log.Info("Cleaning up extra manifests diff")
+	if err := deleteConfigMap(ctx, r.Client, extraManifestsDiffConfigMapName); err != nil {
+		handleError(err, "failed to cleanup extra manifests diff")
+	}
+
 	r.Log.Info("Cleaning up IBU files")
 	if err := cleanupIBUFiles(); err != nil {
 		handleError(err, "failed to cleanup ibu files.")
@@ -181,7 +210,7 @@ func (r *ImageBasedUpgradeReconciler) cleanup(ctx context.Context, ibu *ibuv1.Im
 }
 
 // cleanupOADPResources clean resources from backup/restore as long as OADP is present
-func (r *ImageBasedUpgradeReconciler) cleanupOADPResources(ctx context.Context) error {
+func (r *ImageBasedUpgradeReconciler) cleanupOADPResources(ctx context.Context, ibu *ibuv1.ImageBasedUpgrade) error {
 	if !r.BackupRestore.IsOadpInstalled(ctx) {
 		r.Log.Info("OADP not installed, nothing to cleanup")
 		return nil
@@ -192,8 +221,18 @@ func (r *ImageBasedUpgradeReconciler) cleanupOADPResources(ctx context.Context)
 		return fmt.Errorf("failed to cleanup DeleteBackupRequest CRs: %w", err)
 	}
 
+	// The backups created to be kept are deleted if the upgrade did not complete
+	discardedSuffix := ibu.Status.RetainedBackupsSuffix
+	if shouldRetainBackups(ibu) {
+		r.Log.Info("Retaining Backup", "policy", ibu.Spec.BackupRetention.Policy)
+		if err := r.BackupRestore.RetainBackups(ctx, ibu.Spec.BackupRetention); err != nil {
+			return fmt.Errorf("failed to retain backups: %w", err)
+		}
+		discardedSuffix = ""
+	}
+
 	r.Log.Info("Cleaning up Backup")
-	if err := r.BackupRestore.CleanupBackups(ctx); err != nil {
+	if err := r.BackupRestore.CleanupBackups(ctx, discardedSuffix); err != nil {
 		return fmt.Errorf("failed to cleanup backups: %w", err)
 	}
 
@@ -206,6 +245,17 @@ func (r *ImageBasedUpgradeReconciler) cleanupOADPResources(ctx context.Context)
 	return nil
 }
 
+// shouldRetainBackups checks whether the backups are kept according to the retention policy, which only applies
+// when a successful upgrade is finalized. The backups are deleted on rollback and abort.
+func shouldRetainBackups(ibu *ibuv1.ImageBasedUpgrade) bool {
+	retention := ibu.Spec.BackupRetention
+	if retention == nil || retention.Policy == ibuv1.BackupRetentionPolicies.Delete {
+		return false
+	}
+	return utils.IsStageCompleted(ibu, ibuv1.Stages.Upgrade) && !utils.IsStageCompletedOrFailed(ibu, ibuv1.Stages.Rollback) &&
+		!utils.IsStageInProgress(ibu, ibuv1.Stages.Rollback)
+}
+
 func (r *ImageBasedUpgradeReconciler) cleanupStateroot(ctx context.Context) error {
 	r.Log.Info("Cleaning up cluster stateroot resources")
 	if err := prep.DeleteStaterootSetupJob(ctx, r.Client, r.Log); err != nil {
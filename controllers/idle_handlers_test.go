@@ -28,6 +28,7 @@ import (
 	"github.com/openshift-kni/lifecycle-agent/internal/ostreeclient"
 	"github.com/openshift-kni/lifecycle-agent/lca-cli/ops"
 	rpmostreeclient "github.com/openshift-kni/lifecycle-agent/lca-cli/ostreeclient"
+	"github.com/samber/lo"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 	corev1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -303,3 +304,27 @@ func TestImageBasedUpgradeReconciler_cleanupUnbootedStateroots(t *testing.T) {
 		})
 	}
 }
+
+func TestShouldRetainBackups(t *testing.T) {
+	keepLast := &ibuv1.BackupRetention{Policy: ibuv1.BackupRetentionPolicies.KeepLast, Count: lo.ToPtr(2)}
+
+	upgraded := &ibuv1.ImageBasedUpgrade{Spec: ibuv1.ImageBasedUpgradeSpec{BackupRetention: keepLast}}
+	utils.SetUpgradeStatusCompleted(upgraded)
+	assert.True(t, shouldRetainBackups(upgraded))
+
+	deleted := upgraded.DeepCopy()
+	deleted.Spec.BackupRetention = &ibuv1.BackupRetention{Policy: ibuv1.BackupRetentionPolicies.Delete}
+	assert.False(t, shouldRetainBackups(deleted))
+
+	noPolicy := upgraded.DeepCopy()
+	noPolicy.Spec.BackupRetention = nil
+	assert.False(t, shouldRetainBackups(noPolicy))
+
+	rolledBack := upgraded.DeepCopy()
+	utils.SetRollbackStatusCompleted(rolledBack)
+	assert.False(t, shouldRetainBackups(rolledBack))
+
+	aborted := &ibuv1.ImageBasedUpgrade{Spec: ibuv1.ImageBasedUpgradeSpec{BackupRetention: keepLast}}
+	utils.SetUpgradeStatusFailed(aborted, "failed")
+	assert.False(t, shouldRetainBackups(aborted))
+}
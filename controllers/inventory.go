@@ -0,0 +1,173 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	ibuv1 "github.com/openshift-kni/lifecycle-agent/api/imagebasedupgrade/v1"
+	"github.com/openshift-kni/lifecycle-agent/controllers/utils"
+	"github.com/openshift-kni/lifecycle-agent/internal/common"
+	"github.com/openshift-kni/lifecycle-agent/internal/inventory"
+	lcautils "github.com/openshift-kni/lifecycle-agent/utils"
+	"github.com/samber/lo"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	inventoryReportConfigMapName = "lca-inventory-report"
+	inventoryReportKey           = "changes.yaml"
+
+	defaultInventoryCheckTimeout = 30 * time.Minute
+)
+
+// inventoryPath is the inventory taken before the pivot in the current stateroot
+var inventoryPath = common.PathOutsideChroot(utils.InventoryFilePath)
+
+// snapshotInventory saves the inventory of the cluster in the current stateroot. It is taken once per upgrade, before
+// the workloads are quiesced.
+func (u *UpgHandler) snapshotInventory(ctx context.Context) error {
+	if _, err := os.Stat(inventoryPath); err == nil {
+		return nil
+	}
+
+	snapshot, err := inventory.TakeSnapshot(ctx, u.NoncachedClient)
+	if err != nil {
+		return fmt.Errorf("failed to take inventory snapshot: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(inventoryPath), 0o700); err != nil {
+		return fmt.Errorf("failed to mkdir: %w", err)
+	}
+	if err := lcautils.MarshalToFile(snapshot, inventoryPath); err != nil {
+		return fmt.Errorf("failed to write inventory snapshot: %w", err)
+	}
+	return nil
+}
+
+// exportInventoryToNewStateroot copies the inventory to the new stateroot, so it is compared once the upgrade completes
+func exportInventoryToNewStateroot(staterootPath string) error {
+	if err := lcautils.CopyFileIfExists(inventoryPath, filepath.Join(staterootPath, utils.InventoryFilePath)); err != nil {
+		return fmt.Errorf("failed to copy inventory to the new stateroot: %w", err)
+	}
+	return nil
+}
+
+// compareInventory compares the inventory taken before the pivot with the current one, reports the changes in the
+// inventory report configmap and in the IBU status, and returns the regressions of spec.inventoryCheck
+func (u *UpgHandler) compareInventory(ctx context.Context, ibu *ibuv1.ImageBasedUpgrade) ([]inventory.Change, error) {
+	before := &inventory.Snapshot{}
+	if err := lcautils.ReadYamlOrJSONFile(inventoryPath, before); err != nil {
+		if os.IsNotExist(err) {
+			u.Log.Info("No inventory taken before the pivot, skipping comparison")
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read inventory snapshot: %w", err)
+	}
+
+	after, err := inventory.TakeSnapshot(ctx, u.NoncachedClient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to take inventory snapshot: %w", err)
+	}
+	changes := inventory.Compare(before, after)
+	var regressions []inventory.Change
+	if ibu.Spec.InventoryCheck != nil {
+		regressions = inventory.Regressions(changes, ibu.Spec.InventoryCheck.MustNotRegress)
+	}
+
+	data, err := yaml.Marshal(changes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal inventory changes: %w", err)
+	}
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: inventoryReportConfigMapName, Namespace: common.LcaNamespace},
+		Data:       map[string]string{inventoryReportKey: string(data)},
+	}
+	if err := createOrUpdateConfigMap(ctx, u.Client, cm); err != nil {
+		return nil, fmt.Errorf("failed to save inventory report: %w", err)
+	}
+
+	report := &ibuv1.InventoryReport{
+		ConfigMap: ibuv1.ConfigMapRef{Name: cm.Name, Namespace: cm.Namespace},
+		Changes:   len(changes),
+	}
+	for _, regression := range regressions {
+		report.Regressions = append(report.Regressions, regression.String())
+	}
+	if len(regressions) > 0 {
+		report.RegressedSince = lo.ToPtr(metav1.Now())
+		if ibu.Status.InventoryReport != nil && ibu.Status.InventoryReport.RegressedSince != nil {
+			report.RegressedSince = ibu.Status.InventoryReport.RegressedSince
+		}
+	}
+	ibu.Status.InventoryReport = report
+	u.Log.Info("Inventory compared", "changes", len(changes), "regressions", len(regressions))
+	return regressions, nil
+}
+
+// getInventoryRegressionsExpiredTimeout returns a message if the inventory regressions have not recovered within
+// spec.inventoryCheck.timeout, or an empty string otherwise
+func getInventoryRegressionsExpiredTimeout(ibu *ibuv1.ImageBasedUpgrade, now time.Time) string {
+	if ibu.Spec.InventoryCheck == nil || ibu.Status.InventoryReport == nil || ibu.Status.InventoryReport.RegressedSince == nil {
+		return ""
+	}
+	timeout := defaultInventoryCheckTimeout
+	if ibu.Spec.InventoryCheck.Timeout != nil {
+		timeout = ibu.Spec.InventoryCheck.Timeout.Duration
+	}
+	if now.Sub(ibu.Status.InventoryReport.RegressedSince.Time) <= timeout {
+		return ""
+	}
+	return fmt.Sprintf("inventory regressions did not recover in %s: %s", timeout,
+		strings.Join(ibu.Status.InventoryReport.Regressions, "; "))
+}
+
+// deleteConfigMap deletes the configmap of the LCA namespace, if it exists
+func deleteConfigMap(ctx context.Context, c client.Client, name string) error {
+	cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: common.LcaNamespace}}
+	if err := c.Delete(ctx, cm); err != nil && !k8serrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete configmap %s: %w", name, err)
+	}
+	return nil
+}
+
+func createOrUpdateConfigMap(ctx context.Context, c client.Client, cm *corev1.ConfigMap) error {
+	existing := &corev1.ConfigMap{}
+	if err := c.Get(ctx, types.NamespacedName{Name: cm.Name, Namespace: cm.Namespace}, existing); err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return fmt.Errorf("failed to get configmap: %w", err)
+		}
+		if err := c.Create(ctx, cm); err != nil {
+			return fmt.Errorf("failed to create configmap: %w", err)
+		}
+		return nil
+	}
+	cm.SetResourceVersion(existing.GetResourceVersion())
+	if err := c.Update(ctx, cm); err != nil {
+		return fmt.Errorf("failed to update configmap: %w", err)
+	}
+	return nil
+}
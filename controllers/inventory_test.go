@@ -0,0 +1,101 @@
+package controllers
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	ibuv1 "github.com/openshift-kni/lifecycle-agent/api/imagebasedupgrade/v1"
+	"github.com/openshift-kni/lifecycle-agent/internal/common"
+	"github.com/openshift-kni/lifecycle-agent/internal/inventory"
+	configv1 "github.com/openshift/api/config/v1"
+	operatorsv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/yaml"
+)
+
+func TestInventory(t *testing.T) {
+	origInventoryPath := inventoryPath
+	defer func() {
+		inventoryPath = origInventoryPath
+	}()
+	inventoryPath = filepath.Join(t.TempDir(), "inventory.json")
+
+	s := runtime.NewScheme()
+	for _, addToScheme := range []func(*runtime.Scheme) error{
+		clientgoscheme.AddToScheme, apiextensionsv1.AddToScheme, configv1.AddToScheme, operatorsv1alpha1.AddToScheme,
+	} {
+		assert.NoError(t, addToScheme(s))
+	}
+	oldApp := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "old-app"}}
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "my-app"}}, oldApp,
+	).Build()
+	uh := &UpgHandler{Client: c, NoncachedClient: c, Log: logr.Discard()}
+	ibu := &ibuv1.ImageBasedUpgrade{}
+
+	// Nothing to compare without snapshot
+	regressions, err := uh.compareInventory(context.Background(), ibu)
+	assert.NoError(t, err)
+	assert.Empty(t, regressions)
+	assert.Nil(t, ibu.Status.InventoryReport)
+
+	// The snapshot is taken once, and copied to the new stateroot
+	assert.NoError(t, uh.snapshotInventory(context.Background()))
+	assert.NoError(t, c.Delete(context.Background(), oldApp))
+	assert.NoError(t, uh.snapshotInventory(context.Background()))
+	staterootPath := t.TempDir()
+	assert.NoError(t, exportInventoryToNewStateroot(staterootPath))
+	assert.FileExists(t, filepath.Join(staterootPath, "var/lib/lca/workspace/inventory.json"))
+
+	// The removed namespace is reported, and is a regression only if namespaces must not regress
+	regressions, err = uh.compareInventory(context.Background(), ibu)
+	assert.NoError(t, err)
+	assert.Empty(t, regressions)
+	assert.Equal(t, &ibuv1.InventoryReport{
+		ConfigMap: ibuv1.ConfigMapRef{Name: inventoryReportConfigMapName, Namespace: common.LcaNamespace},
+		Changes:   1,
+	}, ibu.Status.InventoryReport)
+
+	ibu.Spec.InventoryCheck = &ibuv1.InventoryCheck{MustNotRegress: []ibuv1.InventoryCategory{ibuv1.InventoryCategories.Namespaces}}
+	regressions, err = uh.compareInventory(context.Background(), ibu)
+	assert.NoError(t, err)
+	assert.Len(t, regressions, 1)
+	assert.Equal(t, []string{"Namespaces old-app removed"}, ibu.Status.InventoryReport.Regressions)
+	assert.NotNil(t, ibu.Status.InventoryReport.RegressedSince)
+
+	// The regressions are waited for up to the timeout, measured from when they were first found
+	regressedSince := metav1.NewTime(time.Now().Add(-time.Hour))
+	ibu.Status.InventoryReport.RegressedSince = &regressedSince
+	_, err = uh.compareInventory(context.Background(), ibu)
+	assert.NoError(t, err)
+	assert.Equal(t, &regressedSince, ibu.Status.InventoryReport.RegressedSince)
+	assert.Equal(t, "inventory regressions did not recover in 30m0s: Namespaces old-app removed",
+		getInventoryRegressionsExpiredTimeout(ibu, time.Now()))
+	ibu.Spec.InventoryCheck.Timeout = &metav1.Duration{Duration: 2 * time.Hour}
+	assert.Empty(t, getInventoryRegressionsExpiredTimeout(ibu, time.Now()))
+
+	cm := &corev1.ConfigMap{}
+	assert.NoError(t, c.Get(context.Background(), types.NamespacedName{Name: inventoryReportConfigMapName, Namespace: common.LcaNamespace}, cm))
+	var changes []inventory.Change
+	assert.NoError(t, yaml.Unmarshal([]byte(cm.Data[inventoryReportKey]), &changes))
+	assert.Equal(t, []inventory.Change{{
+		Category: ibuv1.InventoryCategories.Namespaces, Type: inventory.ChangeRemoved, Name: "old-app", Regression: true,
+	}}, changes)
+
+	// The report is deleted at Idle
+	assert.NoError(t, deleteConfigMap(context.Background(), c, inventoryReportConfigMapName))
+	assert.True(t, k8serrors.IsNotFound(c.Get(context.Background(),
+		types.NamespacedName{Name: inventoryReportConfigMapName, Namespace: common.LcaNamespace}, cm)))
+	assert.NoError(t, deleteConfigMap(context.Background(), c, inventoryReportConfigMapName))
+}
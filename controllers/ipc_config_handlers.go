@@ -8,6 +8,7 @@ import (
 	"os"
 	"reflect"
 	"strings"
+	"time"
 
 	"github.com/go-logr/logr"
 	ipcv1 "github.com/openshift-kni/lifecycle-agent/api/ipconfig/v1"
@@ -144,6 +145,15 @@ func (h *IPCConfigStageHandler) Handle(ctx context.Context, ipc *ipcv1.IPConfig)
 		return doNotRequeue(), nil
 	}
 
+	if msg := getIPConfigExpiredTimeout(ipc, time.Now()); msg != "" {
+		logger.Info("Config stage timed out", "reason", msg)
+		controllerutils.SetIPConfigStatusFailedWithReason(ipc, controllerutils.ConditionReasons.TimedOut, msg)
+		if err := controllerutils.UpdateIPCStatus(ctx, h.Client, ipc); err != nil {
+			return requeueWithError(fmt.Errorf("failed to update ipconfig status: %w", err))
+		}
+		return doNotRequeue(), nil
+	}
+
 	targetStaterootBooted, err := isTargetStaterootBooted(ipc, h.RPMOstreeClient)
 	if err != nil {
 		logger.Error(err, "Failed to determine whether target stateroot is booted")
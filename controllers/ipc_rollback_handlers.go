@@ -10,6 +10,7 @@ import (
 	ipcv1 "github.com/openshift-kni/lifecycle-agent/api/ipconfig/v1"
 	controllerutils "github.com/openshift-kni/lifecycle-agent/controllers/utils"
 	"github.com/openshift-kni/lifecycle-agent/internal/common"
+	"github.com/openshift-kni/lifecycle-agent/internal/metrics"
 	"github.com/openshift-kni/lifecycle-agent/lca-cli/ops"
 	rpmostreeclient "github.com/openshift-kni/lifecycle-agent/lca-cli/ostreeclient"
 	"github.com/samber/lo"
@@ -114,6 +115,7 @@ func (h *IPCRollbackStageHandler) Handle(
 			logger.Error(err, "Failed to update IPConfig rollback status to rollback requested")
 			return requeueWithError(fmt.Errorf("failed to update ipconfig status: %w", err))
 		}
+		metrics.RecordIPCRollback()
 	}
 
 	// stop when completed or failed
@@ -0,0 +1,225 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	ibuv1 "github.com/openshift-kni/lifecycle-agent/api/imagebasedupgrade/v1"
+	"github.com/openshift-kni/lifecycle-agent/internal/common"
+	lcautils "github.com/openshift-kni/lifecycle-agent/utils"
+	"github.com/samber/lo"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// postUpgradeValidationJobsPath is where the Jobs of spec.postUpgradeValidation are exported in the new stateroot /var
+	postUpgradeValidationJobsPath = "/opt/post-upgrade-validation/jobs.json"
+	postUpgradeValidationLabel    = "lca.openshift.io/post-upgrade-validation"
+
+	// The last lines of the logs of a failed Job are summarized in the condition message
+	postUpgradeValidationLogLines = 5
+	postUpgradeValidationLogBytes = 1024
+)
+
+// errPostUpgradeValidationFailed is wrapped by the errors of the post-upgrade validation Jobs that fail
+var errPostUpgradeValidationFailed = errors.New("post-upgrade validation failed")
+
+// postUpgradeValidationJobsFile is the file of the exported Jobs in the current stateroot
+var postUpgradeValidationJobsFile = common.PathOutsideChroot(postUpgradeValidationJobsPath)
+
+// getPostUpgradeValidationJobs extracts the Jobs from the configmaps of spec.postUpgradeValidation, sorted by
+// namespace and name
+func getPostUpgradeValidationJobs(ctx context.Context, c client.Client, configMaps []ibuv1.ConfigMapRef) ([]batchv1.Job, error) {
+	cms, err := common.GetConfigMaps(ctx, c, configMaps)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get post-upgrade validation configmaps: %w", err)
+	}
+	jobs, err := common.ExtractResourcesFromConfigmaps[*batchv1.Job](cms, batchv1.SchemeGroupVersion.WithKind("Job"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract post-upgrade validation Jobs: %w", err)
+	}
+	if len(jobs) == 0 {
+		return nil, fmt.Errorf("no Job found in the post-upgrade validation configmaps")
+	}
+
+	result := make([]batchv1.Job, 0, len(jobs))
+	for _, job := range jobs {
+		if job.Name == "" || job.Namespace == "" {
+			return nil, fmt.Errorf("post-upgrade validation Job %q must have a name and a namespace", job.Name)
+		}
+		result = append(result, *job)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return client.ObjectKeyFromObject(&result[i]).String() < client.ObjectKeyFromObject(&result[j]).String()
+	})
+	return result, nil
+}
+
+// exportPostUpgradeValidationJobs writes the Jobs of spec.postUpgradeValidation to the new stateroot, as their
+// configmaps may not be restored by the time they are launched
+func exportPostUpgradeValidationJobs(ctx context.Context, c client.Client, configMaps []ibuv1.ConfigMapRef, ostreeVarDir string) error {
+	jobs, err := getPostUpgradeValidationJobs(ctx, c, configMaps)
+	if err != nil {
+		return err
+	}
+
+	filePath := filepath.Join(ostreeVarDir, postUpgradeValidationJobsPath)
+	if err := os.MkdirAll(filepath.Dir(filePath), 0o700); err != nil {
+		return fmt.Errorf("failed to mkdir: %w", err)
+	}
+	if err := lcautils.MarshalToFile(jobs, filePath); err != nil {
+		return fmt.Errorf("failed to write post-upgrade validation Jobs: %w", err)
+	}
+	return nil
+}
+
+// runPostUpgradeValidation launches the Jobs exported to the current stateroot, and returns whether they all
+// succeeded. An error wrapping errPostUpgradeValidationFailed, with a summary of their logs, is returned when any of
+// the Jobs fails. Jobs outside the LCA namespace are not cached, so they are read with the noncached client.
+func (u *UpgHandler) runPostUpgradeValidation(ctx context.Context) (bool, error) {
+	var jobs []batchv1.Job
+	if err := lcautils.ReadYamlOrJSONFile(postUpgradeValidationJobsFile, &jobs); err != nil {
+		if os.IsNotExist(err) {
+			u.Log.Info("No post-upgrade validation Jobs, skipping")
+			return true, nil
+		}
+		return false, fmt.Errorf("failed to read post-upgrade validation Jobs: %w", err)
+	}
+
+	succeeded := true
+	var failures []string
+	for i := range jobs {
+		key := client.ObjectKeyFromObject(&jobs[i])
+		job := &batchv1.Job{}
+		if err := u.NoncachedClient.Get(ctx, key, job); err != nil {
+			if !k8serrors.IsNotFound(err) {
+				return false, fmt.Errorf("failed to get post-upgrade validation Job %s: %w", key, err)
+			}
+			if err := u.Create(ctx, newPostUpgradeValidationJob(&jobs[i])); err != nil {
+				return false, fmt.Errorf("failed to create post-upgrade validation Job %s: %w", key, err)
+			}
+			u.Log.Info("Post-upgrade validation Job created", "job", key)
+			succeeded = false
+			continue
+		}
+		if _, found := job.GetLabels()[postUpgradeValidationLabel]; !found {
+			failures = append(failures, fmt.Sprintf("Job %s already exists and was not created by the post-upgrade validation", key))
+			continue
+		}
+
+		finished, conditionType := common.IsJobFinished(job)
+		switch {
+		case !finished:
+			succeeded = false
+		case conditionType == batchv1.JobFailed:
+			failures = append(failures, u.summarizeFailedJob(ctx, job))
+		}
+	}
+
+	if len(failures) > 0 {
+		return false, fmt.Errorf("%w: %s", errPostUpgradeValidationFailed, strings.Join(failures, "; "))
+	}
+	return succeeded, nil
+}
+
+func newPostUpgradeValidationJob(template *batchv1.Job) *batchv1.Job {
+	job := template.DeepCopy()
+	job.ResourceVersion = ""
+	job.UID = ""
+	job.Status = batchv1.JobStatus{}
+	job.Labels = lo.Assign(job.Labels, map[string]string{postUpgradeValidationLabel: ""})
+	return job
+}
+
+// summarizeFailedJob returns the reason of the Job failure, followed by the last lines of its logs
+func (u *UpgHandler) summarizeFailedJob(ctx context.Context, job *batchv1.Job) string {
+	summary := fmt.Sprintf("Job %s/%s failed", job.Namespace, job.Name)
+	condition, found := lo.Find(job.Status.Conditions, func(c batchv1.JobCondition) bool { return c.Type == batchv1.JobFailed })
+	if found && condition.Message != "" {
+		summary += ": " + condition.Message
+	}
+
+	logs, err := getJobLogsTail(ctx, u.Clientset, job)
+	if err != nil {
+		u.Log.Info("Failed to get logs of post-upgrade validation Job", "job", job.Name, "err", err.Error())
+		return summary
+	}
+	if logs != "" {
+		summary += fmt.Sprintf(" (logs: %s)", logs)
+	}
+	return summary
+}
+
+// getJobLogsTail returns the last lines of the logs of the most recent pod of the Job, on a single line
+var getJobLogsTail = func(ctx context.Context, clientset *kubernetes.Clientset, job *batchv1.Job) (string, error) {
+	if clientset == nil {
+		return "", nil
+	}
+
+	pods, err := clientset.CoreV1().Pods(job.Namespace).List(ctx, metav1.ListOptions{LabelSelector: "job-name=" + job.Name})
+	if err != nil {
+		return "", fmt.Errorf("failed to list pods: %w", err)
+	}
+	if len(pods.Items) == 0 {
+		return "", nil
+	}
+	pod := lo.MaxBy(pods.Items, func(a, b corev1.Pod) bool { return a.CreationTimestamp.After(b.CreationTimestamp.Time) })
+
+	req := clientset.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{
+		TailLines:  lo.ToPtr(int64(postUpgradeValidationLogLines)),
+		LimitBytes: lo.ToPtr(int64(postUpgradeValidationLogBytes)),
+	})
+	podLogs, err := req.Stream(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to get logs of pod %s: %w", pod.Name, err)
+	}
+	defer func() { _ = podLogs.Close() }()
+
+	buf := new(bytes.Buffer)
+	if _, err := io.Copy(buf, podLogs); err != nil {
+		return "", fmt.Errorf("failed to read logs of pod %s: %w", pod.Name, err)
+	}
+	return strings.Join(strings.Split(strings.TrimSpace(buf.String()), "\n"), " | "), nil
+}
+
+// cleanupPostUpgradeValidationJobs deletes the Jobs launched by the post-upgrade validation
+func cleanupPostUpgradeValidationJobs(ctx context.Context, c client.Client, reader client.Reader) error {
+	jobs := &batchv1.JobList{}
+	if err := reader.List(ctx, jobs, client.HasLabels{postUpgradeValidationLabel}); err != nil {
+		return fmt.Errorf("failed to list post-upgrade validation Jobs: %w", err)
+	}
+	for i := range jobs.Items {
+		if err := c.Delete(ctx, &jobs.Items[i], client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil && !k8serrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete post-upgrade validation Job %s/%s: %w", jobs.Items[i].Namespace, jobs.Items[i].Name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-logr/logr"
+	ibuv1 "github.com/openshift-kni/lifecycle-agent/api/imagebasedupgrade/v1"
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+const smokeTestJobs = `apiVersion: batch/v1
+kind: Job
+metadata:
+  name: smoke
+  namespace: my-app
+spec:
+  template:
+    spec:
+      restartPolicy: Never
+      containers:
+      - name: smoke
+        image: quay.io/my-app/smoke:latest
+---
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: api
+  namespace: my-app
+spec:
+  template:
+    spec:
+      restartPolicy: Never
+      containers:
+      - name: api
+        image: quay.io/my-app/api-tests:latest
+`
+
+func TestGetPostUpgradeValidationJobs(t *testing.T) {
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "validation", Namespace: "my-app"},
+		Data:       map[string]string{"jobs.yaml": smokeTestJobs},
+	}
+	empty := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "empty", Namespace: "my-app"},
+		Data:       map[string]string{"cm.yaml": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: other\n"},
+	}
+	c, err := getFakeClientFromObjects(cm, empty)
+	assert.NoError(t, err)
+
+	jobs, err := getPostUpgradeValidationJobs(context.Background(), c, []ibuv1.ConfigMapRef{{Name: "validation", Namespace: "my-app"}})
+	assert.NoError(t, err)
+	assert.Len(t, jobs, 2)
+	assert.Equal(t, "api", jobs[0].Name)
+	assert.Equal(t, "smoke", jobs[1].Name)
+
+	_, err = getPostUpgradeValidationJobs(context.Background(), c, []ibuv1.ConfigMapRef{{Name: "empty", Namespace: "my-app"}})
+	assert.ErrorContains(t, err, "no Job found")
+
+	_, err = getPostUpgradeValidationJobs(context.Background(), c, []ibuv1.ConfigMapRef{{Name: "missing", Namespace: "my-app"}})
+	assert.Error(t, err)
+}
+
+func TestRunPostUpgradeValidation(t *testing.T) {
+	origJobsFile := postUpgradeValidationJobsFile
+	origGetJobLogsTail := getJobLogsTail
+	defer func() {
+		postUpgradeValidationJobsFile = origJobsFile
+		getJobLogsTail = origGetJobLogsTail
+	}()
+	getJobLogsTail = func(ctx context.Context, clientset *kubernetes.Clientset, job *batchv1.Job) (string, error) {
+		return "GET /healthz | 503 Service Unavailable", nil
+	}
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "validation", Namespace: "my-app"},
+		Data:       map[string]string{"jobs.yaml": smokeTestJobs},
+	}
+	c, err := getFakeClientFromObjects(cm)
+	assert.NoError(t, err)
+	uh := &UpgHandler{Client: c, NoncachedClient: c, Log: logr.Discard()}
+
+	// Nothing to validate without exported Jobs
+	postUpgradeValidationJobsFile = filepath.Join(t.TempDir(), postUpgradeValidationJobsPath)
+	validated, err := uh.runPostUpgradeValidation(context.Background())
+	assert.NoError(t, err)
+	assert.True(t, validated)
+
+	// The Jobs are exported to the new stateroot, then created
+	staterootVarPath := t.TempDir()
+	assert.NoError(t, exportPostUpgradeValidationJobs(context.Background(), c, []ibuv1.ConfigMapRef{{Name: "validation", Namespace: "my-app"}}, staterootVarPath))
+	postUpgradeValidationJobsFile = filepath.Join(staterootVarPath, postUpgradeValidationJobsPath)
+	validated, err = uh.runPostUpgradeValidation(context.Background())
+	assert.NoError(t, err)
+	assert.False(t, validated)
+
+	smoke := &batchv1.Job{}
+	assert.NoError(t, c.Get(context.Background(), types.NamespacedName{Name: "smoke", Namespace: "my-app"}, smoke))
+	assert.Contains(t, smoke.Labels, postUpgradeValidationLabel)
+	api := &batchv1.Job{}
+	assert.NoError(t, c.Get(context.Background(), types.NamespacedName{Name: "api", Namespace: "my-app"}, api))
+
+	// Validation is in progress until all Jobs succeed
+	smoke.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}}
+	assert.NoError(t, c.Status().Update(context.Background(), smoke))
+	validated, err = uh.runPostUpgradeValidation(context.Background())
+	assert.NoError(t, err)
+	assert.False(t, validated)
+
+	api.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}}
+	assert.NoError(t, c.Status().Update(context.Background(), api))
+	validated, err = uh.runPostUpgradeValidation(context.Background())
+	assert.NoError(t, err)
+	assert.True(t, validated)
+
+	// A failed Job fails the validation, with its logs
+	api.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Message: "Job has reached the specified backoff limit"}}
+	assert.NoError(t, c.Status().Update(context.Background(), api))
+	_, err = uh.runPostUpgradeValidation(context.Background())
+	assert.True(t, errors.Is(err, errPostUpgradeValidationFailed))
+	assert.EqualError(t, err, "post-upgrade validation failed: Job my-app/api failed: Job has reached the specified backoff limit "+
+		"(logs: GET /healthz | 503 Service Unavailable)")
+
+	// The Jobs are deleted on cleanup
+	assert.NoError(t, cleanupPostUpgradeValidationJobs(context.Background(), c, c))
+	assert.True(t, k8serrors.IsNotFound(c.Get(context.Background(), types.NamespacedName{Name: "smoke", Namespace: "my-app"}, &batchv1.Job{})))
+	assert.True(t, k8serrors.IsNotFound(c.Get(context.Background(), types.NamespacedName{Name: "api", Namespace: "my-app"}, &batchv1.Job{})))
+
+	// A Job that was not created by the validation is not trusted
+	c, err = getFakeClientFromObjects(&batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "smoke", Namespace: "my-app"}})
+	assert.NoError(t, err)
+	uh = &UpgHandler{Client: c, NoncachedClient: c, Log: logr.Discard()}
+	_, err = uh.runPostUpgradeValidation(context.Background())
+	assert.ErrorContains(t, err, "Job my-app/smoke already exists and was not created by the post-upgrade validation")
+}
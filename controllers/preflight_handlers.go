@@ -0,0 +1,166 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	ibuv1 "github.com/openshift-kni/lifecycle-agent/api/imagebasedupgrade/v1"
+	"github.com/openshift-kni/lifecycle-agent/controllers/utils"
+	"github.com/openshift-kni/lifecycle-agent/internal/common"
+	"github.com/openshift-kni/lifecycle-agent/internal/healthcheck"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// Names of the preflight checks reported in status.preflight.checks
+const (
+	PreflightCheckClusterHealth              = "ClusterHealth"
+	PreflightCheckContainerStorageMountpoint = "ContainerStorageMountpoint"
+	PreflightCheckOADPOperator               = "OADPOperator"
+	PreflightCheckOADPConfigMaps             = "OADPConfigMaps"
+	PreflightCheckBackupStorageLocations     = "BackupStorageLocations"
+	PreflightCheckLocalBackup                = "LocalBackup"
+	PreflightCheckExtraManifests             = "ExtraManifests"
+	PreflightCheckPolicyManifests            = "PolicyManifests"
+	PreflightCheckPostUpgradeValidation      = "PostUpgradeValidation"
+	PreflightCheckSeedImageDigest            = "SeedImageDigest"
+	PreflightCheckSeedCompatibility          = "SeedCompatibility"
+	PreflightCheckContainerStorageDiskUsage  = "ContainerStorageDiskUsage"
+)
+
+// isPreflightRequested checks whether the preflight annotation requests a run that has not been done yet.
+// A preflight only runs while the IBU is Idle.
+func isPreflightRequested(ibu *ibuv1.ImageBasedUpgrade) bool {
+	request, exists := ibu.GetAnnotations()[utils.PreflightAnnotation]
+	if !exists {
+		return false
+	}
+	if ibu.Spec.Stage != ibuv1.Stages.Idle || !utils.IsStageCompleted(ibu, ibuv1.Stages.Idle) {
+		return false
+	}
+	return ibu.Status.Preflight == nil || ibu.Status.Preflight.Request != request
+}
+
+// handlePreflight runs every Prep validation and reports the result of each one in status.preflight.
+//
+// Unlike Prep, the preflight has no side effects on the cluster: it does not create the IBU workspace, the stateroot
+// and precache jobs, the extramanifest warning annotation, the probe backups, the namespaces faked for the extra
+// manifests dryrun, does not remove the stale Backups or run the image cleanup, and only updates status.preflight. All checks are run, even if some of them fail, so that every issue is reported at once.
+func (r *ImageBasedUpgradeReconciler) handlePreflight(ctx context.Context, ibu *ibuv1.ImageBasedUpgrade) (ctrl.Result, error) {
+	request := ibu.GetAnnotations()[utils.PreflightAnnotation]
+	r.Log.Info("Running preflight", "request", request)
+
+	checks := r.runPreflightChecks(ctx, ibu)
+
+	passed := true
+	for _, check := range checks {
+		if !check.Passed {
+			passed = false
+			r.Log.Info("Preflight check failed", "check", check.Name, "message", check.Message)
+		}
+	}
+	r.Log.Info("Preflight completed", "request", request, "passed", passed)
+
+	ibu.Status.Preflight = &ibuv1.PreflightStatus{
+		Request:        request,
+		CompletionTime: metav1.Now(),
+		Passed:         passed,
+		Checks:         checks,
+	}
+	if err := utils.UpdateIBUStatus(ctx, r.Client, ibu); err != nil {
+		return requeueWithError(fmt.Errorf("failed to update preflight status: %w", err))
+	}
+	return doNotRequeue(), nil
+}
+
+// runPreflightChecks runs the Prep validations and returns their results. The IBU spec is validated by the same
+// validateIBUSpec as Prep, and the other checks have no side effects either.
+func (r *ImageBasedUpgradeReconciler) runPreflightChecks(ctx context.Context, ibu *ibuv1.ImageBasedUpgrade) []ibuv1.PreflightCheck {
+	var checks []ibuv1.PreflightCheck
+	addCheck := func(name string, err error) {
+		check := ibuv1.PreflightCheck{Name: name, Passed: err == nil}
+		if err != nil {
+			check.Message = err.Error()
+		}
+		checks = append(checks, check)
+	}
+
+	addCheck(PreflightCheckClusterHealth, CheckHealth(ctx, r.NoncachedClient, r.Log.WithName("HealthCheck")))
+
+	validation := r.validateIBUSpec(ctx, ibu, true)
+	checks = append(checks, validation.checks...)
+
+	if len(ibu.Spec.OADPContent) != 0 {
+		// Prep probes the storage locations with a backup written to the object storage, only their phase is checked
+		addCheck(PreflightCheckBackupStorageLocations,
+			healthcheck.AreBackupStorageLocationsAvailable(ctx, r.NoncachedClient, r.Log.WithName("HealthCheck")))
+	}
+
+	// The IBU workspace is not created by the preflight, so the seed image pull-secret is written to the config dir.
+	// The digest is only resolved to inspect the same seed image as Prep, status.seedImageDigest is set by Prep.
+	seedIbu := ibu.DeepCopy()
+	digest, digestErr := r.resolveSeedImageDigest(ctx, ibu, common.LCAConfigDir)
+	addCheck(PreflightCheckSeedImageDigest, digestErr)
+	ocpVersionCheck, ocpVersionErr := r.getSeedOcpVersionCheck(ctx, ibu)
+	switch {
+	case ocpVersionErr != nil:
+		addCheck(PreflightCheckSeedCompatibility, ocpVersionErr)
+	case digestErr != nil:
+		err := fmt.Errorf("not validated, the seed image digest could not be resolved")
+		if ocpVersionCheck.Verdict == ibuv1.SeedCompatibilityVerdicts.Incompatible {
+			err = fmt.Errorf("%s; %w", ocpVersionCheck.Message, err)
+		}
+		addCheck(PreflightCheckSeedCompatibility, err)
+	default:
+		seedIbu.Status.SeedImageDigest = digest
+		_, err := r.validateSeedImageConfig(ctx, seedIbu, common.LCAConfigDir, ocpVersionCheck)
+		addCheck(PreflightCheckSeedCompatibility, err)
+	}
+
+	diskUsageMsg, diskUsageErr := r.checkContainerStorageDiskUsage(ibu)
+	addCheck(PreflightCheckContainerStorageDiskUsage, diskUsageErr)
+	if diskUsageErr == nil {
+		checks[len(checks)-1].Message = diskUsageMsg
+	}
+
+	return checks
+}
+
+// checkContainerStorageDiskUsage estimates whether Prep would need to clean up container storage. It returns an
+// informational message if the automatic image cleanup would run, and an error if the disk usage exceeds the
+// threshold while the cleanup is disabled.
+func (r *ImageBasedUpgradeReconciler) checkContainerStorageDiskUsage(ibu *ibuv1.ImageBasedUpgrade) (string, error) {
+	thresholdPercent, cleanupEnabled := r.getContainerStorageCleanupThreshold(ibu)
+	if !cleanupEnabled {
+		thresholdPercent = common.ContainerStorageUsageThresholdPercentDefault
+	}
+
+	exceeded, err := r.ImageMgmtClient.CheckDiskUsageAgainstThreshold(thresholdPercent)
+	if err != nil {
+		return "", fmt.Errorf("failed to check container storage disk usage: %w", err)
+	}
+	if !exceeded {
+		return "", nil
+	}
+
+	if !cleanupEnabled {
+		return "", fmt.Errorf("container storage disk usage exceeds %d%% and automatic image cleanup is disabled", thresholdPercent)
+	}
+	return fmt.Sprintf("container storage disk usage exceeds %d%%, unused images will be cleaned up during Prep", thresholdPercent), nil
+}
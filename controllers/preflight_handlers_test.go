@@ -0,0 +1,194 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/go-logr/logr"
+	ibuv1 "github.com/openshift-kni/lifecycle-agent/api/imagebasedupgrade/v1"
+	"github.com/openshift-kni/lifecycle-agent/controllers/utils"
+	"github.com/openshift-kni/lifecycle-agent/internal/backuprestore"
+	mock_backuprestore "github.com/openshift-kni/lifecycle-agent/internal/backuprestore/mocks"
+	"github.com/openshift-kni/lifecycle-agent/internal/extramanifest"
+	mock_extramanifest "github.com/openshift-kni/lifecycle-agent/internal/extramanifest/mocks"
+	"github.com/openshift-kni/lifecycle-agent/internal/imagemgmt"
+	"github.com/openshift-kni/lifecycle-agent/lca-cli/ops"
+	configv1 "github.com/openshift/api/config/v1"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestIsPreflightRequested(t *testing.T) {
+	idleConditions := []metav1.Condition{
+		{Type: string(utils.ConditionTypes.Idle), Status: metav1.ConditionTrue, Reason: string(utils.ConditionReasons.Idle)},
+	}
+
+	testcases := []struct {
+		name        string
+		annotations map[string]string
+		stage       ibuv1.ImageBasedUpgradeStage
+		conditions  []metav1.Condition
+		preflight   *ibuv1.PreflightStatus
+		expected    bool
+	}{
+		{
+			name:       "no annotation",
+			stage:      ibuv1.Stages.Idle,
+			conditions: idleConditions,
+			expected:   false,
+		},
+		{
+			name:        "new request while idle",
+			annotations: map[string]string{utils.PreflightAnnotation: "1"},
+			stage:       ibuv1.Stages.Idle,
+			conditions:  idleConditions,
+			expected:    true,
+		},
+		{
+			name:        "request already run",
+			annotations: map[string]string{utils.PreflightAnnotation: "1"},
+			stage:       ibuv1.Stages.Idle,
+			conditions:  idleConditions,
+			preflight:   &ibuv1.PreflightStatus{Request: "1"},
+			expected:    false,
+		},
+		{
+			name:        "updated request",
+			annotations: map[string]string{utils.PreflightAnnotation: "2"},
+			stage:       ibuv1.Stages.Idle,
+			conditions:  idleConditions,
+			preflight:   &ibuv1.PreflightStatus{Request: "1"},
+			expected:    true,
+		},
+		{
+			name:        "request while upgrade in progress",
+			annotations: map[string]string{utils.PreflightAnnotation: "1"},
+			stage:       ibuv1.Stages.Prep,
+			conditions: []metav1.Condition{
+				{Type: string(utils.ConditionTypes.Idle), Status: metav1.ConditionFalse, Reason: string(utils.ConditionReasons.InProgress)},
+				{Type: string(utils.ConditionTypes.PrepInProgress), Status: metav1.ConditionTrue, Reason: string(utils.ConditionReasons.InProgress)},
+			},
+			expected: false,
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			ibu := &ibuv1.ImageBasedUpgrade{
+				ObjectMeta: metav1.ObjectMeta{Annotations: tc.annotations},
+				Spec:       ibuv1.ImageBasedUpgradeSpec{Stage: tc.stage},
+				Status:     ibuv1.ImageBasedUpgradeStatus{Conditions: tc.conditions, Preflight: tc.preflight},
+			}
+			assert.Equal(t, tc.expected, isPreflightRequested(ibu))
+		})
+	}
+}
+
+func TestImageBasedUpgradeReconciler_handlePreflight(t *testing.T) {
+	mockController := gomock.NewController(t)
+	defer mockController.Finish()
+
+	oldHC := CheckHealth
+	defer func() { CheckHealth = oldHC }()
+	CheckHealth = func(ctx context.Context, c client.Reader, l logr.Logger) error { return fmt.Errorf("node not ready") }
+
+	ibu := &ibuv1.ImageBasedUpgrade{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        utils.IBUName,
+			Annotations: map[string]string{utils.PreflightAnnotation: "rollout-1"},
+		},
+		Spec: ibuv1.ImageBasedUpgradeSpec{
+			Stage:          ibuv1.Stages.Idle,
+			SeedImageRef:   ibuv1.SeedImageRef{Version: "4.15.0", Image: "quay.io/seed:4.15.0"},
+			OADPContent:    []ibuv1.ConfigMapRef{{Name: "oadp", Namespace: "openshift-adp"}},
+			ExtraManifests: []ibuv1.ConfigMapRef{{Name: "extra", Namespace: "openshift-lifecycle-agent"}},
+		},
+		Status: ibuv1.ImageBasedUpgradeStatus{
+			Conditions: []metav1.Condition{
+				{Type: string(utils.ConditionTypes.Idle), Status: metav1.ConditionTrue, Reason: string(utils.ConditionReasons.Idle)},
+			},
+		},
+	}
+	testscheme.AddKnownTypes(configv1.GroupVersion, &configv1.ClusterVersion{})
+	clusterVersion := &configv1.ClusterVersion{
+		ObjectMeta: metav1.ObjectMeta{Name: "version"},
+		Status:     configv1.ClusterVersionStatus{Desired: configv1.Release{Version: "4.14.8"}},
+	}
+	fakeClient, err := getFakeClientFromObjects(ibu, clusterVersion)
+	assert.NoError(t, err)
+
+	mockBackupRestore := mock_backuprestore.NewMockBackuperRestorer(mockController)
+	mockBackupRestore.EXPECT().CheckOadpOperatorAvailability(gomock.Any()).
+		Return(backuprestore.NewBRFailedValidationError("OADP", "Please ensure OADP operator is installed")).Times(1)
+	mockBackupRestore.EXPECT().ValidateOadpConfigmaps(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	mockExtraManifest := mock_extramanifest.NewMockEManifestHandler(mockController)
+	mockExtraManifest.EXPECT().ValidateExtraManifestConfigmaps(gomock.Any(), ibu.Spec.ExtraManifests, false).
+		Return("the extra manifest namespace does not exist", nil, nil).Times(1)
+	mockExtraManifest.EXPECT().ValidateAndExtractManifestFromPolicies(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(nil, nil, nil).Times(1)
+
+	mockExecutor := ops.NewMockExecute(mockController)
+	mockExecutor.EXPECT().Execute("skopeo", gomock.Any()).DoAndReturn(func(command string, args ...string) (string, error) {
+		if slices.Contains(args, "{{.Digest}}") {
+			return "sha256:5b0bcabd1ed22e9fb1310cf6c2dec7cdef19f0ad69efa1f392e94a4333501270", nil
+		}
+		// The labels are inspected using the resolved digest
+		assert.Contains(t, args, "docker://quay.io/seed@sha256:5b0bcabd1ed22e9fb1310cf6c2dec7cdef19f0ad69efa1f392e94a4333501270")
+		return "", fmt.Errorf("unauthorized")
+	}).Times(2)
+
+	mockImageMgmt := imagemgmt.NewMockImageMgmtIntf(mockController)
+	mockImageMgmt.EXPECT().CheckDiskUsageAgainstThreshold(gomock.Any()).Return(true, nil).Times(1)
+	mockImageMgmt.EXPECT().CleanupUnusedImages(gomock.Any()).Times(0)
+
+	r := &ImageBasedUpgradeReconciler{
+		Client:          fakeClient,
+		NoncachedClient: fakeClient,
+		Log:             logr.Discard(),
+		BackupRestore:   mockBackupRestore,
+		ExtraManifest:   mockExtraManifest,
+		Executor:        mockExecutor,
+		ImageMgmtClient: mockImageMgmt,
+	}
+
+	result, err := r.handlePreflight(context.TODO(), ibu)
+	assert.NoError(t, err)
+	assert.True(t, result.IsZero())
+
+	updated := &ibuv1.ImageBasedUpgrade{}
+	assert.NoError(t, fakeClient.Get(context.TODO(), types.NamespacedName{Name: utils.IBUName}, updated))
+	assert.NotContains(t, updated.GetAnnotations(), extramanifest.ValidationWarningAnnotation)
+	assert.NotNil(t, updated.Status.Preflight)
+	assert.Equal(t, "rollout-1", updated.Status.Preflight.Request)
+	assert.False(t, updated.Status.Preflight.Passed)
+
+	results := map[string]bool{}
+	for _, check := range updated.Status.Preflight.Checks {
+		results[check.Name] = check.Passed
+	}
+	assert.Equal(t, map[string]bool{
+		PreflightCheckClusterHealth:              false,
+		PreflightCheckContainerStorageMountpoint: false,
+		PreflightCheckOADPOperator:               false,
+		PreflightCheckOADPConfigMaps:             false,
+		PreflightCheckBackupStorageLocations:     false,
+		PreflightCheckLocalBackup:                true,
+		PreflightCheckExtraManifests:             true,
+		PreflightCheckPolicyManifests:            true,
+		PreflightCheckSeedImageDigest:            true,
+		PreflightCheckSeedCompatibility:          false,
+		PreflightCheckContainerStorageDiskUsage:  true,
+	}, results)
+	assert.False(t, isPreflightRequested(updated))
+	// The seed image digest is only recorded by Prep
+	assert.Empty(t, updated.Status.SeedImageDigest)
+
+	// The status fields set by Prep are left as is
+	assert.Empty(t, updated.Status.SeedCompatibility)
+	assert.Empty(t, updated.Status.SelectedPolicies)
+}
@@ -20,17 +20,22 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net"
 	"os"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/openshift-kni/lifecycle-agent/lca-cli/ops"
 	kbatch "k8s.io/api/batch/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/containers/image/v5/docker/reference"
 	"github.com/coreos/go-semver/semver"
 	ibuv1 "github.com/openshift-kni/lifecycle-agent/api/imagebasedupgrade/v1"
 	"github.com/openshift-kni/lifecycle-agent/controllers/utils"
@@ -39,59 +44,199 @@ import (
 	configv1 "github.com/openshift/api/config/v1"
 	"k8s.io/apimachinery/pkg/types"
 
+	"github.com/openshift-kni/lifecycle-agent/internal/backuprestore"
 	"github.com/openshift-kni/lifecycle-agent/internal/common"
 	"github.com/openshift-kni/lifecycle-agent/internal/extramanifest"
+	"github.com/openshift-kni/lifecycle-agent/internal/imagesignature"
 	"github.com/openshift-kni/lifecycle-agent/internal/precache"
 	"github.com/openshift-kni/lifecycle-agent/internal/prep"
 	corev1 "k8s.io/api/core/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 )
 
+// GetSeedImage pulls the seed image pinned to the digest resolved at the start of Prep, after verifying its signature
+// if spec.seedImageRef.verification is set
 func GetSeedImage(c client.Client, ctx context.Context, ibu *ibuv1.ImageBasedUpgrade, log logr.Logger, ops ops.Execute) error {
-	// Use cluster wide pull-secret by default
-	pullSecretFilename := common.ImageRegistryAuthFile
-
-	if ibu.Spec.SeedImageRef.PullSecretRef != nil {
-		var pullSecret string
-		pullSecret, err := lcautils.GetSecretData(ctx, ibu.Spec.SeedImageRef.PullSecretRef.Name,
-			common.LcaNamespace, corev1.DockerConfigJsonKey, c)
-		if err != nil {
-			err = fmt.Errorf("failed to retrieve pull-secret from secret %s, err: %w", ibu.Spec.SeedImageRef.PullSecretRef.Name, err)
-			return err
-		}
+	seedImage, err := GetPinnedSeedImage(ibu)
+	if err != nil {
+		return err
+	}
 
-		pullSecretFilename = filepath.Join(utils.IBUWorkspacePath, "seed-pull-secret")
-		if err = os.WriteFile(common.PathOutsideChroot(pullSecretFilename), []byte(pullSecret), 0o600); err != nil {
-			err = fmt.Errorf("failed to write seed image pull-secret to file %s, err: %w", pullSecretFilename, err)
+	pullSecretFilename, removePullSecret, err := writeSeedImagePullSecret(ctx, c, ibu, utils.IBUWorkspacePath)
+	if err != nil {
+		return err
+	}
+	defer removePullSecret()
+
+	if ibu.Spec.SeedImageRef.Verification != nil {
+		if _, err := verifySeedImageSignature(ctx, c, ibu, ops, seedImage, pullSecretFilename, utils.IBUWorkspacePath); err != nil {
 			return err
 		}
-		defer func() { _ = os.Remove(common.PathOutsideChroot(pullSecretFilename)) }()
+		log.Info("Successfully verified seed image signature", "image", seedImage)
 	}
 
-	if _, err := ops.Execute("podman", "pull", "--authfile", pullSecretFilename, ibu.Spec.SeedImageRef.Image); err != nil {
+	if _, err := ops.Execute("podman", "pull", "--authfile", pullSecretFilename, seedImage); err != nil {
 		return fmt.Errorf("failed to pull image: %w", err)
 	}
-	log.Info("Successfully pulled seed image", "image", ibu.Spec.SeedImageRef.Image)
+	log.Info("Successfully pulled seed image", "image", seedImage)
 
 	return nil
 }
 
+// GetPinnedSeedImage returns the seed image reference pinned to the digest recorded in status.seedImageDigest
+func GetPinnedSeedImage(ibu *ibuv1.ImageBasedUpgrade) (string, error) {
+	if ibu.Status.SeedImageDigest == "" {
+		return "", fmt.Errorf("the seed image digest has not been resolved")
+	}
+	return withDigest(ibu.Spec.SeedImageRef.Image, ibu.Status.SeedImageDigest)
+}
+
+// writeSeedImagePullSecret writes the pull-secret referenced in spec.seedImageRef.pullSecretRef to dir, and returns
+// the authfile to use along with a func removing it. The cluster wide pull-secret is used if no pull-secret is
+// referenced.
+func writeSeedImagePullSecret(ctx context.Context, c client.Client, ibu *ibuv1.ImageBasedUpgrade, dir string) (string, func(), error) {
+	if ibu.Spec.SeedImageRef.PullSecretRef == nil {
+		return common.ImageRegistryAuthFile, func() {}, nil
+	}
+
+	pullSecret, err := lcautils.GetSecretData(ctx, ibu.Spec.SeedImageRef.PullSecretRef.Name,
+		common.LcaNamespace, corev1.DockerConfigJsonKey, c)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to retrieve pull-secret from secret %s, err: %w", ibu.Spec.SeedImageRef.PullSecretRef.Name, err)
+	}
+
+	pullSecretFilename := filepath.Join(dir, "seed-pull-secret")
+	if err = os.WriteFile(common.PathOutsideChroot(pullSecretFilename), []byte(pullSecret), 0o600); err != nil {
+		return "", nil, fmt.Errorf("failed to write seed image pull-secret to file %s, err: %w", pullSecretFilename, err)
+	}
+	return pullSecretFilename, func() { _ = os.Remove(common.PathOutsideChroot(pullSecretFilename)) }, nil
+}
+
+// resolveSeedImageDigest returns the digest of the manifest the seed image points to, without downloading the image
+// itself. If spec.seedImageRef.verification is set, the signature of the manifest is verified as well. The seed image
+// pull-secret, if any, is temporarily written to pullSecretDir.
+func (r *ImageBasedUpgradeReconciler) resolveSeedImageDigest(ctx context.Context, ibu *ibuv1.ImageBasedUpgrade, pullSecretDir string) (string, error) {
+	pullSecretFilename, removePullSecret, err := writeSeedImagePullSecret(ctx, r.Client, ibu, pullSecretDir)
+	if err != nil {
+		return "", err
+	}
+	defer removePullSecret()
+
+	if ibu.Spec.SeedImageRef.Verification != nil {
+		r.Log.Info("Verifying seed image signature")
+		return verifySeedImageSignature(ctx, r.Client, ibu, r.Executor, ibu.Spec.SeedImageRef.Image, pullSecretFilename, pullSecretDir)
+	}
+
+	// TODO: use the context when execute supports it
+	digest, err := r.Executor.Execute("skopeo", "inspect", "--retry-times", "10", "--authfile", pullSecretFilename,
+		"--no-tags", "--format", "{{.Digest}}", "docker://"+ibu.Spec.SeedImageRef.Image)
+	if err != nil || digest == "" {
+		return "", fmt.Errorf("failed to inspect image: %w", err)
+	}
+	return digest, nil
+}
+
+// checkStaterootSeedImageDigest checks that the new stateroot was set up from the seed image digest recorded in
+// status.seedImageDigest
+func checkStaterootSeedImageDigest(ibu *ibuv1.ImageBasedUpgrade, digestFile string) error {
+	if ibu.Status.SeedImageDigest == "" {
+		// Prep was run by a release that did not pin the seed image digest
+		return nil
+	}
+
+	content, err := os.ReadFile(digestFile)
+	if err != nil {
+		return fmt.Errorf("failed to read the seed image digest of the new stateroot: %w", err)
+	}
+	if digest := strings.TrimSpace(string(content)); digest != ibu.Status.SeedImageDigest {
+		return fmt.Errorf("the new stateroot was set up from seed image digest %s but status.seedImageDigest is %s",
+			digest, ibu.Status.SeedImageDigest)
+	}
+	return nil
+}
+
+// Names of the seed image compatibility checks reported in status.seedCompatibility
+const (
+	SeedCompatibilityCheckFormatVersion         = "SeedFormatVersion"
+	SeedCompatibilityCheckOcpVersion            = "OcpVersion"
+	SeedCompatibilityCheckProxy                 = "Proxy"
+	SeedCompatibilityCheckFIPS                  = "FIPS"
+	SeedCompatibilityCheckAdditionalTrustBundle = "AdditionalTrustBundle"
+	SeedCompatibilityCheckContainerStorage      = "ContainerStorage"
+	SeedCompatibilityCheckClusterNetwork        = "ClusterNetwork"
+	SeedCompatibilityCheckServiceNetwork        = "ServiceNetwork"
+)
+
 // validateSeedImageConfig retrieves the labels for the seed image without downloading the image itself, then validates
-// the config data from the labels
-func (r *ImageBasedUpgradeReconciler) validateSeedImageConfig(ctx context.Context, ibu *ibuv1.ImageBasedUpgrade) error {
-	labels, err := r.getLabelsForSeedImage(ctx, ibu)
+// the config data from the labels. The seed image pull-secret, if any, is temporarily written to pullSecretDir.
+//
+// Every compatibility check is run and returned, so that all the mismatches are reported at once. The seed image OCP
+// version, checked earlier by getSeedOcpVersionCheck, is reported as the first check.
+func (r *ImageBasedUpgradeReconciler) validateSeedImageConfig(ctx context.Context, ibu *ibuv1.ImageBasedUpgrade, pullSecretDir string,
+	ocpVersionCheck ibuv1.SeedCompatibilityCheck) ([]ibuv1.SeedCompatibilityCheck, error) {
+	report, err := r.getSeedCompatibilityReport(ctx, ibu, pullSecretDir, ocpVersionCheck)
 	if err != nil {
-		return fmt.Errorf("failed to get seed image labels: %w", err)
+		return report, err
 	}
 
-	r.Log.Info("Checking seed image version compatibility")
-	if err := checkSeedImageVersionCompatibility(labels); err != nil {
-		return fmt.Errorf("checking seed image compatibility: %w", err)
+	var mismatches []string
+	for _, check := range report {
+		if check.Verdict == ibuv1.SeedCompatibilityVerdicts.Incompatible {
+			mismatches = append(mismatches, check.Message)
+		}
+	}
+	if len(mismatches) != 0 {
+		return report, fmt.Errorf("checking seed image compatibility: %s", strings.Join(mismatches, "; "))
 	}
 
+	return report, nil
+}
+
+// newSeedCompatibilityCheck returns the result of a check from the error returned by its compatibility helper
+func newSeedCompatibilityCheck(name, seedValue, clusterValue string, err error) ibuv1.SeedCompatibilityCheck {
+	check := ibuv1.SeedCompatibilityCheck{
+		Name:         name,
+		SeedValue:    seedValue,
+		ClusterValue: clusterValue,
+		Verdict:      ibuv1.SeedCompatibilityVerdicts.Compatible,
+	}
+	if err != nil {
+		check.Verdict = ibuv1.SeedCompatibilityVerdicts.Incompatible
+		check.Message = err.Error()
+	}
+	return check
+}
+
+// getSeedOcpVersionCheck checks the seed image OCP version from the IBU spec against the cluster OCP version. It is
+// checked before the seed image is inspected, and reported along with the other seed image compatibility checks.
+func (r *ImageBasedUpgradeReconciler) getSeedOcpVersionCheck(ctx context.Context, ibu *ibuv1.ImageBasedUpgrade) (ibuv1.SeedCompatibilityCheck, error) {
+	clusterOcpVersion, err := r.getClusterOcpVersion(ctx)
+	if err != nil {
+		return ibuv1.SeedCompatibilityCheck{}, err
+	}
+	return newSeedCompatibilityCheck(SeedCompatibilityCheckOcpVersion, ibu.Spec.SeedImageRef.Version, clusterOcpVersion,
+		checkSeedOcpVersionCompatibility(ibu.Spec.SeedImageRef.Version, clusterOcpVersion)), nil
+}
+
+// getSeedCompatibilityReport runs every seed image compatibility check and returns their results, after the given
+// OCP version check. An error is returned if the seed or cluster values could not be retrieved, along with the checks
+// run so far.
+func (r *ImageBasedUpgradeReconciler) getSeedCompatibilityReport(ctx context.Context, ibu *ibuv1.ImageBasedUpgrade, pullSecretDir string,
+	ocpVersionCheck ibuv1.SeedCompatibilityCheck) ([]ibuv1.SeedCompatibilityCheck, error) {
+	report := []ibuv1.SeedCompatibilityCheck{ocpVersionCheck}
+
+	labels, err := r.getLabelsForSeedImage(ctx, ibu, pullSecretDir)
+	if err != nil {
+		return report, fmt.Errorf("failed to get seed image labels: %w", err)
+	}
+
+	r.Log.Info("Checking seed image version compatibility")
+	report = append(report, newSeedCompatibilityCheck(SeedCompatibilityCheckFormatVersion, labels[common.SeedFormatOCILabel],
+		fmt.Sprintf("%d", common.SeedFormatVersion), checkSeedImageVersionCompatibility(labels)))
+
 	seedInfo, err := getSeedConfigFromLabel(labels)
 	if err != nil {
-		return fmt.Errorf("failed to get seed cluster info from label: %w", err)
+		return report, fmt.Errorf("failed to get seed cluster info from label: %w", err)
 	}
 
 	seedHasProxy := false
@@ -112,50 +257,105 @@ func (r *ImageBasedUpgradeReconciler) validateSeedImageConfig(ctx context.Contex
 
 	clusterHasProxy, err := lcautils.HasProxy(ctx, r.Client)
 	if err != nil {
-		return fmt.Errorf("failed to check if cluster has proxy: %w", err)
+		return report, fmt.Errorf("failed to check if cluster has proxy: %w", err)
 	}
 
 	r.Log.Info("Checking seed image proxy compatibility")
-	if err := checkSeedImageProxyCompatibility(seedHasProxy, clusterHasProxy); err != nil {
-		return fmt.Errorf("checking seed image compatibility: %w", err)
-	}
+	report = append(report, newSeedCompatibilityCheck(SeedCompatibilityCheckProxy, strconv.FormatBool(seedHasProxy), strconv.FormatBool(clusterHasProxy),
+		checkSeedImageProxyCompatibility(seedHasProxy, clusterHasProxy)))
 
 	clusterHasFIPS, err := lcautils.HasFIPS(ctx, r.Client)
 	if err != nil {
-		return fmt.Errorf("failed to check if cluster has fips: %w", err)
+		return report, fmt.Errorf("failed to check if cluster has fips: %w", err)
 	}
 
 	r.Log.Info("Checking seed image FIPS compatibility")
-	if err := checkSeedImageFIPSCompatibility(seedHasFIPS, clusterHasFIPS); err != nil {
-		return fmt.Errorf("checking seed image compatibility: %w", err)
-	}
+	report = append(report, newSeedCompatibilityCheck(SeedCompatibilityCheckFIPS, strconv.FormatBool(seedHasFIPS), strconv.FormatBool(clusterHasFIPS),
+		checkSeedImageFIPSCompatibility(seedHasFIPS, clusterHasFIPS)))
 
 	hasUserCaBundle, proxyConfigmapName, err := lcautils.GetClusterAdditionalTrustBundleState(ctx, r.Client)
 	if err != nil {
-		return fmt.Errorf("failed to get cluster additional trust bundle state: %w", err)
+		return report, fmt.Errorf("failed to get cluster additional trust bundle state: %w", err)
 	}
 
-	//nolint:staticcheck //lint:ignore SA9003 // else branch intentionally left empty
+	clusterTrustBundleValue := formatAdditionalTrustBundleState(hasUserCaBundle, proxyConfigmapName)
 	if seedInfo != nil && seedInfo.AdditionalTrustBundle != nil {
-		if err := checkSeedImageAdditionalTrustBundleCompatibility(*seedInfo.AdditionalTrustBundle, hasUserCaBundle, proxyConfigmapName); err != nil {
-			return fmt.Errorf("checking seed image additional trust bundle compatibility: %w", err)
-		}
+		r.Log.Info("Checking seed image additional trust bundle compatibility")
+		report = append(report, newSeedCompatibilityCheck(SeedCompatibilityCheckAdditionalTrustBundle,
+			formatAdditionalTrustBundleState(seedInfo.AdditionalTrustBundle.HasUserCaBundle, seedInfo.AdditionalTrustBundle.ProxyConfigmapName),
+			clusterTrustBundleValue,
+			checkSeedImageAdditionalTrustBundleCompatibility(*seedInfo.AdditionalTrustBundle, hasUserCaBundle, proxyConfigmapName)))
 	} else {
 		// For the sake of backwards compatibility, we allow older seed images
 		// that don't have information about the additional trust bundle. This
 		// means that upgrade will fail at the recert stage if there's a
 		// mismatch between the seed and the seed reconfiguration data.
+		report = append(report, ibuv1.SeedCompatibilityCheck{
+			Name:         SeedCompatibilityCheckAdditionalTrustBundle,
+			ClusterValue: clusterTrustBundleValue,
+			Verdict:      ibuv1.SeedCompatibilityVerdicts.Skipped,
+			Message:      "seed image does not include the additional trust bundle information",
+		})
 	}
 
 	if seedInfo != nil && seedInfo.ContainerStorageMountpointTarget != "" {
 		// Seed image data includes the container storage mountpoint target, so we can compare to running cluster
 		r.Log.Info("Checking seed image container storage compatibility")
-		if err := checkSeedImageContainerStorageCompatibility(seedInfo.ContainerStorageMountpointTarget, r.ContainerStorageMountpointTarget); err != nil {
-			return fmt.Errorf("checking seed image compatibility: %w", err)
+		report = append(report, newSeedCompatibilityCheck(SeedCompatibilityCheckContainerStorage,
+			seedInfo.ContainerStorageMountpointTarget, r.ContainerStorageMountpointTarget,
+			checkSeedImageContainerStorageCompatibility(seedInfo.ContainerStorageMountpointTarget, r.ContainerStorageMountpointTarget)))
+	} else {
+		report = append(report, ibuv1.SeedCompatibilityCheck{
+			Name:         SeedCompatibilityCheckContainerStorage,
+			ClusterValue: r.ContainerStorageMountpointTarget,
+			Verdict:      ibuv1.SeedCompatibilityVerdicts.Skipped,
+			Message:      "seed image does not include the container storage mountpoint target",
+		})
+	}
+
+	clusterNetworks, serviceNetworks, err := lcautils.GetClusterNetworks(ctx, r.Client)
+	if err != nil {
+		return report, fmt.Errorf("failed to get cluster networks: %w", err)
+	}
+
+	var seedClusterNetworks, seedServiceNetworks []string
+	if seedInfo != nil {
+		seedClusterNetworks = seedInfo.ClusterNetworks
+		seedServiceNetworks = seedInfo.ServiceNetworks
+	}
+
+	// Recert does not support changing the cluster and service networks, so they must match the seed image
+	r.Log.Info("Checking seed image cluster and service networks compatibility")
+	for _, network := range []struct {
+		name            string
+		seedNetworks    []string
+		clusterNetworks []string
+	}{
+		{name: SeedCompatibilityCheckClusterNetwork, seedNetworks: seedClusterNetworks, clusterNetworks: clusterNetworks},
+		{name: SeedCompatibilityCheckServiceNetwork, seedNetworks: seedServiceNetworks, clusterNetworks: serviceNetworks},
+	} {
+		if len(network.seedNetworks) == 0 {
+			// Older seed images do not include the cluster and service networks
+			report = append(report, ibuv1.SeedCompatibilityCheck{
+				Name:         network.name,
+				ClusterValue: strings.Join(network.clusterNetworks, ","),
+				Verdict:      ibuv1.SeedCompatibilityVerdicts.Skipped,
+				Message:      "seed image does not include the networks",
+			})
+			continue
 		}
+
+		report = append(report, newSeedCompatibilityCheck(network.name,
+			strings.Join(network.seedNetworks, ","), strings.Join(network.clusterNetworks, ","),
+			checkSeedImageNetworkCompatibility(network.name, network.seedNetworks, network.clusterNetworks)))
 	}
 
-	return nil
+	return report, nil
+}
+
+// formatAdditionalTrustBundleState formats the additional trust bundle state for status.seedCompatibility
+func formatAdditionalTrustBundleState(hasUserCaBundle bool, proxyConfigmapName string) string {
+	return fmt.Sprintf("hasUserCaBundle=%t, proxyConfigmapName=%q", hasUserCaBundle, proxyConfigmapName)
 }
 
 func getSeedConfigFromLabel(labels map[string]string) (*seedclusterinfo.SeedClusterInfo, error) {
@@ -172,34 +372,29 @@ func getSeedConfigFromLabel(labels map[string]string) (*seedclusterinfo.SeedClus
 	return &seedInfo, nil
 }
 
-// getLabelsForSeedImage uses skopeo inspect to retrieve the labels for the seed image without downloading the image itself
-func (r *ImageBasedUpgradeReconciler) getLabelsForSeedImage(ctx context.Context, ibu *ibuv1.ImageBasedUpgrade) (map[string]string, error) {
-	// Use cluster wide pull-secret by default
-	pullSecretFilename := common.ImageRegistryAuthFile
-
-	if ibu.Spec.SeedImageRef.PullSecretRef != nil {
-		var pullSecret string
-		pullSecret, err := lcautils.GetSecretData(ctx, ibu.Spec.SeedImageRef.PullSecretRef.Name,
-			common.LcaNamespace, corev1.DockerConfigJsonKey, r.Client)
-		if err != nil {
-			err = fmt.Errorf("failed to retrieve pull-secret from secret %s, err: %w", ibu.Spec.SeedImageRef.PullSecretRef.Name, err)
+// getLabelsForSeedImage uses skopeo inspect to retrieve the labels for the seed image without downloading the image itself.
+// The seed image pinned to status.seedImageDigest is inspected if the digest has been resolved.
+func (r *ImageBasedUpgradeReconciler) getLabelsForSeedImage(ctx context.Context, ibu *ibuv1.ImageBasedUpgrade, pullSecretDir string) (map[string]string, error) {
+	image := ibu.Spec.SeedImageRef.Image
+	if ibu.Status.SeedImageDigest != "" {
+		var err error
+		if image, err = GetPinnedSeedImage(ibu); err != nil {
 			return nil, err
 		}
+	}
 
-		pullSecretFilename = filepath.Join(utils.IBUWorkspacePath, "seed-pull-secret")
-		if err = os.WriteFile(common.PathOutsideChroot(pullSecretFilename), []byte(pullSecret), 0o600); err != nil {
-			err = fmt.Errorf("failed to write seed image pull-secret to file %s, err: %w", pullSecretFilename, err)
-			return nil, err
-		}
-		defer func() { _ = os.Remove(common.PathOutsideChroot(pullSecretFilename)) }()
+	pullSecretFilename, removePullSecret, err := writeSeedImagePullSecret(ctx, r.Client, ibu, pullSecretDir)
+	if err != nil {
+		return nil, err
 	}
+	defer removePullSecret()
 
 	inspectArgs := []string{
 		"inspect",
 		"--retry-times", "10",
 		"--authfile", pullSecretFilename,
 		"--format", "json",
-		"docker://" + ibu.Spec.SeedImageRef.Image,
+		"docker://" + image,
 	}
 
 	var inspect struct {
@@ -218,6 +413,42 @@ func (r *ImageBasedUpgradeReconciler) getLabelsForSeedImage(ctx context.Context,
 	return inspect.Labels, nil
 }
 
+// verifySeedImageSignature verifies the signature of the seed image with the public keys referenced in
+// spec.seedImageRef.verification, and returns the digest of the verified seed image manifest. The signature is
+// temporarily stored in workDir. An imagesignature.VerificationError is returned if the signature cannot be verified.
+func verifySeedImageSignature(ctx context.Context, c client.Client, ibu *ibuv1.ImageBasedUpgrade, executor ops.Execute, image, authFile, workDir string) (string, error) {
+	secretName := ibu.Spec.SeedImageRef.Verification.PublicKeysRef.Name
+	secret := &corev1.Secret{}
+	if err := c.Get(ctx, types.NamespacedName{Name: secretName, Namespace: common.LcaNamespace}, secret); err != nil {
+		return "", fmt.Errorf("failed to get public keys secret %s: %w", secretName, err)
+	}
+
+	var keysData []byte
+	for _, data := range secret.Data {
+		keysData = append(keysData, data...)
+		keysData = append(keysData, '\n')
+	}
+	publicKeys, err := imagesignature.ParsePublicKeys(keysData)
+	if err != nil {
+		return "", fmt.Errorf("failed to get public keys from secret %s: %w", secretName, err)
+	}
+
+	digest, err := imagesignature.Verify(executor, image, authFile, workDir, publicKeys)
+	if err != nil {
+		return "", fmt.Errorf("failed to verify seed image signature: %w", err)
+	}
+	return digest, nil
+}
+
+// withDigest returns the image reference pinned to the manifest digest, without its tag
+func withDigest(image, digest string) (string, error) {
+	named, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse image %s: %w", image, err)
+	}
+	return reference.TrimNamed(named).String() + "@" + digest, nil
+}
+
 // checkSeedImageVersionCompatibility checks if the seed image is compatible with the
 // current version of the lifecycle-agent by inspecting the OCI image's labels
 // and checking if the specified format version equals the hard-coded one that
@@ -298,6 +529,29 @@ func checkSeedImageContainerStorageCompatibility(seedContainerStorageMountpoint,
 	return nil
 }
 
+// checkSeedImageNetworkCompatibility checks that the seed image networks, either the cluster or the service
+// networks, are the same as the ones of the cluster being upgraded, in the same order. The networks are not changed
+// by recert, so the upgraded cluster would keep the seed networks and fail after the reboot.
+func checkSeedImageNetworkCompatibility(networkName string, seedNetworks, clusterNetworks []string) error {
+	normalize := func(cidrs []string) []string {
+		normalized := make([]string, 0, len(cidrs))
+		for _, cidr := range cidrs {
+			if _, ipNet, err := net.ParseCIDR(cidr); err == nil {
+				cidr = ipNet.String()
+			}
+			normalized = append(normalized, cidr)
+		}
+		return normalized
+	}
+
+	if !slices.Equal(normalize(seedNetworks), normalize(clusterNetworks)) {
+		return fmt.Errorf("%s mismatch: seed=%s, cluster=%s, changing the networks is not supported",
+			networkName, strings.Join(seedNetworks, ","), strings.Join(clusterNetworks, ","))
+	}
+
+	return nil
+}
+
 // checkSeedImageFIPSCompatibility checks for FIPS configuration compatibility
 // of the seed image vs the current cluster. If the seed image has FIPS enabled
 // and the cluster being upgraded doesn't, we cannot proceed as recert does not
@@ -315,15 +569,17 @@ func checkSeedImageFIPSCompatibility(seedHasFIPS, hasFIPS bool) error {
 	return nil
 }
 
-// validateSeedOcpVersion rejects upgrade request if seed image version is not higher than current cluster (target) OCP version
-func (r *ImageBasedUpgradeReconciler) validateSeedOcpVersion(seedOcpVersion string) error {
-	// get target OCP version
+// getClusterOcpVersion returns the OCP version of the current cluster (target)
+func (r *ImageBasedUpgradeReconciler) getClusterOcpVersion(ctx context.Context) (string, error) {
 	targetClusterVersion := &configv1.ClusterVersion{}
-	if err := r.Get(context.Background(), types.NamespacedName{Name: "version"}, targetClusterVersion); err != nil {
-		return fmt.Errorf("failed to get ClusterVersion for target: %w", err)
+	if err := r.Get(ctx, types.NamespacedName{Name: "version"}, targetClusterVersion); err != nil {
+		return "", fmt.Errorf("failed to get ClusterVersion for target: %w", err)
 	}
-	targetOCP := targetClusterVersion.Status.Desired.Version
+	return targetClusterVersion.Status.Desired.Version, nil
+}
 
+// checkSeedOcpVersionCompatibility checks that the seed image version is higher than the current cluster (target) OCP version
+func checkSeedOcpVersionCompatibility(seedOcpVersion, targetOCP string) error {
 	// parse versions
 	targetSemVer, err := semver.NewVersion(targetOCP)
 	if err != nil {
@@ -339,7 +595,6 @@ func (r *ImageBasedUpgradeReconciler) validateSeedOcpVersion(seedOcpVersion stri
 		return fmt.Errorf("seed OCP version (%s) must be higher than current OCP version (%s)", seedOcpVersion, targetOCP)
 	}
 
-	r.Log.Info("OCP versions are validated", "seed", seedOcpVersion, "target", targetOCP)
 	return nil
 }
 
@@ -426,57 +681,146 @@ func getSeedManifestPath(osname string) string {
 	)
 }
 
-// validateIBUSpec validates the fields in the IBU spec
-func (r *ImageBasedUpgradeReconciler) validateIBUSpec(ctx context.Context, ibu *ibuv1.ImageBasedUpgrade) error {
-	// Check spec against this cluster's version and possibly exit early
-	if err := r.validateSeedOcpVersion(ibu.Spec.SeedImageRef.Version); err != nil {
-		return fmt.Errorf("failed to validate seed image OCP version: %w", err)
-	}
+// specValidation is the outcome of validateIBUSpec
+type specValidation struct {
+	// checks are the results of the validations, in the order they are run
+	checks []ibuv1.PreflightCheck
+	// err is the error of the first failed validation
+	err error
+
+	// extraManifestsWarning is the warning of the extramanifests validation, e.g. for a manifest whose CRD is missing
+	extraManifestsWarning string
+	// manifestDiffs are the changes the extra manifests make to the cluster, computed with dryrun
+	manifestDiffs []extramanifest.ManifestDiff
+	// policyManifests are the manifests extracted from the selected policies, grouped by policy
+	policyManifests  [][]*unstructured.Unstructured
+	selectedPolicies []ibuv1.SelectedPolicy
+	// backupEstimate is the estimate of the backups, nil if they could not be estimated
+	backupEstimate *ibuv1.BackupEstimateStatus
+}
 
-	// If OADP configmap is provided, check if OADP operator is available, validate the configmap
-	// and remove stale Backups from object storage if any.
-	if len(ibu.Spec.OADPContent) != 0 {
-		err := r.BackupRestore.CheckOadpOperatorAvailability(ctx)
-		if err != nil {
-			return fmt.Errorf("failed to check oadp operator availability: %w", err)
+// add records the result of a validation. The message is reported if the validation passed, and the error is
+// prefixed with errPrefix otherwise.
+func (v *specValidation) add(name string, err error, errPrefix, message string) {
+	check := ibuv1.PreflightCheck{Name: name, Passed: err == nil, Message: message}
+	if err != nil {
+		if errPrefix != "" {
+			err = fmt.Errorf("%s: %w", errPrefix, err)
+		}
+		check.Message = err.Error()
+		if v.err == nil {
+			v.err = err
 		}
+	}
+	v.checks = append(v.checks, check)
+}
+
+// validateIBUSpec validates the fields in the IBU spec against the cluster. It is shared by Prep and the preflight,
+// and does not update the IBU. Every validation is run, even if some of them fail, so that the preflight reports every
+// issue at once. For Prep, the stale Backups are removed from the object storage and the namespaces created by the
+// extra manifests are faked for the dryrun; the preflight skips these side effects on the cluster.
+// The seed image is validated separately, see validateSeedImageConfig.
+func (r *ImageBasedUpgradeReconciler) validateIBUSpec(ctx context.Context, ibu *ibuv1.ImageBasedUpgrade, preflight bool) *specValidation {
+	v := &specValidation{}
+
+	var mountpointErr error
+	if r.ContainerStorageMountpointTarget == "" {
+		mountpointErr = fmt.Errorf("container storage mountpoint target not found")
+	}
+	v.add(PreflightCheckContainerStorageMountpoint, mountpointErr, "", "")
 
-		err = r.BackupRestore.ValidateOadpConfigmaps(ctx, ibu.Spec.OADPContent)
-		if err != nil {
-			return fmt.Errorf("failed to validate oadp configMap: %w", err)
+	// If OADP configmap is provided, check if OADP operator is available and validate the configmap
+	if len(ibu.Spec.OADPContent) != 0 {
+		oadpErr := r.BackupRestore.CheckOadpOperatorAvailability(ctx)
+		v.add(PreflightCheckOADPOperator, oadpErr, "failed to check oadp operator availability", "")
+		if oadpErr != nil {
+			// The Backup and Restore CRs cannot be validated without OADP
+			v.add(PreflightCheckOADPConfigMaps, fmt.Errorf("not validated, the OADP operator is not available"), "", "")
+		} else {
+			err := r.BackupRestore.ValidateOadpConfigmaps(ctx, ibu.Spec.OADPContent, !preflight)
+			v.add(PreflightCheckOADPConfigMaps, err, "failed to validate oadp configMap", "")
+			if err == nil {
+				// The estimate is informative only, the validation passes without it
+				estimate, err := r.BackupRestore.EstimateBackups(ctx, ibu.Spec.OADPContent)
+				if err != nil {
+					r.Log.Error(err, "failed to estimate backups")
+				} else if estimate != nil {
+					v.checks[len(v.checks)-1].Message = fmt.Sprintf("estimated backups: %d resources, %d volumes of %s, in about %s",
+						estimate.Resources, estimate.Volumes, estimate.VolumeSize.String(), estimate.Duration.Duration)
+				}
+				v.backupEstimate = estimate
+			}
 		}
 	}
 
+	v.add(PreflightCheckLocalBackup, backuprestore.ValidateLocalBackup(ibu.Spec.LocalBackup), "failed to validate localBackup", "")
+
 	// Validate the extraManifests configmap if it's provided
 	if len(ibu.Spec.ExtraManifests) != 0 {
-		warn, err := r.ExtraManifest.ValidateExtraManifestConfigmaps(ctx, ibu.Spec.ExtraManifests)
-		if err != nil {
-			return fmt.Errorf("failed to validate extramanifest cms: %w", err)
-		}
-		if warn != "" {
-			r.Log.Info(fmt.Sprintf("Adding IBU annotation '%s' with the extramanifest validation warning", extramanifest.ValidationWarningAnnotation))
-			if err := extramanifest.AddAnnotationEMWarningValidation(r.Client, r.Log, ibu, warn); err != nil {
-				return fmt.Errorf("failed to add extramanifest warning validation annotation: %w", err)
-			}
+		warn, diffs, err := r.ExtraManifest.ValidateExtraManifestConfigmaps(ctx, ibu.Spec.ExtraManifests, !preflight)
+		v.add(PreflightCheckExtraManifests, err, "failed to validate extramanifest cms", warn)
+		if err == nil {
+			v.extraManifestsWarning = warn
+			v.manifestDiffs = diffs
 		}
 	}
 
-	// Validate the manifests from policies if related annotations are specified
+	policyManifests, selectedPolicies, err := r.validatePolicyManifests(ctx, ibu)
+	v.add(PreflightCheckPolicyManifests, err, "", "")
+	if err == nil {
+		v.policyManifests = policyManifests
+		v.selectedPolicies = selectedPolicies
+	}
+
+	if len(ibu.Spec.PostUpgradeValidation) != 0 {
+		_, err := getPostUpgradeValidationJobs(ctx, r.Client, ibu.Spec.PostUpgradeValidation)
+		v.add(PreflightCheckPostUpgradeValidation, err, "failed to validate postUpgradeValidation", "")
+	}
+	return v
+}
+
+// validatePolicyManifests validates the manifests from policies, checking their count if the related annotation is
+// specified and the deletions of the mustnothave templates with dryrun. It returns the manifests grouped by policy,
+// along with the selected policies.
+func (r *ImageBasedUpgradeReconciler) validatePolicyManifests(ctx context.Context, ibu *ibuv1.ImageBasedUpgrade) ([][]*unstructured.Unstructured, []ibuv1.SelectedPolicy, error) {
 	var validationAnns = map[string]string{}
 	if count, exists := ibu.GetAnnotations()[extramanifest.TargetOcpVersionManifestCountAnnotation]; exists {
 		validationAnns[extramanifest.TargetOcpVersionManifestCountAnnotation] = count
 	}
-	if len(validationAnns) != 0 {
-		versions, err := extramanifest.GetMatchingTargetOcpVersionLabelVersions(ibu.Spec.SeedImageRef.Version)
-		if err != nil {
-			return fmt.Errorf("failed to get matching versions for target-ocp-version label: %w", err)
-		}
 
-		objectLabels := map[string]string{extramanifest.TargetOcpVersionLabel: strings.Join(versions, ",")}
-		if _, err := r.ExtraManifest.ValidateAndExtractManifestFromPolicies(ctx, nil, objectLabels, validationAnns); err != nil {
-			return fmt.Errorf("failed to validate manifests from policies: %w", err)
+	versions, err := extramanifest.GetMatchingTargetOcpVersionLabelVersions(ibu.Spec.SeedImageRef.Version)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get matching versions for target-ocp-version label: %w", err)
+	}
+
+	objectLabels := map[string]string{extramanifest.TargetOcpVersionLabel: strings.Join(versions, ",")}
+	manifests, selectedPolicies, err := r.ExtraManifest.ValidateAndExtractManifestFromPolicies(ctx, nil, objectLabels, validationAnns)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to validate manifests from policies: %w", err)
+	}
+	return manifests, selectedPolicies, nil
+}
+
+// applySpecValidation records the outcome of the IBU spec validation in the IBU: the extramanifest warning
+// annotation, the selected policies, the backup estimate and the extra manifests diff
+func (r *ImageBasedUpgradeReconciler) applySpecValidation(ctx context.Context, ibu *ibuv1.ImageBasedUpgrade, v *specValidation) error {
+	if v.extraManifestsWarning != "" {
+		r.Log.Info(fmt.Sprintf("Adding IBU annotation '%s' with the extramanifest validation warning", extramanifest.ValidationWarningAnnotation))
+		if err := extramanifest.AddAnnotationEMWarningValidation(r.Client, r.Log, ibu, v.extraManifestsWarning); err != nil {
+			return fmt.Errorf("failed to add extramanifest warning validation annotation: %w", err)
 		}
 	}
+
+	ibu.Status.SelectedPolicies = v.selectedPolicies
+	ibu.Status.BackupEstimate = v.backupEstimate
+
+	// The diff is informative only, the Prep stage goes on without it
+	policyDiffs, err := r.ExtraManifest.DiffPolicyManifests(ctx, v.policyManifests, v.selectedPolicies)
+	if err != nil {
+		r.Log.Error(err, "failed to diff manifests from policies")
+	} else if err := r.reportExtraManifestsDiff(ctx, ibu, append(v.manifestDiffs, policyDiffs...)); err != nil {
+		r.Log.Error(err, "failed to report extra manifests diff")
+	}
 	return nil
 }
 
@@ -495,13 +839,14 @@ func initIBUWorkspaceDir() error {
 	return nil
 }
 
-// Cleanup container storage, if needed
-func (r *ImageBasedUpgradeReconciler) containerStorageCleanup(ibu *ibuv1.ImageBasedUpgrade) error {
+// getContainerStorageCleanupThreshold returns the container storage disk usage threshold for the automatic image
+// cleanup, or false if the cleanup is disabled
+func (r *ImageBasedUpgradeReconciler) getContainerStorageCleanupThreshold(ibu *ibuv1.ImageBasedUpgrade) (int, bool) {
 	// Check whether image cleanup is disabled using annotation
 	if val, exists := ibu.GetAnnotations()[common.ImageCleanupOnPrepAnnotation]; exists {
 		if val == common.ImageCleanupDisabledValue {
 			r.Log.Info("Automatic image cleanup is disabled")
-			return nil
+			return 0, false
 		}
 	}
 
@@ -513,10 +858,20 @@ func (r *ImageBasedUpgradeReconciler) containerStorageCleanup(ibu *ibuv1.ImageBa
 		if thresholdPercent, err = strconv.Atoi(val); err != nil {
 			r.Log.Error(err, "Failed to parse threshold value from annotation", "value", val)
 			r.Log.Info("Automatic image cleanup is disabled, due to failure to parse threshold annotation")
-			return nil
+			return 0, false
 		}
 	}
 
+	return thresholdPercent, true
+}
+
+// Cleanup container storage, if needed
+func (r *ImageBasedUpgradeReconciler) containerStorageCleanup(ibu *ibuv1.ImageBasedUpgrade) error {
+	thresholdPercent, enabled := r.getContainerStorageCleanupThreshold(ibu)
+	if !enabled {
+		return nil
+	}
+
 	// Check container storage disk usage
 	if exceeded, err := r.ImageMgmtClient.CheckDiskUsageAgainstThreshold(thresholdPercent); err != nil {
 		return fmt.Errorf("failed to check container storage disk usage: %w", err)
@@ -541,6 +896,10 @@ var (
 
 // handlePrep the main func to run prep stage
 func (r *ImageBasedUpgradeReconciler) handlePrep(ctx context.Context, ibu *ibuv1.ImageBasedUpgrade) (ctrl.Result, error) {
+	if msg := getPrepExpiredTimeout(ibu, time.Now()); msg != "" {
+		return prepFailWithReasonDoNotRequeue(r.Log, utils.ConditionReasons.TimedOut, msg, ibu)
+	}
+
 	r.Log.Info("Running health check for Prep")
 	if err := CheckHealth(ctx, r.NoncachedClient, r.Log.WithName("HealthCheck")); err != nil {
 		msg := fmt.Sprintf("Waiting for system to stabilize before Prep stage can continue: %s", err.Error())
@@ -554,14 +913,41 @@ func (r *ImageBasedUpgradeReconciler) handlePrep(ctx context.Context, ibu *ibuv1
 	staterootSetupJob, err := prep.GetStaterootSetupJob(ctx, r.Client, r.Log)
 	if err != nil {
 		if k8serrors.IsNotFound(err) {
-			// Ensure there is a container storage mountpoint, required for shared storage between stateroots for IBU
-			if r.ContainerStorageMountpointTarget == "" {
-				return prepFailDoNotRequeue(r.Log, "container storage mountpoint target not found", ibu)
+			// Check the seed image OCP version first, before the more expensive validations and seed image checks.
+			// A mismatch does not fail Prep yet, it is reported along with the other seed image compatibility checks.
+			r.Log.Info("Checking seed image OCP version")
+			ocpVersionCheck, err := r.getSeedOcpVersionCheck(ctx, ibu)
+			if err != nil {
+				return requeueWithError(err)
 			}
+			ibu.Status.SeedCompatibility = []ibuv1.SeedCompatibilityCheck{ocpVersionCheck}
 
+			// The container storage mountpoint, required for shared storage between stateroots for IBU, is validated
+			// along with the spec
 			r.Log.Info("Validating IBU spec")
-			if err := r.validateIBUSpec(ctx, ibu); err != nil {
-				return prepFailDoNotRequeue(r.Log, fmt.Sprintf("failed to validate IBU spec: %s", err.Error()), ibu)
+			validation := r.validateIBUSpec(ctx, ibu, false)
+			if validation.err != nil {
+				return prepFailDoNotRequeue(r.Log, fmt.Sprintf("failed to validate IBU spec: %s", validation.err.Error()), ibu)
+			}
+			if err := r.applySpecValidation(ctx, ibu, validation); err != nil {
+				return requeueWithError(err)
+			}
+
+			if len(ibu.Spec.OADPContent) != 0 {
+				probed, err := probeBackupStorageLocations(ctx, r.BackupRestore, r.Log, ibu)
+				if err != nil {
+					if backuprestore.IsBRStorageBackendUnavailableError(err) {
+						msg := fmt.Sprintf("Waiting for system to stabilize before Prep stage can continue: %s", err.Error())
+						r.Log.Info(msg)
+						utils.SetPrepStatusInProgress(ibu, msg)
+						return requeueWithHealthCheckInterval(), nil
+					}
+					return requeueWithError(fmt.Errorf("failed to probe BackupStorageLocations: %w", err))
+				}
+				if !probed {
+					utils.SetPrepStatusInProgress(ibu, "Probing BackupStorageLocations")
+					return requeueWithShortInterval(), nil
+				}
 			}
 
 			r.Log.Info("Creating IBU workspace")
@@ -569,10 +955,29 @@ func (r *ImageBasedUpgradeReconciler) handlePrep(ctx context.Context, ibu *ibuv1
 				return prepFailDoNotRequeue(r.Log, fmt.Sprintf("failed to initialize IBU workspace: %s", err.Error()), ibu)
 			}
 
+			// Every later operation on the seed image uses this digest, even if the seed image tag is updated since
+			r.Log.Info("Resolving seed image digest")
+			digest, err := r.resolveSeedImageDigest(ctx, ibu, utils.IBUWorkspacePath)
+			if err != nil {
+				if imagesignature.IsVerificationError(err) {
+					return prepFailWithReasonDoNotRequeue(r.Log, utils.ConditionReasons.SignatureVerificationFailed,
+						fmt.Sprintf("failed to resolve seed image digest: %s", err.Error()), ibu)
+				}
+				return prepFailDoNotRequeue(r.Log, fmt.Sprintf("failed to resolve seed image digest: %s", err.Error()), ibu)
+			}
+			r.Log.Info("Resolved seed image digest", "digest", digest)
+			ibu.Status.SeedImageDigest = digest
+			// The stateroot setup job reads the digest from the IBU status
+			if err := utils.UpdateIBUStatus(ctx, r.Client, ibu); err != nil {
+				return requeueWithError(fmt.Errorf("failed to update seed image digest: %w", err))
+			}
+
 			// Validate config information from the seed image labels, prior to launching the job and downloading the image
 			r.Log.Info("Validating seed information")
-			if err := r.validateSeedImageConfig(ctx, ibu); err != nil {
-				return prepFailDoNotRequeue(r.Log, fmt.Sprintf("failed to validate seed image info: %s", err.Error()), ibu)
+			report, err := r.validateSeedImageConfig(ctx, ibu, utils.IBUWorkspacePath, ocpVersionCheck)
+			ibu.Status.SeedCompatibility = report
+			if err != nil {
+				return prepFailDoNotRequeue(r.Log, fmt.Sprintf("failed to validate seed image info, see status.seedCompatibility for details: %s", err.Error()), ibu)
 			}
 
 			r.Log.Info("Checking container storage disk space")
@@ -616,6 +1021,11 @@ func (r *ImageBasedUpgradeReconciler) handlePrep(ctx context.Context, ibu *ibuv1
 	precacheJob, err := precache.GetJob(ctx, r.Client)
 	if err != nil {
 		if k8serrors.IsNotFound(err) {
+			// The precache list is read from the new stateroot
+			if err := checkStaterootSeedImageDigest(ibu, common.GetStaterootSeedImageDigestFile(ibu)); err != nil {
+				return prepFailDoNotRequeue(r.Log, err.Error(), ibu)
+			}
+
 			r.Log.Info("Launching a new precache job")
 			if err := r.launchPrecaching(ctx, common.ContainersListFilePath, ibu); err != nil {
 				return requeueWithError(fmt.Errorf("failed to launch precaching job: %w", err))
@@ -636,6 +1046,9 @@ func (r *ImageBasedUpgradeReconciler) handlePrep(ctx context.Context, ibu *ibuv1
 
 	// check .status
 	_, precacheFinishedType := common.IsJobFinished(precacheJob)
+	if precacheStatus := precache.GetPrecacheStatus(time.Now()); precacheStatus != nil {
+		ibu.Status.Precache = precacheStatus
+	}
 	switch precacheFinishedType {
 	case "":
 		common.LogPodLogs(precacheJob, r.Log, r.Clientset) // pod logs
@@ -660,6 +1073,13 @@ func prepFailDoNotRequeue(log logr.Logger, msg string, ibu *ibuv1.ImageBasedUpgr
 	return doNotRequeue(), nil
 }
 
+// prepFailWithReasonDoNotRequeue is like prepFailDoNotRequeue, with a dedicated condition reason
+func prepFailWithReasonDoNotRequeue(log logr.Logger, reason utils.ConditionReason, msg string, ibu *ibuv1.ImageBasedUpgrade) (ctrl.Result, error) {
+	log.Error(fmt.Errorf("prep stage failed"), msg, "reason", reason)
+	utils.SetPrepStatusFailedWithReason(ibu, reason, msg)
+	return doNotRequeue(), nil
+}
+
 // prepInProgressRequeue helper function when everything is a success at the end
 func prepSuccessDoNotRequeue(log logr.Logger, ibu *ibuv1.ImageBasedUpgrade) (ctrl.Result, error) {
 	msg := "Prep stage completed successfully"
@@ -1,18 +1,36 @@
 package controllers
 
 import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
 	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/go-logr/logr"
+	ibuv1 "github.com/openshift-kni/lifecycle-agent/api/imagebasedupgrade/v1"
+	"github.com/openshift-kni/lifecycle-agent/internal/common"
+	"github.com/openshift-kni/lifecycle-agent/internal/imagesignature"
+	"github.com/openshift-kni/lifecycle-agent/lca-cli/ops"
+	"github.com/openshift-kni/lifecycle-agent/lca-cli/seedclusterinfo"
 	configv1 "github.com/openshift/api/config/v1"
+	mcv1 "github.com/openshift/api/machineconfiguration/v1"
 	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
-func TestImageBasedUpgradeReconciler_validateSeedOcpVersion(t *testing.T) {
+func TestImageBasedUpgradeReconciler_checkSeedOcpVersionCompatibility(t *testing.T) {
 	// fake client setup
 	s := scheme.Scheme
 	s.AddKnownTypes(configv1.GroupVersion, &configv1.ClusterVersion{})
@@ -63,11 +81,303 @@ func TestImageBasedUpgradeReconciler_validateSeedOcpVersion(t *testing.T) {
 				Log:             logr.Logger{},
 			}
 
-			err := r.validateSeedOcpVersion(tt.args.seedOcpVersion)
-			tt.wantErr(t, err, fmt.Sprintf("validateSeedOcpVersion(%v)", tt.args.seedOcpVersion))
+			clusterOcpVersion, err := r.getClusterOcpVersion(context.TODO())
+			assert.NoError(t, err)
+
+			err = checkSeedOcpVersionCompatibility(tt.args.seedOcpVersion, clusterOcpVersion)
+			tt.wantErr(t, err, fmt.Sprintf("checkSeedOcpVersionCompatibility(%v)", tt.args.seedOcpVersion))
 			if err != nil {
 				assert.Equal(t, tt.wantErrMsg, err.Error())
 			}
 		})
 	}
 }
+
+func TestImageBasedUpgradeReconciler_validateSeedImageConfig(t *testing.T) {
+	mockController := gomock.NewController(t)
+	defer mockController.Finish()
+
+	s := scheme.Scheme
+	s.AddKnownTypes(configv1.GroupVersion, &configv1.ClusterVersion{}, &configv1.Proxy{}, &configv1.Network{})
+	s.AddKnownTypes(mcv1.GroupVersion, &mcv1.MachineConfig{})
+	objs := []client.Object{
+		&configv1.ClusterVersion{
+			ObjectMeta: metav1.ObjectMeta{Name: "version"},
+			Status:     configv1.ClusterVersionStatus{Desired: configv1.Release{Version: "4.14.8"}},
+		},
+		&configv1.Proxy{
+			ObjectMeta: metav1.ObjectMeta{Name: common.OpenshiftProxyCRName},
+			Spec:       configv1.ProxySpec{HTTPProxy: "http://proxy.example.com:3128"},
+		},
+		&corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "sno",
+				Annotations: map[string]string{"machineconfiguration.openshift.io/currentConfig": "rendered-master"},
+			},
+		},
+		&mcv1.MachineConfig{ObjectMeta: metav1.ObjectMeta{Name: "rendered-master"}},
+		&configv1.Network{
+			ObjectMeta: metav1.ObjectMeta{Name: common.OpenshiftInfraCRName},
+			Status: configv1.NetworkStatus{
+				ClusterNetwork: []configv1.ClusterNetworkEntry{{CIDR: "10.132.0.0/14"}},
+				ServiceNetwork: []string{"172.30.0.0/16"},
+			},
+		},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(s).WithObjects(objs...).Build()
+
+	seedInfo, err := json.Marshal(seedclusterinfo.SeedClusterInfo{
+		HasProxy:                         false,
+		HasFIPS:                          true,
+		ContainerStorageMountpointTarget: "/sysroot/containers",
+		ClusterNetworks:                  []string{"10.128.0.0/14"},
+		ServiceNetworks:                  []string{"172.30.0.0/16"},
+	})
+	assert.NoError(t, err)
+	inspect, err := json.Marshal(map[string]map[string]string{"Labels": {
+		common.SeedFormatOCILabel:      fmt.Sprintf("%d", common.SeedFormatVersion),
+		common.SeedClusterInfoOCILabel: string(seedInfo),
+	}})
+	assert.NoError(t, err)
+
+	mockExecutor := ops.NewMockExecute(mockController)
+	mockExecutor.EXPECT().Execute("skopeo", gomock.Any()).Return(string(inspect), nil).Times(1)
+
+	r := &ImageBasedUpgradeReconciler{
+		Client:                           fakeClient,
+		NoncachedClient:                  fakeClient,
+		Log:                              logr.Discard(),
+		Executor:                         mockExecutor,
+		ContainerStorageMountpointTarget: "/sysroot/containers",
+	}
+	ibu := &ibuv1.ImageBasedUpgrade{
+		Spec: ibuv1.ImageBasedUpgradeSpec{
+			SeedImageRef: ibuv1.SeedImageRef{Version: "4.14.8", Image: "quay.io/seed:4.14.8"},
+		},
+	}
+
+	ocpVersionCheck, err := r.getSeedOcpVersionCheck(context.TODO(), ibu)
+	assert.NoError(t, err)
+	report, err := r.validateSeedImageConfig(context.TODO(), ibu, "/var/lib/lca/workspace", ocpVersionCheck)
+	assert.Error(t, err)
+	// All the mismatches are reported at once
+	assert.Contains(t, err.Error(), "seed OCP version (4.14.8) must be higher than current OCP version (4.14.8)")
+	assert.Contains(t, err.Error(), "seed image does not have a proxy but the cluster being upgraded does")
+	assert.Contains(t, err.Error(), "seed image has FIPS enabled but the cluster being upgraded does not")
+	assert.Contains(t, err.Error(), "ClusterNetwork mismatch: seed=10.128.0.0/14, cluster=10.132.0.0/14")
+
+	verdicts := map[string]ibuv1.SeedCompatibilityVerdict{}
+	for _, check := range report {
+		verdicts[check.Name] = check.Verdict
+	}
+	assert.Equal(t, map[string]ibuv1.SeedCompatibilityVerdict{
+		SeedCompatibilityCheckOcpVersion:            ibuv1.SeedCompatibilityVerdicts.Incompatible,
+		SeedCompatibilityCheckFormatVersion:         ibuv1.SeedCompatibilityVerdicts.Compatible,
+		SeedCompatibilityCheckProxy:                 ibuv1.SeedCompatibilityVerdicts.Incompatible,
+		SeedCompatibilityCheckFIPS:                  ibuv1.SeedCompatibilityVerdicts.Incompatible,
+		SeedCompatibilityCheckAdditionalTrustBundle: ibuv1.SeedCompatibilityVerdicts.Skipped,
+		SeedCompatibilityCheckContainerStorage:      ibuv1.SeedCompatibilityVerdicts.Compatible,
+		SeedCompatibilityCheckClusterNetwork:        ibuv1.SeedCompatibilityVerdicts.Incompatible,
+		SeedCompatibilityCheckServiceNetwork:        ibuv1.SeedCompatibilityVerdicts.Compatible,
+	}, verdicts)
+
+	proxyCheck := report[2]
+	assert.Equal(t, SeedCompatibilityCheckProxy, proxyCheck.Name)
+	assert.Equal(t, "false", proxyCheck.SeedValue)
+	assert.Equal(t, "true", proxyCheck.ClusterValue)
+}
+
+func TestImageBasedUpgradeReconciler_getSeedOcpVersionCheck(t *testing.T) {
+	s := scheme.Scheme
+	s.AddKnownTypes(configv1.GroupVersion, &configv1.ClusterVersion{})
+	fakeClient := fake.NewClientBuilder().WithScheme(s).WithObjects(&configv1.ClusterVersion{
+		ObjectMeta: metav1.ObjectMeta{Name: "version"},
+		Status:     configv1.ClusterVersionStatus{Desired: configv1.Release{Version: "4.15.2"}},
+	}).Build()
+
+	r := &ImageBasedUpgradeReconciler{
+		Client: fakeClient,
+		Log:    logr.Discard(),
+	}
+	ibu := &ibuv1.ImageBasedUpgrade{
+		Spec: ibuv1.ImageBasedUpgradeSpec{
+			SeedImageRef: ibuv1.SeedImageRef{Version: "4.15.0", Image: "quay.io/seed:4.15.0"},
+		},
+	}
+
+	// A version mismatch is reported as a check, not an error, so that it is reported with the other seed checks
+	check, err := r.getSeedOcpVersionCheck(context.TODO(), ibu)
+	assert.NoError(t, err)
+	assert.Equal(t, SeedCompatibilityCheckOcpVersion, check.Name)
+	assert.Equal(t, ibuv1.SeedCompatibilityVerdicts.Incompatible, check.Verdict)
+	assert.Equal(t, "4.15.0", check.SeedValue)
+	assert.Equal(t, "4.15.2", check.ClusterValue)
+	assert.Contains(t, check.Message, "seed OCP version (4.15.0) must be higher than current OCP version (4.15.2)")
+
+	ibu.Spec.SeedImageRef.Version = "4.16.0"
+	check, err = r.getSeedOcpVersionCheck(context.TODO(), ibu)
+	assert.NoError(t, err)
+	assert.Equal(t, ibuv1.SeedCompatibilityVerdicts.Compatible, check.Verdict)
+}
+
+func TestCheckSeedImageNetworkCompatibility(t *testing.T) {
+	tests := []struct {
+		name            string
+		seedNetworks    []string
+		clusterNetworks []string
+		wantErr         bool
+	}{
+		{
+			name:            "same networks",
+			seedNetworks:    []string{"10.128.0.0/14"},
+			clusterNetworks: []string{"10.128.0.0/14"},
+		},
+		{
+			name:            "same networks in a different notation",
+			seedNetworks:    []string{"fd01:0:0::/48"},
+			clusterNetworks: []string{"fd01::/48"},
+		},
+		{
+			name:            "different networks",
+			seedNetworks:    []string{"10.128.0.0/14"},
+			clusterNetworks: []string{"10.132.0.0/14"},
+			wantErr:         true,
+		},
+		{
+			name:            "different dual-stack order",
+			seedNetworks:    []string{"10.128.0.0/14", "fd01::/48"},
+			clusterNetworks: []string{"fd01::/48", "10.128.0.0/14"},
+			wantErr:         true,
+		},
+		{
+			name:            "single-stack seed for a dual-stack cluster",
+			seedNetworks:    []string{"10.128.0.0/14"},
+			clusterNetworks: []string{"10.128.0.0/14", "fd01::/48"},
+			wantErr:         true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkSeedImageNetworkCompatibility(SeedCompatibilityCheckClusterNetwork, tt.seedNetworks, tt.clusterNetworks)
+			assert.Equal(t, tt.wantErr, err != nil)
+		})
+	}
+}
+
+func TestImageBasedUpgradeReconciler_resolveSeedImageDigest(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	assert.NoError(t, err)
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "seed-keys", Namespace: common.LcaNamespace},
+		Data:       map[string][]byte{"cosign.pub": pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(secret).Build()
+
+	digest := "sha256:5b0bcabd1ed22e9fb1310cf6c2dec7cdef19f0ad69efa1f392e94a4333501270"
+	tests := []struct {
+		name               string
+		verification       *ibuv1.SeedImageVerification
+		expectVerification bool
+	}{
+		{
+			name: "no verification",
+		},
+		{
+			// The seed image is not signed, no signature is found at its sha256-<digest>.sig tag
+			name:               "unsigned image",
+			verification:       &ibuv1.SeedImageVerification{PublicKeysRef: ibuv1.PublicKeysRef{Name: "seed-keys"}},
+			expectVerification: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockController := gomock.NewController(t)
+			defer mockController.Finish()
+
+			mockExecutor := ops.NewMockExecute(mockController)
+			mockExecutor.EXPECT().Execute("skopeo", gomock.Any()).DoAndReturn(func(command string, args ...string) (string, error) {
+				switch args[0] {
+				case "inspect":
+					assert.Contains(t, args, "{{.Digest}}")
+					return digest, nil
+				case "copy":
+					assert.Contains(t, args, "docker://quay.io/seed:sha256-5b0bcabd1ed22e9fb1310cf6c2dec7cdef19f0ad69efa1f392e94a4333501270.sig")
+					return "reading manifest: manifest unknown", fmt.Errorf("exit status 1")
+				}
+				return "", fmt.Errorf("unexpected skopeo command")
+			}).AnyTimes()
+
+			r := &ImageBasedUpgradeReconciler{
+				Client:   fakeClient,
+				Log:      logr.Discard(),
+				Executor: mockExecutor,
+			}
+			ibu := &ibuv1.ImageBasedUpgrade{
+				Spec: ibuv1.ImageBasedUpgradeSpec{
+					SeedImageRef: ibuv1.SeedImageRef{
+						Version:      "4.15.0",
+						Image:        "quay.io/seed:4.15.0",
+						Verification: tt.verification,
+					},
+				},
+			}
+
+			got, err := r.resolveSeedImageDigest(context.TODO(), ibu, t.TempDir())
+			if tt.expectVerification {
+				assert.ErrorContains(t, err, "no signature found")
+				assert.True(t, imagesignature.IsVerificationError(err))
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, digest, got)
+
+			ibu.Status.SeedImageDigest = got
+			pinned, err := GetPinnedSeedImage(ibu)
+			assert.NoError(t, err)
+			assert.Equal(t, "quay.io/seed@"+digest, pinned)
+		})
+	}
+}
+
+func TestCheckStaterootSeedImageDigest(t *testing.T) {
+	digestFile := filepath.Join(t.TempDir(), common.SeedImageDigestFileName)
+	assert.NoError(t, os.WriteFile(digestFile, []byte("sha256:1111"), 0o600))
+
+	tests := []struct {
+		name       string
+		digest     string
+		digestFile string
+		wantErr    bool
+	}{
+		{
+			name:       "same digest",
+			digest:     "sha256:1111",
+			digestFile: digestFile,
+		},
+		{
+			name:       "digest changed",
+			digest:     "sha256:2222",
+			digestFile: digestFile,
+			wantErr:    true,
+		},
+		{
+			name:       "stateroot digest not recorded",
+			digest:     "sha256:1111",
+			digestFile: filepath.Join(t.TempDir(), common.SeedImageDigestFileName),
+			wantErr:    true,
+		},
+		{
+			name:       "digest not pinned by Prep",
+			digestFile: filepath.Join(t.TempDir(), common.SeedImageDigestFileName),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ibu := &ibuv1.ImageBasedUpgrade{Status: ibuv1.ImageBasedUpgradeStatus{SeedImageDigest: tt.digest}}
+			err := checkStaterootSeedImageDigest(ibu, tt.digestFile)
+			assert.Equal(t, tt.wantErr, err != nil)
+		})
+	}
+}
@@ -0,0 +1,395 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/go-logr/logr"
+	ibuv1 "github.com/openshift-kni/lifecycle-agent/api/imagebasedupgrade/v1"
+	"github.com/openshift-kni/lifecycle-agent/controllers/utils"
+	"github.com/openshift-kni/lifecycle-agent/internal/common"
+	lcautils "github.com/openshift-kni/lifecycle-agent/utils"
+	"github.com/samber/lo"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+//+kubebuilder:rbac:groups=apps,resources=deployments;statefulsets,verbs=get;list;watch;update
+
+const (
+	defaultQuiesceStepTimeout = 5 * time.Minute
+
+	quiesceJobNamePrefix = "lca-quiesce-"
+	quiesceStepLabel     = "lca.openshift.io/quiesce-step"
+
+	workloadKindDeployment  = "Deployment"
+	workloadKindStatefulSet = "StatefulSet"
+)
+
+// errQuiesceFailed is wrapped by the errors of the quiesce steps that fail or time out
+var errQuiesceFailed = errors.New("quiesce failed")
+
+// quiesceRecordPath is the record of the quiesce in the current stateroot
+var quiesceRecordPath = common.PathOutsideChroot(utils.QuiesceRecordFilePath)
+
+// quiesceRecord tracks the quiesce steps and the original replicas of the workloads they scaled down. It is saved in
+// the original stateroot while the steps run, and copied to the new stateroot before the reboot.
+type quiesceRecord struct {
+	Steps     []quiesceStepRecord `json:"steps,omitempty"`
+	Workloads []quiescedWorkload  `json:"workloads,omitempty"`
+}
+
+type quiesceStepRecord struct {
+	Name      string      `json:"name"`
+	StartTime metav1.Time `json:"startTime"`
+	Completed bool        `json:"completed,omitempty"`
+}
+
+type quiescedWorkload struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Replicas  int32  `json:"replicas"`
+}
+
+func readQuiesceRecord(path string) (*quiesceRecord, error) {
+	record := &quiesceRecord{}
+	if err := lcautils.ReadYamlOrJSONFile(path, record); err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read quiesce record: %w", err)
+	}
+	return record, nil
+}
+
+func writeQuiesceRecord(record *quiesceRecord, path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to mkdir: %w", err)
+	}
+	if err := lcautils.MarshalToFile(record, path); err != nil {
+		return fmt.Errorf("failed to write quiesce record: %w", err)
+	}
+	return nil
+}
+
+// quiesceWorkloads runs the steps of spec.quiesce in order, and returns whether they all completed. The progress of
+// the steps and the original replicas of the workloads are saved in the quiesce record, so that a step is resumed by
+// the next reconcile and is not run again once completed. An error wrapping errQuiesceFailed is returned when a step
+// fails or times out.
+func (u *UpgHandler) quiesceWorkloads(ctx context.Context, ibu *ibuv1.ImageBasedUpgrade) (bool, error) {
+	record, err := readQuiesceRecord(quiesceRecordPath)
+	if err != nil {
+		return false, err
+	}
+	if record == nil {
+		record = &quiesceRecord{}
+	}
+
+	for _, step := range ibu.Spec.Quiesce.Steps {
+		stepRecord, index, found := lo.FindIndexOf(record.Steps, func(s quiesceStepRecord) bool { return s.Name == step.Name })
+		if !found {
+			if step.Job != nil {
+				// A Job left over by a previous upgrade must not complete the step
+				if err := deleteQuiesceJob(ctx, u.Client, step); err != nil {
+					return false, err
+				}
+			}
+			stepRecord = quiesceStepRecord{Name: step.Name, StartTime: metav1.Now()}
+			record.Steps = append(record.Steps, stepRecord)
+			index = len(record.Steps) - 1
+			if err := writeQuiesceRecord(record, quiesceRecordPath); err != nil {
+				return false, err
+			}
+			u.Log.Info("Starting quiesce step", "step", step.Name)
+		}
+		if stepRecord.Completed {
+			continue
+		}
+
+		timeout := defaultQuiesceStepTimeout
+		if step.Timeout != nil {
+			timeout = step.Timeout.Duration
+		}
+		if time.Since(stepRecord.StartTime.Time) > timeout {
+			return false, fmt.Errorf("%w: step %s did not complete in %s", errQuiesceFailed, step.Name, timeout)
+		}
+
+		var completed bool
+		if step.ScaleDown != nil {
+			completed, err = scaleDownWorkloads(ctx, u.Client, u.Log, step.ScaleDown, record, quiesceRecordPath)
+		} else {
+			completed, err = runQuiesceJob(ctx, u.Client, u.NoncachedClient, step)
+		}
+		if err != nil || !completed {
+			return false, err
+		}
+
+		record.Steps[index].Completed = true
+		if err := writeQuiesceRecord(record, quiesceRecordPath); err != nil {
+			return false, err
+		}
+		u.Log.Info("Quiesce step completed", "step", step.Name)
+	}
+	return true, nil
+}
+
+// scaleDownWorkloads scales the selected workloads to zero replicas, and returns whether their pods are all deleted.
+// The original replicas of a workload are saved in the quiesce record before it is scaled down, so they are not lost if
+// LCA restarts in between.
+func scaleDownWorkloads(ctx context.Context, c client.Client, log logr.Logger, scaleDown *ibuv1.QuiesceScaleDown,
+	record *quiesceRecord, recordPath string) (bool, error) {
+	workloads, err := getQuiesceWorkloads(ctx, c, scaleDown)
+	if err != nil {
+		return false, err
+	}
+
+	completed := true
+	for _, workload := range workloads {
+		var kind string
+		var replicas **int32
+		var statusReplicas int32
+		var observedGeneration int64
+		switch w := workload.(type) {
+		case *appsv1.Deployment:
+			kind, replicas, statusReplicas, observedGeneration = workloadKindDeployment, &w.Spec.Replicas, w.Status.Replicas, w.Status.ObservedGeneration
+		case *appsv1.StatefulSet:
+			kind, replicas, statusReplicas, observedGeneration = workloadKindStatefulSet, &w.Spec.Replicas, w.Status.Replicas, w.Status.ObservedGeneration
+		}
+
+		if lo.FromPtrOr(*replicas, 1) != 0 {
+			_, recorded := lo.Find(record.Workloads, func(w quiescedWorkload) bool {
+				return w.Kind == kind && w.Namespace == workload.GetNamespace() && w.Name == workload.GetName()
+			})
+			if !recorded {
+				record.Workloads = append(record.Workloads, quiescedWorkload{
+					Kind:      kind,
+					Namespace: workload.GetNamespace(),
+					Name:      workload.GetName(),
+					Replicas:  lo.FromPtrOr(*replicas, 1),
+				})
+				if err := writeQuiesceRecord(record, recordPath); err != nil {
+					return false, err
+				}
+			}
+			*replicas = lo.ToPtr(int32(0))
+			if err := c.Update(ctx, workload); err != nil {
+				return false, fmt.Errorf("failed to scale down %s %s/%s: %w", kind, workload.GetNamespace(), workload.GetName(), err)
+			}
+			log.Info("Workload scaled down", "kind", kind, "namespace", workload.GetNamespace(), "name", workload.GetName())
+			completed = false
+			continue
+		}
+		if statusReplicas != 0 || workload.GetGeneration() != observedGeneration {
+			log.Info("Waiting for workload to scale down", "kind", kind, "namespace", workload.GetNamespace(),
+				"name", workload.GetName(), "replicas", statusReplicas)
+			completed = false
+		}
+	}
+	return completed, nil
+}
+
+// getQuiesceWorkloads returns the Deployments and StatefulSets selected by name or by label. The workloads that do not
+// exist are skipped.
+func getQuiesceWorkloads(ctx context.Context, c client.Client, scaleDown *ibuv1.QuiesceScaleDown) ([]client.Object, error) {
+	var workloads []client.Object
+	seen := map[string]bool{}
+	add := func(kind string, obj client.Object) {
+		if key := kind + "/" + obj.GetName(); !seen[key] {
+			seen[key] = true
+			workloads = append(workloads, obj)
+		}
+	}
+
+	for _, name := range scaleDown.Deployments {
+		deployment := &appsv1.Deployment{}
+		if err := c.Get(ctx, types.NamespacedName{Namespace: scaleDown.Namespace, Name: name}, deployment); err != nil {
+			if k8serrors.IsNotFound(err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to get Deployment %s/%s: %w", scaleDown.Namespace, name, err)
+		}
+		add(workloadKindDeployment, deployment)
+	}
+	for _, name := range scaleDown.StatefulSets {
+		statefulSet := &appsv1.StatefulSet{}
+		if err := c.Get(ctx, types.NamespacedName{Namespace: scaleDown.Namespace, Name: name}, statefulSet); err != nil {
+			if k8serrors.IsNotFound(err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to get StatefulSet %s/%s: %w", scaleDown.Namespace, name, err)
+		}
+		add(workloadKindStatefulSet, statefulSet)
+	}
+
+	if scaleDown.LabelSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(scaleDown.LabelSelector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid quiesce label selector: %w", err)
+		}
+		opts := []client.ListOption{client.InNamespace(scaleDown.Namespace), client.MatchingLabelsSelector{Selector: selector}}
+
+		deployments := &appsv1.DeploymentList{}
+		if err := c.List(ctx, deployments, opts...); err != nil {
+			return nil, fmt.Errorf("failed to list Deployments: %w", err)
+		}
+		for i := range deployments.Items {
+			add(workloadKindDeployment, &deployments.Items[i])
+		}
+		statefulSets := &appsv1.StatefulSetList{}
+		if err := c.List(ctx, statefulSets, opts...); err != nil {
+			return nil, fmt.Errorf("failed to list StatefulSets: %w", err)
+		}
+		for i := range statefulSets.Items {
+			add(workloadKindStatefulSet, &statefulSets.Items[i])
+		}
+	}
+	return workloads, nil
+}
+
+// runQuiesceJob creates the Job of the step, and returns whether it succeeded. The Job is deleted once it succeeds,
+// and kept for debugging if it fails. Jobs outside the LCA namespace are not cached, so they are read with reader.
+func runQuiesceJob(ctx context.Context, c client.Client, reader client.Reader, step ibuv1.QuiesceStep) (bool, error) {
+	job := &batchv1.Job{}
+	key := types.NamespacedName{Namespace: step.Job.Namespace, Name: quiesceJobNamePrefix + step.Name}
+	if err := reader.Get(ctx, key, job); err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return false, fmt.Errorf("failed to get quiesce Job %s: %w", key, err)
+		}
+		if err := c.Create(ctx, newQuiesceJob(step)); err != nil {
+			return false, fmt.Errorf("failed to create quiesce Job %s: %w", key, err)
+		}
+		return false, nil
+	}
+
+	for _, condition := range job.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch condition.Type {
+		case batchv1.JobComplete:
+			if err := c.Delete(ctx, job, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil && !k8serrors.IsNotFound(err) {
+				return false, fmt.Errorf("failed to delete quiesce Job %s: %w", key, err)
+			}
+			return true, nil
+		case batchv1.JobFailed:
+			return false, fmt.Errorf("%w: Job %s of step %s failed: %s", errQuiesceFailed, key, step.Name, condition.Message)
+		}
+	}
+	return false, nil
+}
+
+func newQuiesceJob(step ibuv1.QuiesceStep) *batchv1.Job {
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      quiesceJobNamePrefix + step.Name,
+			Namespace: step.Job.Namespace,
+			Labels:    map[string]string{quiesceStepLabel: step.Name},
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: lo.ToPtr(int32(0)),
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{quiesceStepLabel: step.Name},
+				},
+				Spec: corev1.PodSpec{
+					RestartPolicy:      corev1.RestartPolicyNever,
+					ServiceAccountName: step.Job.ServiceAccountName,
+					Containers: []corev1.Container{{
+						Name:    "quiesce",
+						Image:   step.Job.Image,
+						Command: step.Job.Command,
+					}},
+				},
+			},
+		},
+	}
+}
+
+func deleteQuiesceJob(ctx context.Context, c client.Client, step ibuv1.QuiesceStep) error {
+	job := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: quiesceJobNamePrefix + step.Name, Namespace: step.Job.Namespace}}
+	if err := c.Delete(ctx, job, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil && !k8serrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete quiesce Job %s/%s: %w", job.Namespace, job.Name, err)
+	}
+	return nil
+}
+
+// exportQuiesceRecordToNewStateroot copies the quiesce record to the new stateroot, so the workloads are scaled back
+// once the upgrade completes
+func exportQuiesceRecordToNewStateroot(staterootPath string) error {
+	if err := lcautils.CopyFileIfExists(quiesceRecordPath, filepath.Join(staterootPath, utils.QuiesceRecordFilePath)); err != nil {
+		return fmt.Errorf("failed to copy quiesce record to the new stateroot: %w", err)
+	}
+	return nil
+}
+
+// restoreQuiescedWorkloads scales the workloads of the quiesce record in the current stateroot back to their original
+// replicas, then removes the record. The workloads that do not exist are skipped.
+func restoreQuiescedWorkloads(ctx context.Context, c client.Client, log logr.Logger) error {
+	record, err := readQuiesceRecord(quiesceRecordPath)
+	if err != nil || record == nil {
+		return err
+	}
+
+	for _, workload := range record.Workloads {
+		var obj client.Object
+		var replicas **int32
+		switch workload.Kind {
+		case workloadKindDeployment:
+			deployment := &appsv1.Deployment{}
+			obj, replicas = deployment, &deployment.Spec.Replicas
+		case workloadKindStatefulSet:
+			statefulSet := &appsv1.StatefulSet{}
+			obj, replicas = statefulSet, &statefulSet.Spec.Replicas
+		default:
+			continue
+		}
+
+		if err := c.Get(ctx, types.NamespacedName{Namespace: workload.Namespace, Name: workload.Name}, obj); err != nil {
+			if k8serrors.IsNotFound(err) {
+				log.Info("Quiesced workload not found, skipping", "kind", workload.Kind, "namespace", workload.Namespace, "name", workload.Name)
+				continue
+			}
+			return fmt.Errorf("failed to get %s %s/%s: %w", workload.Kind, workload.Namespace, workload.Name, err)
+		}
+		if lo.FromPtrOr(*replicas, 1) == workload.Replicas {
+			continue
+		}
+		*replicas = lo.ToPtr(workload.Replicas)
+		if err := c.Update(ctx, obj); err != nil {
+			return fmt.Errorf("failed to scale %s %s/%s back: %w", workload.Kind, workload.Namespace, workload.Name, err)
+		}
+		log.Info("Quiesced workload scaled back", "kind", workload.Kind, "namespace", workload.Namespace,
+			"name", workload.Name, "replicas", workload.Replicas)
+	}
+
+	if err := os.Remove(quiesceRecordPath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove quiesce record: %w", err)
+	}
+	return nil
+}
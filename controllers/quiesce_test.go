@@ -0,0 +1,177 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	ibuv1 "github.com/openshift-kni/lifecycle-agent/api/imagebasedupgrade/v1"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestQuiesceWorkloads(t *testing.T) {
+	origQuiesceRecordPath := quiesceRecordPath
+	defer func() {
+		quiesceRecordPath = origQuiesceRecordPath
+	}()
+	quiesceRecordPath = filepath.Join(t.TempDir(), "quiesce.json")
+
+	db := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "my-app", Labels: map[string]string{"tier": "data"}},
+		Spec:       appsv1.StatefulSetSpec{Replicas: lo.ToPtr(int32(2))},
+		Status:     appsv1.StatefulSetStatus{Replicas: 2},
+	}
+	web := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "my-app"},
+		Spec:       appsv1.DeploymentSpec{Replicas: lo.ToPtr(int32(3))},
+		Status:     appsv1.DeploymentStatus{Replicas: 3},
+	}
+	c, err := getFakeClientFromObjects(db, web)
+	assert.NoError(t, err)
+
+	ibu := &ibuv1.ImageBasedUpgrade{Spec: ibuv1.ImageBasedUpgradeSpec{Quiesce: &ibuv1.Quiesce{Steps: []ibuv1.QuiesceStep{
+		{
+			Name: "flush",
+			Job:  &ibuv1.QuiesceJob{Namespace: "my-app", Image: "quay.io/my-app/tools:latest", Command: []string{"flush"}},
+		},
+		{
+			Name: "stop",
+			ScaleDown: &ibuv1.QuiesceScaleDown{
+				Namespace:     "my-app",
+				Deployments:   []string{"web", "missing"},
+				LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "data"}},
+			},
+		},
+	}}}}
+	uh := &UpgHandler{Client: c, NoncachedClient: c, Log: logr.Discard()}
+
+	// The Job of the first step is created
+	quiesced, err := uh.quiesceWorkloads(context.Background(), ibu)
+	assert.NoError(t, err)
+	assert.False(t, quiesced)
+	job := &batchv1.Job{}
+	jobKey := types.NamespacedName{Name: "lca-quiesce-flush", Namespace: "my-app"}
+	assert.NoError(t, c.Get(context.Background(), jobKey, job))
+	assert.Equal(t, []string{"flush"}, job.Spec.Template.Spec.Containers[0].Command)
+
+	// The workloads are scaled down once the Job completes, and the Job is deleted
+	job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}}
+	assert.NoError(t, c.Status().Update(context.Background(), job))
+	quiesced, err = uh.quiesceWorkloads(context.Background(), ibu)
+	assert.NoError(t, err)
+	assert.False(t, quiesced)
+	assert.True(t, k8serrors.IsNotFound(c.Get(context.Background(), jobKey, &batchv1.Job{})))
+	assert.NoError(t, c.Get(context.Background(), types.NamespacedName{Name: "web", Namespace: "my-app"}, web))
+	assert.Equal(t, int32(0), *web.Spec.Replicas)
+	assert.NoError(t, c.Get(context.Background(), types.NamespacedName{Name: "db", Namespace: "my-app"}, db))
+	assert.Equal(t, int32(0), *db.Spec.Replicas)
+
+	// The step completes once the pods are deleted
+	web.Status.Replicas = 0
+	assert.NoError(t, c.Status().Update(context.Background(), web))
+	db.Status.Replicas = 0
+	assert.NoError(t, c.Status().Update(context.Background(), db))
+	quiesced, err = uh.quiesceWorkloads(context.Background(), ibu)
+	assert.NoError(t, err)
+	assert.True(t, quiesced)
+
+	record, err := readQuiesceRecord(quiesceRecordPath)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []quiescedWorkload{
+		{Kind: workloadKindDeployment, Namespace: "my-app", Name: "web", Replicas: 3},
+		{Kind: workloadKindStatefulSet, Namespace: "my-app", Name: "db", Replicas: 2},
+	}, record.Workloads)
+
+	// The record is copied to the new stateroot
+	staterootPath := t.TempDir()
+	assert.NoError(t, exportQuiesceRecordToNewStateroot(staterootPath))
+	assert.FileExists(t, filepath.Join(staterootPath, "var/lib/lca/quiesce.json"))
+
+	// The workloads get their replicas back and the record is removed
+	assert.NoError(t, restoreQuiescedWorkloads(context.Background(), c, logr.Discard()))
+	assert.NoError(t, c.Get(context.Background(), types.NamespacedName{Name: "web", Namespace: "my-app"}, web))
+	assert.Equal(t, int32(3), *web.Spec.Replicas)
+	assert.NoError(t, c.Get(context.Background(), types.NamespacedName{Name: "db", Namespace: "my-app"}, db))
+	assert.Equal(t, int32(2), *db.Spec.Replicas)
+	_, err = os.Stat(quiesceRecordPath)
+	assert.True(t, os.IsNotExist(err))
+
+	// Nothing to restore without record
+	assert.NoError(t, restoreQuiescedWorkloads(context.Background(), c, logr.Discard()))
+}
+
+func TestQuiesceWorkloadsFailures(t *testing.T) {
+	origQuiesceRecordPath := quiesceRecordPath
+	defer func() {
+		quiesceRecordPath = origQuiesceRecordPath
+	}()
+
+	ibu := &ibuv1.ImageBasedUpgrade{Spec: ibuv1.ImageBasedUpgradeSpec{Quiesce: &ibuv1.Quiesce{Steps: []ibuv1.QuiesceStep{{
+		Name:    "flush",
+		Job:     &ibuv1.QuiesceJob{Namespace: "my-app", Image: "quay.io/my-app/tools:latest"},
+		Timeout: &metav1.Duration{Duration: time.Minute},
+	}}}}}
+
+	// The step times out
+	quiesceRecordPath = filepath.Join(t.TempDir(), "quiesce.json")
+	assert.NoError(t, writeQuiesceRecord(&quiesceRecord{Steps: []quiesceStepRecord{
+		{Name: "flush", StartTime: metav1.NewTime(time.Now().Add(-2 * time.Minute))},
+	}}, quiesceRecordPath))
+	c, err := getFakeClientFromObjects()
+	assert.NoError(t, err)
+	uh := &UpgHandler{Client: c, NoncachedClient: c, Log: logr.Discard()}
+	_, err = uh.quiesceWorkloads(context.Background(), ibu)
+	assert.True(t, errors.Is(err, errQuiesceFailed))
+	assert.ErrorContains(t, err, "step flush did not complete in 1m0s")
+
+	// The Job fails
+	quiesceRecordPath = filepath.Join(t.TempDir(), "quiesce.json")
+	job := newQuiesceJob(ibu.Spec.Quiesce.Steps[0])
+	job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Message: "BackoffLimitExceeded"}}
+	assert.NoError(t, writeQuiesceRecord(&quiesceRecord{Steps: []quiesceStepRecord{
+		{Name: "flush", StartTime: metav1.Now()},
+	}}, quiesceRecordPath))
+	c, err = getFakeClientFromObjects(job)
+	assert.NoError(t, err)
+	uh = &UpgHandler{Client: c, NoncachedClient: c, Log: logr.Discard()}
+	_, err = uh.quiesceWorkloads(context.Background(), ibu)
+	assert.True(t, errors.Is(err, errQuiesceFailed))
+	assert.ErrorContains(t, err, "Job my-app/lca-quiesce-flush of step flush failed: BackoffLimitExceeded")
+
+	// The original replicas are saved before the workload is scaled down
+	quiesceRecordPath = filepath.Join(t.TempDir(), "quiesce.json")
+	web := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "my-app"},
+		Spec:       appsv1.DeploymentSpec{Replicas: lo.ToPtr(int32(3))},
+	}
+	c = fake.NewClientBuilder().WithScheme(testscheme).WithObjects(web).WithInterceptorFuncs(interceptor.Funcs{
+		Update: func(ctx context.Context, cl client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+			record, err := readQuiesceRecord(quiesceRecordPath)
+			assert.NoError(t, err)
+			assert.Equal(t, []quiescedWorkload{{Kind: workloadKindDeployment, Namespace: "my-app", Name: "web", Replicas: 3}},
+				record.Workloads)
+			return errors.New("connection refused")
+		},
+	}).Build()
+	uh = &UpgHandler{Client: c, NoncachedClient: c, Log: logr.Discard()}
+	ibu.Spec.Quiesce.Steps = []ibuv1.QuiesceStep{{
+		Name:      "stop",
+		ScaleDown: &ibuv1.QuiesceScaleDown{Namespace: "my-app", Deployments: []string{"web"}},
+	}}
+	_, err = uh.quiesceWorkloads(context.Background(), ibu)
+	assert.ErrorContains(t, err, "failed to scale down Deployment my-app/web: connection refused")
+}
@@ -118,7 +118,13 @@ func (r *ImageBasedUpgradeReconciler) startRollback(ctx context.Context, ibu *ib
 	return doNotRequeue(), nil
 }
 
-func (r *ImageBasedUpgradeReconciler) finishRollback(ibu *ibuv1.ImageBasedUpgrade) (ctrl.Result, error) {
+func (r *ImageBasedUpgradeReconciler) finishRollback(ctx context.Context, ibu *ibuv1.ImageBasedUpgrade) (ctrl.Result, error) {
+	// The workloads quiesced before the pivot are still scaled down in the original stateroot
+	if err := restoreQuiescedWorkloads(ctx, r.Client, r.Log); err != nil {
+		utils.SetRollbackStatusInProgress(ibu, fmt.Sprintf("Restoring Quiesced Workloads: Failure occurred: %s", err))
+		return requeueWithError(fmt.Errorf("error while restoring quiesced workloads: %w", err))
+	}
+
 	utils.SetRollbackStatusCompleted(ibu)
 
 	return doNotRequeue(), nil
@@ -135,7 +141,7 @@ func (r *ImageBasedUpgradeReconciler) handleRollback(ctx context.Context, ibu *i
 	if origStaterootBooted {
 		r.Log.Info("Pivot for rollback successful, starting post pivot steps")
 		utils.StopStageHistory(r.Client, r.Log, ibu)
-		return r.finishRollback(ibu)
+		return r.finishRollback(ctx, ibu)
 	} else {
 		r.Log.Info("Starting pre pivot for rollback steps and will pivot to previous stateroot with a reboot")
 		return r.startRollback(ctx, ibu)
@@ -345,7 +345,7 @@ func TestFinishRollback(t *testing.T) {
 
 	ibu := &ibuv1.ImageBasedUpgrade{}
 
-	result, err := r.finishRollback(ibu)
+	result, err := r.finishRollback(context.Background(), ibu)
 	assert.NoError(t, err)
 	assert.Equal(t, doNotRequeue(), result)
 	assertConditionsMatch(t, []metav1.Condition{
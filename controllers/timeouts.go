@@ -0,0 +1,91 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"time"
+
+	ibuv1 "github.com/openshift-kni/lifecycle-agent/api/imagebasedupgrade/v1"
+	ipcv1 "github.com/openshift-kni/lifecycle-agent/api/ipconfig/v1"
+	"github.com/openshift-kni/lifecycle-agent/controllers/utils"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// stepTimeout is a timeout from spec.timeouts along with the start time of the stage or phase it bounds
+type stepTimeout struct {
+	name      string
+	timeout   *metav1.Duration
+	startTime *metav1.Time
+}
+
+// getExpiredTimeout returns a message describing the first expired timeout, or an empty string if none has expired.
+// Timeouts that are not set, and those of steps that are not in progress, never expire.
+func getExpiredTimeout(now time.Time, steps ...stepTimeout) string {
+	for _, step := range steps {
+		if step.timeout == nil || step.timeout.Duration <= 0 || step.startTime == nil {
+			continue
+		}
+		if now.Sub(step.startTime.Time) >= step.timeout.Duration {
+			return fmt.Sprintf("%s timed out after %s, started at %s", step.name, step.timeout.Duration,
+				step.startTime.UTC().Format(time.RFC3339))
+		}
+	}
+	return ""
+}
+
+// getPrepExpiredTimeout checks the timeouts of the Prep stage and of its jobs
+func getPrepExpiredTimeout(ibu *ibuv1.ImageBasedUpgrade, now time.Time) string {
+	timeouts := ibu.Spec.Timeouts
+	if timeouts == nil {
+		return ""
+	}
+	return getExpiredTimeout(now,
+		stepTimeout{"Prep stage", timeouts.Prep, utils.GetStageStartTime(ibu)},
+		stepTimeout{"Stateroot setup", timeouts.StaterootSetup, utils.GetPhaseStartTime(ibu, PrepPhaseStateroot)},
+		stepTimeout{"Precache", timeouts.Precache, utils.GetPhaseStartTime(ibu, PrepPhasePrecache)},
+	)
+}
+
+// getUpgradeExpiredTimeout checks the timeouts of the Upgrade stage and of its pre-pivot or post-pivot phase
+func getUpgradeExpiredTimeout(ibu *ibuv1.ImageBasedUpgrade, phase string, now time.Time) string {
+	timeouts := ibu.Spec.Timeouts
+	if timeouts == nil {
+		return ""
+	}
+	phaseTimeout := timeouts.PrePivot
+	if phase == UpgradePhasePostpivot {
+		phaseTimeout = timeouts.PostPivot
+	}
+	return getExpiredTimeout(now,
+		stepTimeout{"Upgrade stage", timeouts.Upgrade, utils.GetStageStartTime(ibu)},
+		stepTimeout{fmt.Sprintf("Upgrade %s phase", phase), phaseTimeout, utils.GetPhaseStartTime(ibu, phase)},
+	)
+}
+
+// getIPConfigExpiredTimeout checks the timeouts of the IPConfig Config stage and of its phases
+func getIPConfigExpiredTimeout(ipc *ipcv1.IPConfig, now time.Time) string {
+	timeouts := ipc.Spec.Timeouts
+	if timeouts == nil {
+		return ""
+	}
+	return getExpiredTimeout(now,
+		stepTimeout{"Config stage", timeouts.Config, utils.GetIPStageStartTime(ipc)},
+		stepTimeout{"Config pre-pivot phase", timeouts.PrePivot, utils.GetIPPhaseStartTime(ipc, IPConfigPhasePrePivot)},
+		stepTimeout{"Config post-pivot phase", timeouts.PostPivot, utils.GetIPPhaseStartTime(ipc, IPConfigPhasePostPivot)},
+	)
+}
@@ -0,0 +1,130 @@
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	ibuv1 "github.com/openshift-kni/lifecycle-agent/api/imagebasedupgrade/v1"
+	ipcv1 "github.com/openshift-kni/lifecycle-agent/api/ipconfig/v1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGetPrepExpiredTimeout(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	newIBU := func(timeouts *ibuv1.Timeouts, phases ...*ibuv1.Phase) *ibuv1.ImageBasedUpgrade {
+		return &ibuv1.ImageBasedUpgrade{
+			Spec: ibuv1.ImageBasedUpgradeSpec{Stage: ibuv1.Stages.Prep, Timeouts: timeouts},
+			Status: ibuv1.ImageBasedUpgradeStatus{
+				History: []*ibuv1.History{{
+					Stage:     ibuv1.Stages.Prep,
+					StartTime: metav1.NewTime(now.Add(-90 * time.Minute)),
+					Phases:    phases,
+				}},
+			},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		ibu      *ibuv1.ImageBasedUpgrade
+		expected string
+	}{
+		{
+			name: "no timeouts",
+			ibu:  newIBU(nil),
+		},
+		{
+			name: "stage within its timeout",
+			ibu:  newIBU(&ibuv1.Timeouts{Prep: &metav1.Duration{Duration: 2 * time.Hour}}),
+		},
+		{
+			name:     "stage timeout expired",
+			ibu:      newIBU(&ibuv1.Timeouts{Prep: &metav1.Duration{Duration: time.Hour}}),
+			expected: "Prep stage timed out after 1h0m0s, started at 2026-03-01T10:30:00Z",
+		},
+		{
+			name: "stateroot setup timeout expired",
+			ibu: newIBU(&ibuv1.Timeouts{StaterootSetup: &metav1.Duration{Duration: 30 * time.Minute}},
+				&ibuv1.Phase{Phase: PrepPhaseStateroot, StartTime: metav1.NewTime(now.Add(-45 * time.Minute))}),
+			expected: "Stateroot setup timed out after 30m0s, started at 2026-03-01T11:15:00Z",
+		},
+		{
+			name: "completed phase does not time out",
+			ibu: newIBU(&ibuv1.Timeouts{StaterootSetup: &metav1.Duration{Duration: 30 * time.Minute}},
+				&ibuv1.Phase{
+					Phase:          PrepPhaseStateroot,
+					StartTime:      metav1.NewTime(now.Add(-80 * time.Minute)),
+					CompletionTime: metav1.NewTime(now.Add(-60 * time.Minute)),
+				}),
+		},
+		{
+			name: "precache timeout expired",
+			ibu: newIBU(&ibuv1.Timeouts{Precache: &metav1.Duration{Duration: 10 * time.Minute}},
+				&ibuv1.Phase{Phase: PrepPhasePrecache, StartTime: metav1.NewTime(now.Add(-10 * time.Minute))}),
+			expected: "Precache timed out after 10m0s, started at 2026-03-01T11:50:00Z",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, getPrepExpiredTimeout(tc.ibu, now))
+		})
+	}
+}
+
+func TestGetUpgradeExpiredTimeout(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	ibu := &ibuv1.ImageBasedUpgrade{
+		Spec: ibuv1.ImageBasedUpgradeSpec{
+			Stage: ibuv1.Stages.Upgrade,
+			Timeouts: &ibuv1.Timeouts{
+				Upgrade:  &metav1.Duration{Duration: 3 * time.Hour},
+				PrePivot: &metav1.Duration{Duration: 20 * time.Minute},
+			},
+		},
+		Status: ibuv1.ImageBasedUpgradeStatus{
+			History: []*ibuv1.History{{
+				Stage:     ibuv1.Stages.Upgrade,
+				StartTime: metav1.NewTime(now.Add(-time.Hour)),
+				Phases: []*ibuv1.Phase{
+					{Phase: UpgradePhasePrepivot, StartTime: metav1.NewTime(now.Add(-time.Hour))},
+				},
+			}},
+		},
+	}
+
+	assert.Equal(t, "Upgrade PrePivot phase timed out after 20m0s, started at 2026-03-01T11:00:00Z",
+		getUpgradeExpiredTimeout(ibu, UpgradePhasePrepivot, now))
+	// the post-pivot phase has no timeout and the stage is within its timeout
+	assert.Equal(t, "", getUpgradeExpiredTimeout(ibu, UpgradePhasePostpivot, now))
+	assert.Equal(t, "Upgrade stage timed out after 3h0m0s, started at 2026-03-01T11:00:00Z",
+		getUpgradeExpiredTimeout(ibu, UpgradePhasePostpivot, now.Add(2*time.Hour)))
+}
+
+func TestGetIPConfigExpiredTimeout(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	ipc := &ipcv1.IPConfig{
+		Spec: ipcv1.IPConfigSpec{
+			Stage:    ipcv1.IPStages.Config,
+			Timeouts: &ipcv1.Timeouts{PostPivot: &metav1.Duration{Duration: 15 * time.Minute}},
+		},
+		Status: ipcv1.IPConfigStatus{
+			History: []*ipcv1.IPHistory{{
+				Stage:     ipcv1.IPStages.Config,
+				StartTime: metav1.NewTime(now.Add(-time.Hour)),
+				Phases: []*ipcv1.IPPhase{
+					{
+						Phase:          IPConfigPhasePrePivot,
+						StartTime:      metav1.NewTime(now.Add(-time.Hour)),
+						CompletionTime: metav1.NewTime(now.Add(-30 * time.Minute)),
+					},
+					{Phase: IPConfigPhasePostPivot, StartTime: metav1.NewTime(now.Add(-10 * time.Minute))},
+				},
+			}},
+		},
+	}
+
+	assert.Equal(t, "", getIPConfigExpiredTimeout(ipc, now))
+	assert.Equal(t, "Config post-pivot phase timed out after 15m0s, started at 2026-03-01T11:50:00Z",
+		getIPConfigExpiredTimeout(ipc, now.Add(5*time.Minute)))
+}
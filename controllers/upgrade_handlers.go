@@ -18,10 +18,12 @@ package controllers
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/go-logr/logr"
 	ibuv1 "github.com/openshift-kni/lifecycle-agent/api/imagebasedupgrade/v1"
@@ -36,7 +38,11 @@ import (
 	"github.com/openshift-kni/lifecycle-agent/lca-cli/ops"
 	rpmostreeclient "github.com/openshift-kni/lifecycle-agent/lca-cli/ostreeclient"
 	lcautils "github.com/openshift-kni/lifecycle-agent/utils"
+	"github.com/samber/lo"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -45,7 +51,7 @@ import (
 type (
 	UpgradeHandler interface {
 		HandleBackup(ctx context.Context, ibu *ibuv1.ImageBasedUpgrade) (ctrl.Result, error)
-		HandleRestore(ctx context.Context) (ctrl.Result, error)
+		HandleRestore(ctx context.Context, ibu *ibuv1.ImageBasedUpgrade) (ctrl.Result, error)
 		PostPivot(ctx context.Context, ibu *ibuv1.ImageBasedUpgrade) (ctrl.Result, error)
 		PrePivot(ctx context.Context, ibu *ibuv1.ImageBasedUpgrade) (ctrl.Result, error)
 	}
@@ -63,6 +69,7 @@ type (
 		RPMOstreeClient rpmostreeclient.IClient
 		OstreeClient    ostreeclient.IClient
 		RebootClient    reboot.RebootIntf
+		Clientset       *kubernetes.Clientset
 	}
 )
 
@@ -125,6 +132,12 @@ func (u *UpgHandler) PrePivot(ctx context.Context, ibu *ibuv1.ImageBasedUpgrade)
 	// start pre-pivot phase timer
 	utils.StartPhase(u.Client, u.Log, ibu, UpgradePhasePrepivot)
 
+	if msg := getUpgradeExpiredTimeout(ibu, UpgradePhasePrepivot, time.Now()); msg != "" {
+		u.Log.Error(fmt.Errorf("upgrade timed out"), msg)
+		utils.SetUpgradeStatusFailedWithReason(ibu, utils.ConditionReasons.TimedOut, msg)
+		return doNotRequeue(), nil
+	}
+
 	if prog := utils.GetInProgressCondition(ibu, ibuv1.Stages.Upgrade); prog == nil {
 		// Set in-progress status
 		u.resetProgressMessage(ctx, ibu)
@@ -138,6 +151,30 @@ func (u *UpgHandler) PrePivot(ctx context.Context, ibu *ibuv1.ImageBasedUpgrade)
 		return requeueWithHealthCheckInterval(), nil
 	}
 
+	if len(ibu.Spec.OADPContent) != 0 {
+		probed, err := probeBackupStorageLocations(ctx, u.BackupRestore, u.Log, ibu)
+		if err != nil {
+			if backuprestore.IsBRStorageBackendUnavailableError(err) {
+				msg := fmt.Sprintf("Waiting for system to stabilize before Upgrade (pre-pivot) stage can continue: %s", err.Error())
+				u.Log.Info(msg)
+				utils.SetUpgradeStatusInProgress(ibu, msg)
+				return requeueWithHealthCheckInterval(), nil
+			}
+			return requeueWithError(fmt.Errorf("failed to probe BackupStorageLocations: %w", err))
+		}
+		if !probed {
+			utils.SetUpgradeStatusInProgress(ibu, "Probing BackupStorageLocations")
+			return requeueWithShortInterval(), nil
+		}
+	}
+
+	u.Log.Info("Checking the seed image digest of the new stateroot")
+	if err := checkStaterootSeedImageDigest(ibu, filepath.Join(getStaterootPath(common.GetDesiredStaterootName(ibu)), common.SeedDataDir, common.SeedImageDigestFileName)); err != nil {
+		u.Log.Error(err, "Seed image digest changed")
+		utils.SetUpgradeStatusFailed(ibu, err.Error())
+		return doNotRequeue(), nil
+	}
+
 	utils.SetUpgradeStatusInProgress(ibu, "Backing up Application Data")
 	if updateErr := utils.UpdateIBUStatus(ctx, u.Client, ibu); updateErr != nil {
 		u.Log.Error(updateErr, "failed to update IBU CR status")
@@ -161,6 +198,33 @@ func (u *UpgHandler) PrePivot(ctx context.Context, ibu *ibuv1.ImageBasedUpgrade)
 		return ctrlResult, nil
 	}
 
+	u.Log.Info("Taking inventory snapshot")
+	if err := u.snapshotInventory(ctx); err != nil {
+		return requeueWithError(fmt.Errorf("error while taking inventory snapshot: %w", err))
+	}
+
+	if ibu.Spec.Quiesce != nil {
+		utils.SetUpgradeStatusInProgress(ibu, "Quiescing Workloads")
+		if updateErr := utils.UpdateIBUStatus(ctx, u.Client, ibu); updateErr != nil {
+			u.Log.Error(updateErr, "failed to update IBU CR status")
+		}
+
+		u.Log.Info("Quiescing workloads")
+		quiesced, err := u.quiesceWorkloads(ctx, ibu)
+		if err != nil {
+			if errors.Is(err, errQuiesceFailed) {
+				u.Log.Error(err, "Failed to quiesce workloads")
+				utils.SetUpgradeStatusFailed(ibu, err.Error())
+				return doNotRequeue(), nil
+			}
+			return requeueWithError(fmt.Errorf("error while quiescing workloads: %w", err))
+		}
+		if !quiesced {
+			utils.SetUpgradeStatusInProgress(ibu, "Quiescing of Workloads is in progress")
+			return requeueWithShortInterval(), nil
+		}
+	}
+
 	u.Log.Info("Remounting sysroot")
 	if err := u.Ops.RemountSysroot(); err != nil {
 		return requeueWithError(fmt.Errorf("error while remounting sysroot: %w", err))
@@ -184,6 +248,18 @@ func (u *UpgHandler) PrePivot(ctx context.Context, ibu *ibuv1.ImageBasedUpgrade)
 		return requeueWithError(fmt.Errorf("error while exporting OADP configuration and restores: %w", err))
 	}
 
+	if ibu.Spec.LocalBackup != nil {
+		u.Log.Info("Writing local backup into new stateroot")
+		if err := u.BackupRestore.ExportLocalBackupToDir(ctx, ibu.Spec.LocalBackup, staterootVarPath); err != nil {
+			if backuprestore.IsBRFailedError(err) || backuprestore.IsBRFailedValidationError(err) {
+				u.Log.Error(err, "Failed to export local backup")
+				utils.SetUpgradeStatusFailed(ibu, err.Error())
+				return doNotRequeue(), nil
+			}
+			return requeueWithError(fmt.Errorf("error while exporting local backup: %w", err))
+		}
+	}
+
 	utils.SetUpgradeStatusInProgress(ibu, "Exporting Policy and Config Manifests")
 	if updateErr := utils.UpdateIBUStatus(ctx, u.Client, ibu); updateErr != nil {
 		u.Log.Error(updateErr, "failed to update IBU CR status")
@@ -199,6 +275,13 @@ func (u *UpgHandler) PrePivot(ctx context.Context, ibu *ibuv1.ImageBasedUpgrade)
 		return requeueWithError(fmt.Errorf("error while exporting manifests: %w", err))
 	}
 
+	if len(ibu.Spec.PostUpgradeValidation) != 0 {
+		u.Log.Info("Writing post-upgrade validation Jobs into new stateroot")
+		if err := exportPostUpgradeValidationJobs(ctx, u.Client, ibu.Spec.PostUpgradeValidation, staterootVarPath); err != nil {
+			return requeueWithError(fmt.Errorf("error while exporting post-upgrade validation Jobs: %w", err))
+		}
+	}
+
 	utils.SetUpgradeStatusInProgress(ibu, "Exporting Cluster, LVM, and cert-manager configuration")
 	if updateErr := utils.UpdateIBUStatus(ctx, u.Client, ibu); updateErr != nil {
 		u.Log.Error(updateErr, "failed to update IBU CR status")
@@ -233,11 +316,28 @@ func (u *UpgHandler) PrePivot(ctx context.Context, ibu *ibuv1.ImageBasedUpgrade)
 		return requeueWithError(fmt.Errorf("error while exporting IBU CR to the new state root: %w", err))
 	}
 
+	u.Log.Info("Save the quiesce record to the new state root before pivot")
+	if err := exportQuiesceRecordToNewStateroot(staterootPath); err != nil {
+		return requeueWithError(err)
+	}
+
+	u.Log.Info("Save the inventory snapshot to the new state root before pivot")
+	if err := exportInventoryToNewStateroot(staterootPath); err != nil {
+		return requeueWithError(err)
+	}
+
 	u.Log.Info("Save a copy of the IBU in the current stateroot for rollback")
 	if err := exportForUncontrolledRollback(ibu); err != nil {
 		return requeueWithError(fmt.Errorf("error while exporting for uncontrolled rollback: %w", err))
 	}
 
+	// The maintenance window may have closed while the pre-pivot steps were running, wait for the next one to pivot
+	if result, msg := checkMaintenanceWindow(ibu, ibuv1.Stages.Upgrade, time.Now()); !result.IsZero() {
+		u.Log.Info("Pivot held by schedule", "reason", msg)
+		utils.SetUpgradeStatusInProgress(ibu, fmt.Sprintf("%s before rebooting to the new stateroot", msg))
+		return result, nil
+	}
+
 	if err := u.setDefaultDeploymentToNewStateroot(stateroot); err != nil {
 		return requeueWithError(fmt.Errorf("error while setting default deployment: %w", err))
 	}
@@ -266,7 +366,7 @@ func (u *UpgHandler) exportOadpConfigurationAndRestore(ctx context.Context, ibu
 	}
 
 	u.Log.Info("Writing Restore CRs into new stateroot")
-	if err := u.BackupRestore.ExportRestoresToDir(ctx, ibu.Spec.OADPContent, ostreeVarDir); err != nil {
+	if err := u.BackupRestore.ExportRestoresToDir(ctx, ibu.Spec.OADPContent, ibu.Status.RetainedBackupsSuffix, ostreeVarDir); err != nil {
 		return fmt.Errorf("failed to export restores: %w", err)
 	}
 
@@ -376,6 +476,13 @@ func (u *UpgHandler) PostPivot(ctx context.Context, ibu *ibuv1.ImageBasedUpgrade
 	// start post-pivot phase timer
 	utils.StartPhase(u.Client, u.Log, ibu, UpgradePhasePostpivot)
 
+	if msg := getUpgradeExpiredTimeout(ibu, UpgradePhasePostpivot, time.Now()); msg != "" {
+		u.Log.Error(fmt.Errorf("upgrade timed out"), msg)
+		utils.SetUpgradeStatusFailedWithReason(ibu, utils.ConditionReasons.TimedOut, msg)
+		u.autoRollbackIfEnabled(ibu, fmt.Sprintf("Rollback due to timeout: %s", msg))
+		return doNotRequeue(), nil
+	}
+
 	u.Log.Info("Starting health check for different components")
 	if err := CheckHealth(ctx, u.NoncachedClient, u.Log); err != nil {
 		utils.SetUpgradeStatusInProgress(ibu, fmt.Sprintf("Waiting for system to stabilize: %s", err.Error()))
@@ -400,7 +507,7 @@ func (u *UpgHandler) PostPivot(ctx context.Context, ibu *ibuv1.ImageBasedUpgrade
 		u.Log.Error(updateErr, "failed to update IBU CR status")
 	}
 
-	err = u.ExtraManifest.ApplyExtraManifests(ctx, common.PathOutsideChroot(extramanifest.PolicyManifestPath))
+	applied, err := u.ExtraManifest.ApplyExtraManifests(ctx, common.PathOutsideChroot(extramanifest.PolicyManifestPath))
 	if err != nil {
 		if extramanifest.IsEMFailedError(err) {
 			u.Log.Error(err, "Failed to apply policy manifests")
@@ -411,13 +518,17 @@ func (u *UpgHandler) PostPivot(ctx context.Context, ibu *ibuv1.ImageBasedUpgrade
 		utils.SetUpgradeStatusInProgress(ibu, fmt.Sprintf("Applying Policy Manifests: Failure occurred: %s", err.Error()))
 		return requeueWithError(fmt.Errorf("error while applying policy manifests: %w", err))
 	}
+	if !applied {
+		utils.SetUpgradeStatusInProgress(ibu, "Applying Policy Manifests: Waiting for the manifests to be ready")
+		return requeueWithShortInterval(), nil
+	}
 
 	utils.SetUpgradeStatusInProgress(ibu, "Applying Config Manifests")
 	if updateErr := utils.UpdateIBUStatus(ctx, u.Client, ibu); updateErr != nil {
 		u.Log.Error(updateErr, "failed to update IBU CR status")
 	}
 
-	err = u.ExtraManifest.ApplyExtraManifests(ctx, common.PathOutsideChroot(extramanifest.CmManifestPath))
+	applied, err = u.ExtraManifest.ApplyExtraManifests(ctx, common.PathOutsideChroot(extramanifest.CmManifestPath))
 	if err != nil {
 		if extramanifest.IsEMFailedError(err) {
 			u.Log.Error(err, "Failed to apply config manifests")
@@ -428,6 +539,34 @@ func (u *UpgHandler) PostPivot(ctx context.Context, ibu *ibuv1.ImageBasedUpgrade
 		utils.SetUpgradeStatusInProgress(ibu, fmt.Sprintf("Applying Config Manifests: Failure occurred: %s", err.Error()))
 		return requeueWithError(fmt.Errorf("error while applying config manifests: %w", err))
 	}
+	if !applied {
+		utils.SetUpgradeStatusInProgress(ibu, "Applying Config Manifests: Waiting for the manifests to be ready")
+		return requeueWithShortInterval(), nil
+	}
+
+	if ibu.Spec.LocalBackup != nil {
+		utils.SetUpgradeStatusInProgress(ibu, "Restoring Local Backup")
+		if updateErr := utils.UpdateIBUStatus(ctx, u.Client, ibu); updateErr != nil {
+			u.Log.Error(updateErr, "failed to update IBU CR status")
+		}
+
+		if err := u.BackupRestore.RestoreLocalBackup(ctx); err != nil {
+			if backuprestore.IsBRFailedError(err) {
+				u.Log.Error(err, "Failed to restore local backup")
+				utils.SetUpgradeStatusFailed(ibu, err.Error())
+				u.autoRollbackIfEnabled(ibu, fmt.Sprintf("Rollback due to failure restoring local backup: %s", err))
+				return doNotRequeue(), nil
+			}
+			utils.SetUpgradeStatusInProgress(ibu, fmt.Sprintf("Restoring Local Backup: Failure occurred: %s", err.Error()))
+			return requeueWithError(fmt.Errorf("error while restoring local backup: %w", err))
+		}
+
+		// The local backup includes the secrets of the applications
+		if err := os.RemoveAll(common.PathOutsideChroot(backuprestore.LocalBackupPath)); err != nil {
+			return requeueWithError(fmt.Errorf("error while removing local backup path: %w", err))
+		}
+		u.Log.Info("Local backup path removed", "path", backuprestore.LocalBackupPath)
+	}
 
 	// Handling restores with OADP operator
 	utils.SetUpgradeStatusInProgress(ibu, "Restoring Application Data")
@@ -435,7 +574,7 @@ func (u *UpgHandler) PostPivot(ctx context.Context, ibu *ibuv1.ImageBasedUpgrade
 		u.Log.Error(updateErr, "failed to update IBU CR status")
 	}
 
-	result, err := u.HandleRestore(ctx)
+	result, err := u.HandleRestore(ctx, ibu)
 	if err != nil {
 		// Restore failed
 		if backuprestore.IsBRFailedError(err) {
@@ -453,6 +592,52 @@ func (u *UpgHandler) PostPivot(ctx context.Context, ibu *ibuv1.ImageBasedUpgrade
 		return result, nil
 	}
 
+	if err := restoreQuiescedWorkloads(ctx, u.Client, u.Log); err != nil {
+		utils.SetUpgradeStatusInProgress(ibu, fmt.Sprintf("Restoring Quiesced Workloads: Failure occurred: %s", err))
+		return requeueWithError(fmt.Errorf("error while restoring quiesced workloads: %w", err))
+	}
+
+	if len(ibu.Spec.PostUpgradeValidation) != 0 {
+		utils.SetUpgradeStatusInProgress(ibu, "Running Post-Upgrade Validation")
+		if updateErr := utils.UpdateIBUStatus(ctx, u.Client, ibu); updateErr != nil {
+			u.Log.Error(updateErr, "failed to update IBU CR status")
+		}
+
+		validated, err := u.runPostUpgradeValidation(ctx)
+		if err != nil {
+			if errors.Is(err, errPostUpgradeValidationFailed) {
+				u.Log.Error(err, "Post-upgrade validation failed")
+				utils.SetUpgradeStatusFailed(ibu, err.Error())
+				u.autoRollbackIfEnabled(ibu, fmt.Sprintf("Rollback due to post-upgrade validation failure: %s", err))
+				return doNotRequeue(), nil
+			}
+			utils.SetUpgradeStatusInProgress(ibu, fmt.Sprintf("Running Post-Upgrade Validation: Failure occurred: %s", err))
+			return requeueWithError(fmt.Errorf("error while running post-upgrade validation: %w", err))
+		}
+		if !validated {
+			utils.SetUpgradeStatusInProgress(ibu, "Post-Upgrade Validation is in progress")
+			return requeueWithMediumInterval(), nil
+		}
+	}
+
+	regressions, err := u.compareInventory(ctx, ibu)
+	if err != nil {
+		utils.SetUpgradeStatusInProgress(ibu, fmt.Sprintf("Comparing Cluster Inventory: Failure occurred: %s", err))
+		return requeueWithError(fmt.Errorf("error while comparing cluster inventory: %w", err))
+	}
+	if len(regressions) > 0 {
+		u.Log.Info("Inventory regressions found", "regressions", ibu.Status.InventoryReport.Regressions)
+		if msg := getInventoryRegressionsExpiredTimeout(ibu, time.Now()); msg != "" {
+			u.Log.Error(fmt.Errorf("inventory regressions timed out"), msg)
+			utils.SetUpgradeStatusFailedWithReason(ibu, utils.ConditionReasons.TimedOut, msg)
+			u.autoRollbackIfEnabled(ibu, fmt.Sprintf("Rollback due to inventory regressions: %s", msg))
+			return doNotRequeue(), nil
+		}
+		utils.SetUpgradeStatusInProgress(ibu, fmt.Sprintf("Waiting for inventory regressions to recover: %s",
+			strings.Join(ibu.Status.InventoryReport.Regressions, "; ")))
+		return requeueWithMediumInterval(), nil
+	}
+
 	if err := u.RebootClient.DisableInitMonitor(); err != nil {
 		// Don't fail the upgrade on failure here, just log it
 		u.Log.Error(err, "Unable to disable LCA init monitor")
@@ -484,10 +669,25 @@ func (u *UpgHandler) HandleBackup(ctx context.Context, ibu *ibuv1.ImageBasedUpgr
 		return requeueWithError(fmt.Errorf("failed to patch LVMS PVs with Retain as persistentVolumeReclaimPolicy: %w", err))
 	}
 
+	// The suffix of the backups kept once the upgrade is finalized is recorded before the backups are created, so that
+	// the next reconciles, the restores and the cleanup refer to the same backups
+	if ibu.Status.RetainedBackupsSuffix == "" {
+		suffix := backuprestore.RetainedBackupsSuffix(ibu.Spec.BackupRetention, ibu.Spec.SeedImageRef.Version, time.Now())
+		if suffix != "" {
+			ibu.Status.RetainedBackupsSuffix = suffix
+			if err := utils.UpdateIBUStatus(ctx, u.Client, ibu); err != nil {
+				return requeueWithError(fmt.Errorf("failed to record the retained backups suffix: %w", err))
+			}
+		}
+	}
+
 	// trigger and track each group
 	for index, backups := range sortedBackupGroups {
 		u.Log.Info("Processing backup", "groupIndex", index+1, "totalGroups", len(sortedBackupGroups))
 
+		// name the backups kept once the upgrade is finalized, and set their Velero TTL, according to the retention policy
+		backuprestore.SetBackupsRetention(ibu.Spec.BackupRetention, ibu.Status.RetainedBackupsSuffix, backups)
+
 		// check for any stale backup in the group
 		if err := u.BackupRestore.CleanupStaleBackups(ctx, backups); err != nil {
 			return requeueWithError(fmt.Errorf("failed to cleanup stale Backups: %w", err))
@@ -498,8 +698,15 @@ func (u *UpgHandler) HandleBackup(ctx context.Context, ibu *ibuv1.ImageBasedUpgr
 			return requeueWithError(fmt.Errorf("error while starting or tracking backup: %w", err))
 		}
 
-		// The current backup group has done, work on the next group
+		// The current backup group has done, verify its contents and work on the next group
 		if len(backupTracker.SucceededBackups) == len(backups) {
+			verified, err := u.BackupRestore.VerifyBackups(ctx, backups)
+			if err != nil {
+				return requeueWithError(fmt.Errorf("error while verifying backups: %w", err))
+			}
+			if !verified {
+				return requeueWithShortInterval(), nil
+			}
 			continue
 		}
 
@@ -522,7 +729,7 @@ func (u *UpgHandler) HandleBackup(ctx context.Context, ibu *ibuv1.ImageBasedUpgr
 	return doNotRequeue(), nil
 }
 
-func (u *UpgHandler) HandleRestore(ctx context.Context) (ctrl.Result, error) {
+func (u *UpgHandler) HandleRestore(ctx context.Context, ibu *ibuv1.ImageBasedUpgrade) (ctrl.Result, error) {
 	u.Log.Info("Handling restores with OADP operator")
 	// Load restore CRs from files
 	sortedRestoreGroups, err := u.BackupRestore.LoadRestoresFromOadpRestorePath()
@@ -541,9 +748,17 @@ func (u *UpgHandler) HandleRestore(ctx context.Context) (ctrl.Result, error) {
 		if err != nil {
 			return requeueWithError(fmt.Errorf("error while starting or tracking restore: %w", err))
 		}
+		setToleratedRestoreErrors(ibu, restoreTracker.ToleratedRestores)
 
-		// The current restore group has done, work on the next group
+		// The current restore group has done, wait for its readiness gates then work on the next group
 		if len(restoreTracker.SucceededRestores) == len(restores) {
+			ready, err := u.BackupRestore.CheckRestoreReadiness(ctx, restores)
+			if err != nil {
+				return requeueWithError(fmt.Errorf("error while checking restore readiness gates: %w", err))
+			}
+			if !ready {
+				return requeueWithShortInterval(), nil
+			}
 			continue
 		}
 
@@ -575,6 +790,44 @@ func (u *UpgHandler) HandleRestore(ctx context.Context) (ctrl.Result, error) {
 	return doNotRequeue(), nil
 }
 
+// setToleratedRestoreErrors records the errors of the tolerated restores in the IBU status
+func setToleratedRestoreErrors(ibu *ibuv1.ImageBasedUpgrade, toleratedRestores []ibuv1.ToleratedRestoreErrors) {
+	for _, tolerated := range toleratedRestores {
+		_, index, found := lo.FindIndexOf(ibu.Status.ToleratedRestoreErrors, func(t ibuv1.ToleratedRestoreErrors) bool {
+			return t.Restore == tolerated.Restore
+		})
+		if found {
+			ibu.Status.ToleratedRestoreErrors[index] = tolerated
+		} else {
+			ibu.Status.ToleratedRestoreErrors = append(ibu.Status.ToleratedRestoreErrors, tolerated)
+		}
+	}
+}
+
+// probeBackupStorageLocations probes the OADP BackupStorageLocations once per stage: the result is recorded in the
+// BackupStorageProbed condition, and the probe is not run again once it passed. It returns whether the probe passed,
+// the probe being in progress otherwise unless an error is returned.
+func probeBackupStorageLocations(ctx context.Context, br backuprestore.BackuperRestorer, log logr.Logger, ibu *ibuv1.ImageBasedUpgrade) (bool, error) {
+	condition := meta.FindStatusCondition(ibu.Status.Conditions, string(utils.ConditionTypes.BackupStorageProbed))
+	if condition != nil && condition.Status == metav1.ConditionTrue && condition.ObservedGeneration == ibu.Generation {
+		return true, nil
+	}
+
+	log.Info("Probing BackupStorageLocations")
+	done, err := br.ProbeBackupStorageLocations(ctx)
+	if !done {
+		return false, err //nolint:wrapcheck
+	}
+	if err != nil {
+		utils.SetStatusCondition(&ibu.Status.Conditions, utils.ConditionTypes.BackupStorageProbed,
+			utils.ConditionReasons.Failed, metav1.ConditionFalse, err.Error(), ibu.Generation)
+		return false, err //nolint:wrapcheck
+	}
+	utils.SetStatusCondition(&ibu.Status.Conditions, utils.ConditionTypes.BackupStorageProbed,
+		utils.ConditionReasons.Completed, metav1.ConditionTrue, "BackupStorageLocations are writable", ibu.Generation)
+	return true, nil
+}
+
 // logManifestFiles lists all files in the manifests directory before pivot for debugging.
 func (u *UpgHandler) logManifestFiles(staterootVarPath string) {
 	manifestsDir := filepath.Join(staterootVarPath, common.OptOpenshift, common.ClusterConfigDir, clusterconfig.ManifestDir)
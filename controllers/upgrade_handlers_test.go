@@ -7,6 +7,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/go-logr/logr"
 	ibuv1 "github.com/openshift-kni/lifecycle-agent/api/imagebasedupgrade/v1"
@@ -24,6 +25,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	velerov1 "github.com/vmware-tanzu/velero/pkg/apis/velero/v1"
 	"go.uber.org/mock/gomock"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/tools/record"
 	controllerruntime "sigs.k8s.io/controller-runtime"
@@ -45,6 +47,8 @@ func TestImageBasedUpgradeReconciler_handleBackup(t *testing.T) {
 		name        string
 		inputVelero [][]*velerov1.Backup
 		trackers    []func() (*backuprestore.BackupTracker, error)
+		verifyErr   error
+		verifying   bool
 		wantCtlRes  controllerruntime.Result
 		wantErr     assert.ErrorAssertionFunc
 	}{
@@ -61,6 +65,36 @@ func TestImageBasedUpgradeReconciler_handleBackup(t *testing.T) {
 			wantCtlRes: doNotRequeue(),
 			wantErr:    assert.NoError,
 		},
+		{
+			name:        "backup verification failed",
+			inputVelero: [][]*velerov1.Backup{{&velerov1.Backup{}}},
+			trackers: []func() (*backuprestore.BackupTracker, error){
+				func() (*backuprestore.BackupTracker, error) {
+					return &backuprestore.BackupTracker{
+						SucceededBackups: []string{"name-successful"},
+					}, nil
+				},
+			},
+			verifyErr:  backuprestore.NewBRFailedError("Backup", "Backup verification failed: name-successful: no deployments.apps found"),
+			wantCtlRes: doNotRequeue(),
+			wantErr: func(t assert.TestingT, err error, i ...interface{}) bool {
+				return assert.True(t, backuprestore.IsBRFailedError(err), i...)
+			},
+		},
+		{
+			name:        "backup verification in progress",
+			inputVelero: [][]*velerov1.Backup{{&velerov1.Backup{}}},
+			trackers: []func() (*backuprestore.BackupTracker, error){
+				func() (*backuprestore.BackupTracker, error) {
+					return &backuprestore.BackupTracker{
+						SucceededBackups: []string{"name-successful"},
+					}, nil
+				},
+			},
+			verifying:  true,
+			wantCtlRes: requeueWithShortInterval(),
+			wantErr:    assert.NoError,
+		},
 		{
 			name:        "backup failed",
 			inputVelero: [][]*velerov1.Backup{{&velerov1.Backup{}}},
@@ -139,9 +173,13 @@ func TestImageBasedUpgradeReconciler_handleBackup(t *testing.T) {
 			mockBackuprestore.EXPECT().GetSortedBackupsFromConfigmap(gomock.Any(), gomock.Any()).Return(tt.inputVelero, nil)
 			mockBackuprestore.EXPECT().PatchPVsReclaimPolicy(gomock.Any()).Return(nil)
 
-			for _, track := range tt.trackers {
+			for i, track := range tt.trackers {
 				mockBackuprestore.EXPECT().CleanupStaleBackups(gomock.Any(), gomock.Any()).Return(nil)
-				mockBackuprestore.EXPECT().StartOrTrackBackup(gomock.Any(), gomock.Any()).Return(track())
+				tracker, err := track()
+				mockBackuprestore.EXPECT().StartOrTrackBackup(gomock.Any(), gomock.Any()).Return(tracker, err)
+				if err == nil && len(tracker.SucceededBackups) == len(tt.inputVelero[i]) {
+					mockBackuprestore.EXPECT().VerifyBackups(gomock.Any(), tt.inputVelero[i]).Return(!tt.verifying && tt.verifyErr == nil, tt.verifyErr)
+				}
 			}
 
 			// assert
@@ -162,6 +200,54 @@ func TestImageBasedUpgradeReconciler_handleBackup(t *testing.T) {
 	}
 }
 
+func TestImageBasedUpgradeReconciler_handleBackupRecordsRetainedBackupsSuffix(t *testing.T) {
+	mockController := gomock.NewController(t)
+	mockBackuprestore := mock_backuprestore.NewMockBackuperRestorer(mockController)
+	defer mockController.Finish()
+
+	ibu := &ibuv1.ImageBasedUpgrade{
+		ObjectMeta: metav1.ObjectMeta{Name: utils.IBUName},
+		Spec: ibuv1.ImageBasedUpgradeSpec{
+			SeedImageRef:    ibuv1.SeedImageRef{Version: "4.16.2"},
+			BackupRetention: &ibuv1.BackupRetention{Policy: ibuv1.BackupRetentionPolicies.KeepLast},
+		},
+	}
+	fakeClient, err := getFakeClientFromObjects(ibu)
+	assert.NoError(t, err)
+
+	var backupNames []string
+	mockBackuprestore.EXPECT().GetSortedBackupsFromConfigmap(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, content []ibuv1.ConfigMapRef) ([][]*velerov1.Backup, error) {
+			return [][]*velerov1.Backup{{{ObjectMeta: metav1.ObjectMeta{Name: "backup"}}}}, nil
+		}).Times(2)
+	mockBackuprestore.EXPECT().PatchPVsReclaimPolicy(gomock.Any()).Return(nil).Times(2)
+	mockBackuprestore.EXPECT().CleanupStaleBackups(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+	mockBackuprestore.EXPECT().StartOrTrackBackup(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, backups []*velerov1.Backup) (*backuprestore.BackupTracker, error) {
+			backupNames = append(backupNames, backups[0].Name)
+			return &backuprestore.BackupTracker{ProgressingBackups: []string{backups[0].Name}}, nil
+		}).Times(2)
+
+	uph := &UpgHandler{
+		Client:        fakeClient,
+		Log:           logr.Discard(),
+		BackupRestore: mockBackuprestore,
+	}
+	_, err = uph.HandleBackup(context.Background(), ibu)
+	assert.NoError(t, err)
+	suffix := ibu.Status.RetainedBackupsSuffix
+	assert.Regexp(t, `^4\.16\.2-[0-9]{14}$`, suffix)
+
+	// The suffix is persisted, and kept by the next reconciles
+	persisted := &ibuv1.ImageBasedUpgrade{}
+	assert.NoError(t, fakeClient.Get(context.Background(), client.ObjectKeyFromObject(ibu), persisted))
+	assert.Equal(t, suffix, persisted.Status.RetainedBackupsSuffix)
+	time.Sleep(time.Second)
+	_, err = uph.HandleBackup(context.Background(), persisted)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"backup-" + suffix, "backup-" + suffix}, backupNames)
+}
+
 func TestImageBasedUpgradeReconciler_handleRestore(t *testing.T) {
 	mockController := gomock.NewController(t)
 	mockBackuprestore := mock_backuprestore.NewMockBackuperRestorer(mockController)
@@ -173,9 +259,11 @@ func TestImageBasedUpgradeReconciler_handleRestore(t *testing.T) {
 		name                          string
 		inputVelero                   [][]*velerov1.Restore
 		trackers                      []func() (*backuprestore.RestoreTracker, error)
+		readinessReturn               func() (bool, error)
 		restorePVsReclaimPolicyReturn func() error
 		wantCtlRes                    controllerruntime.Result
 		wantErr                       assert.ErrorAssertionFunc
+		wantToleratedRestoreErrors    []ibuv1.ToleratedRestoreErrors
 	}{
 		{
 			name:        "restore successful",
@@ -193,6 +281,28 @@ func TestImageBasedUpgradeReconciler_handleRestore(t *testing.T) {
 			wantCtlRes: doNotRequeue(),
 			wantErr:    assert.NoError,
 		},
+		{
+			name:        "restore partially failed with tolerated errors",
+			inputVelero: [][]*velerov1.Restore{{&velerov1.Restore{}, &velerov1.Restore{}}},
+			trackers: []func() (*backuprestore.RestoreTracker, error){
+				func() (*backuprestore.RestoreTracker, error) {
+					return &backuprestore.RestoreTracker{
+						SucceededRestores: []string{"name-success", "name-tolerated"},
+						ToleratedRestores: []ibuv1.ToleratedRestoreErrors{
+							{Restore: "name-tolerated", Count: 1, Errors: []string{"my-app: error restoring events.events.k8s.io/my-app/event"}},
+						},
+					}, nil
+				},
+			},
+			restorePVsReclaimPolicyReturn: func() error {
+				return nil
+			},
+			wantCtlRes: doNotRequeue(),
+			wantErr:    assert.NoError,
+			wantToleratedRestoreErrors: []ibuv1.ToleratedRestoreErrors{
+				{Restore: "name-tolerated", Count: 1, Errors: []string{"my-app: error restoring events.events.k8s.io/my-app/event"}},
+			},
+		},
 		{
 			name:        "restore failed",
 			inputVelero: [][]*velerov1.Restore{{&velerov1.Restore{}}},
@@ -284,14 +394,56 @@ func TestImageBasedUpgradeReconciler_handleRestore(t *testing.T) {
 			wantCtlRes: doNotRequeue(),
 			wantErr:    assert.Error,
 		},
+		{
+			name:        "restore waiting for readiness gate",
+			inputVelero: [][]*velerov1.Restore{{&velerov1.Restore{}}},
+			trackers: []func() (*backuprestore.RestoreTracker, error){
+				func() (*backuprestore.RestoreTracker, error) {
+					return &backuprestore.RestoreTracker{
+						SucceededRestores: []string{"name-successful-1"},
+					}, nil
+				},
+			},
+			readinessReturn: func() (bool, error) {
+				return false, nil
+			},
+			wantCtlRes: requeueWithShortInterval(),
+			wantErr:    assert.NoError,
+		},
+		{
+			name:        "restore readiness gate timed out",
+			inputVelero: [][]*velerov1.Restore{{&velerov1.Restore{}}},
+			trackers: []func() (*backuprestore.RestoreTracker, error){
+				func() (*backuprestore.RestoreTracker, error) {
+					return &backuprestore.RestoreTracker{
+						SucceededRestores: []string{"name-successful-1"},
+					}, nil
+				},
+			},
+			readinessReturn: func() (bool, error) {
+				return false, backuprestore.NewBRFailedError("Restore", "Restore readiness gates timed out")
+			},
+			wantCtlRes: doNotRequeue(),
+			wantErr: func(t assert.TestingT, err error, i ...interface{}) bool {
+				return assert.True(t, backuprestore.IsBRFailedError(err), i...)
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			// setup
 			mockBackuprestore.EXPECT().LoadRestoresFromOadpRestorePath().Return(tt.inputVelero, nil).Times(1)
 
-			for _, track := range tt.trackers {
-				mockBackuprestore.EXPECT().StartOrTrackRestore(gomock.Any(), gomock.Any()).Return(track()).Times(1)
+			for i, track := range tt.trackers {
+				tracker, err := track()
+				mockBackuprestore.EXPECT().StartOrTrackRestore(gomock.Any(), gomock.Any()).Return(tracker, err).Times(1)
+				if err == nil && len(tracker.SucceededRestores) == len(tt.inputVelero[i]) {
+					ready, readinessErr := true, error(nil)
+					if tt.readinessReturn != nil {
+						ready, readinessErr = tt.readinessReturn()
+					}
+					mockBackuprestore.EXPECT().CheckRestoreReadiness(gomock.Any(), gomock.Any()).Return(ready, readinessErr).Times(1)
+				}
 			}
 
 			if tt.restorePVsReclaimPolicyReturn != nil {
@@ -306,11 +458,13 @@ func TestImageBasedUpgradeReconciler_handleRestore(t *testing.T) {
 				Log:             logr.Logger{},
 				BackupRestore:   mockBackuprestore,
 			}
-			got, err := uph.HandleRestore(context.Background())
-			if !tt.wantErr(t, err, fmt.Sprintf("handleRestore(%v, %v)", context.Background(), &ibuv1.ImageBasedUpgrade{})) {
+			ibu := &ibuv1.ImageBasedUpgrade{}
+			got, err := uph.HandleRestore(context.Background(), ibu)
+			if !tt.wantErr(t, err, fmt.Sprintf("handleRestore(%v, %v)", context.Background(), ibu)) {
 				return
 			}
 			assert.Equalf(t, tt.wantCtlRes.RequeueAfter, got.RequeueAfter, "ctl interval: handleRestore")
+			assert.Equal(t, tt.wantToleratedRestoreErrors, ibu.Status.ToleratedRestoreErrors)
 
 		})
 	}
@@ -342,6 +496,7 @@ func TestImageBasedUpgradeReconciler_prePivot(t *testing.T) {
 		name                                            string
 		args                                            args
 		healthCheckError                                error
+		probeBackupStorageLocationsError                error
 		getSortedBackupsFromConfigmapReturn             func() ([][]*velerov1.Backup, error)
 		getStartOrTrackBackupReturn                     func() (*backuprestore.BackupTracker, error)
 		getPatchPVsReclaimPolicyReturn                  func() error
@@ -379,6 +534,33 @@ func TestImageBasedUpgradeReconciler_prePivot(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "Pre-pivot upgrade requests requeue because of BackupStorageLocations probe fail",
+			args: args{
+				ibu: ibuv1.ImageBasedUpgrade{
+					Spec: ibuv1.ImageBasedUpgradeSpec{
+						OADPContent: []ibuv1.ConfigMapRef{{Name: "oadp-cm"}},
+					},
+				},
+			},
+			probeBackupStorageLocationsError: backuprestore.NewBRStorageBackendUnavailableError("BackupStorageLocations probe backups failed: default: Failed"),
+			want:                             requeueWithHealthCheckInterval(),
+			wantErr:                          assert.NoError,
+			wantConditions: []metav1.Condition{
+				{
+					Type:    string(utils.ConditionTypes.UpgradeInProgress),
+					Reason:  string(utils.ConditionReasons.InProgress),
+					Status:  metav1.ConditionTrue,
+					Message: "Waiting for system to stabilize before Upgrade (pre-pivot) stage can continue: BackupStorageLocations probe backups failed: default: Failed",
+				},
+				{
+					Type:    string(utils.ConditionTypes.BackupStorageProbed),
+					Reason:  string(utils.ConditionReasons.Failed),
+					Status:  metav1.ConditionFalse,
+					Message: "BackupStorageLocations probe backups failed: default: Failed",
+				},
+			},
+		},
 		{
 			name: "backup failed request no requeue",
 			args: args{
@@ -503,6 +685,12 @@ func TestImageBasedUpgradeReconciler_prePivot(t *testing.T) {
 			want:    doNotRequeue(),
 			wantErr: assert.NoError,
 			wantConditions: []metav1.Condition{
+				{
+					Type:    string(utils.ConditionTypes.BackupStorageProbed),
+					Reason:  string(utils.ConditionReasons.Completed),
+					Status:  metav1.ConditionTrue,
+					Message: "BackupStorageLocations are writable",
+				},
 				{
 					Type:    string(utils.ConditionTypes.UpgradeCompleted),
 					Reason:  string(utils.ConditionReasons.Failed),
@@ -540,6 +728,12 @@ func TestImageBasedUpgradeReconciler_prePivot(t *testing.T) {
 					Status:  metav1.ConditionTrue,
 					Message: "Exporting Application Configuration",
 				},
+				{
+					Type:    string(utils.ConditionTypes.BackupStorageProbed),
+					Reason:  string(utils.ConditionReasons.Completed),
+					Status:  metav1.ConditionTrue,
+					Message: "BackupStorageLocations are writable",
+				},
 			},
 		},
 		{
@@ -563,6 +757,12 @@ func TestImageBasedUpgradeReconciler_prePivot(t *testing.T) {
 			want:    doNotRequeue(),
 			wantErr: assert.NoError,
 			wantConditions: []metav1.Condition{
+				{
+					Type:    string(utils.ConditionTypes.BackupStorageProbed),
+					Reason:  string(utils.ConditionReasons.Completed),
+					Status:  metav1.ConditionTrue,
+					Message: "BackupStorageLocations are writable",
+				},
 				{
 					Type:    string(utils.ConditionTypes.UpgradeCompleted),
 					Reason:  string(utils.ConditionReasons.Failed),
@@ -604,6 +804,12 @@ func TestImageBasedUpgradeReconciler_prePivot(t *testing.T) {
 					Status:  metav1.ConditionTrue,
 					Message: "Exporting Application Configuration",
 				},
+				{
+					Type:    string(utils.ConditionTypes.BackupStorageProbed),
+					Reason:  string(utils.ConditionReasons.Completed),
+					Status:  metav1.ConditionTrue,
+					Message: "BackupStorageLocations are writable",
+				},
 			},
 		},
 		{
@@ -642,6 +848,12 @@ func TestImageBasedUpgradeReconciler_prePivot(t *testing.T) {
 					Status:  metav1.ConditionTrue,
 					Message: "Exporting Policy and Config Manifests",
 				},
+				{
+					Type:    string(utils.ConditionTypes.BackupStorageProbed),
+					Reason:  string(utils.ConditionReasons.Completed),
+					Status:  metav1.ConditionTrue,
+					Message: "BackupStorageLocations are writable",
+				},
 			},
 		},
 		{
@@ -683,6 +895,12 @@ func TestImageBasedUpgradeReconciler_prePivot(t *testing.T) {
 					Status:  metav1.ConditionTrue,
 					Message: "Exporting Cluster, LVM, and cert-manager configuration",
 				},
+				{
+					Type:    string(utils.ConditionTypes.BackupStorageProbed),
+					Reason:  string(utils.ConditionReasons.Completed),
+					Status:  metav1.ConditionTrue,
+					Message: "BackupStorageLocations are writable",
+				},
 			},
 		},
 		{
@@ -730,6 +948,12 @@ func TestImageBasedUpgradeReconciler_prePivot(t *testing.T) {
 			want:    doNotRequeue(),
 			wantErr: assert.NoError,
 			wantConditions: []metav1.Condition{
+				{
+					Type:    string(utils.ConditionTypes.BackupStorageProbed),
+					Reason:  string(utils.ConditionReasons.Completed),
+					Status:  metav1.ConditionTrue,
+					Message: "BackupStorageLocations are writable",
+				},
 				{
 					Type:    string(utils.ConditionTypes.UpgradeCompleted),
 					Reason:  string(utils.ConditionReasons.Failed),
@@ -744,7 +968,79 @@ func TestImageBasedUpgradeReconciler_prePivot(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "Pivot held by schedule after the pre-pivot steps",
+			args: args{
+				ibu: ibuv1.ImageBasedUpgrade{
+					Spec: ibuv1.ImageBasedUpgradeSpec{
+						Stage:        ibuv1.Stages.Upgrade,
+						SeedImageRef: ibuv1.SeedImageRef{Version: "4.15.2"},
+						Schedule: &ibuv1.Schedule{
+							Windows: []ibuv1.MaintenanceWindow{
+								{
+									Start: metav1.NewTime(time.Now().Add(-4 * time.Hour)),
+									End:   metav1.NewTime(time.Now().Add(-2 * time.Hour)),
+								},
+							},
+						},
+					},
+				},
+			},
+			getSortedBackupsFromConfigmapReturn: func() ([][]*velerov1.Backup, error) {
+				return nil, nil
+			},
+			remountSysrootReturn: func() error {
+				return nil
+			},
+			exportOadpConfigurationToDirReturn: func() error {
+				return nil
+			},
+			exportRestoresToDirReturn: func() error {
+				return nil
+			},
+			extractAndExportManifestFromPoliciesToDirReturn: func() error {
+				return nil
+			},
+			exportExtraManifestToDirReturn: func() error {
+				return nil
+			},
+			fetchClusterConfigReturn: func() error {
+				return nil
+			},
+			fetchLvmConfigReturn: func() error {
+				return nil
+			},
+			fetchCertManagerConfigReturn: func() error {
+				return nil
+			},
+			exportIBUCRNew: true,
+			want:           requeueWithLongInterval(),
+			wantErr:        assert.NoError,
+			wantConditions: []metav1.Condition{
+				{
+					Type:    string(utils.ConditionTypes.UpgradeInProgress),
+					Reason:  string(utils.ConditionReasons.InProgress),
+					Status:  metav1.ConditionTrue,
+					Message: "Waiting for a maintenance window: no upcoming window in spec.schedule before rebooting to the new stateroot",
+				},
+				{
+					Type:    string(utils.ConditionTypes.BackupStorageProbed),
+					Reason:  string(utils.ConditionReasons.Completed),
+					Status:  metav1.ConditionTrue,
+					Message: "BackupStorageLocations are writable",
+				},
+			},
+		},
 	}
+
+	// The inventory is already taken
+	origInventoryPath := inventoryPath
+	defer func() {
+		inventoryPath = origInventoryPath
+	}()
+	inventoryPath = filepath.Join(t.TempDir(), "inventory.json")
+	assert.NoError(t, os.WriteFile(inventoryPath, []byte("{}"), 0o600))
+
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			if tt.getSortedBackupsFromConfigmapReturn != nil {
@@ -767,9 +1063,12 @@ func TestImageBasedUpgradeReconciler_prePivot(t *testing.T) {
 				mockOps.EXPECT().RemountSysroot().Return(tt.remountSysrootReturn())
 			}
 			if tt.exportRestoresToDirReturn != nil {
-				mockBackuprestore.EXPECT().ExportRestoresToDir(gomock.Any(), gomock.Any(), gomock.Any()).Return(tt.exportRestoresToDirReturn()).Times(1)
+				mockBackuprestore.EXPECT().ExportRestoresToDir(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(tt.exportRestoresToDirReturn()).Times(1)
 				tt.args.ibu.Spec.OADPContent = []ibuv1.ConfigMapRef{{Name: "atleast-one-restore-to-proceed-with-export"}}
 			}
+			if len(tt.args.ibu.Spec.OADPContent) != 0 && tt.healthCheckError == nil {
+				mockBackuprestore.EXPECT().ProbeBackupStorageLocations(gomock.Any()).Return(true, tt.probeBackupStorageLocationsError)
+			}
 			if tt.extractAndExportManifestFromPoliciesToDirReturn != nil {
 				mockExtramanifest.EXPECT().ExtractAndExportManifestFromPoliciesToDir(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(tt.extractAndExportManifestFromPoliciesToDirReturn()).Times(1)
 			}
@@ -880,9 +1179,9 @@ func TestImageBasedUpgradeReconciler_prePivot(t *testing.T) {
 					dat, _ := os.ReadFile(filepath.Join(ibuTempDirOrig, utils.IBUFilePath))
 					savedIbu := ibuv1.ImageBasedUpgrade{}
 					_ = yaml.Unmarshal(dat, &savedIbu)
-					assert.Equalf(t, len(savedIbu.Status.Conditions), 2, "")
-					assert.Equalf(t, savedIbu.Status.Conditions[0].Message, utils.UpgradeFailed, "")
-					assert.Equalf(t, savedIbu.Status.Conditions[1].Message, "Uncontrolled rollback", "")
+					assert.Equalf(t, len(savedIbu.Status.Conditions), 3, "")
+					assert.Equalf(t, savedIbu.Status.Conditions[1].Message, utils.UpgradeFailed, "")
+					assert.Equalf(t, savedIbu.Status.Conditions[2].Message, "Uncontrolled rollback", "")
 				}
 			}
 		})
@@ -900,6 +1199,21 @@ func TestImageBasedUpgradeReconciler_postPivot(t *testing.T) {
 		mockController.Finish()
 	}()
 
+	postPivotStart := metav1.NewTime(time.Now().Add(-2 * time.Hour))
+	timedOutIBU := &ibuv1.ImageBasedUpgrade{
+		Spec: ibuv1.ImageBasedUpgradeSpec{
+			Stage:    ibuv1.Stages.Upgrade,
+			Timeouts: &ibuv1.Timeouts{PostPivot: &metav1.Duration{Duration: time.Hour}},
+		},
+		Status: ibuv1.ImageBasedUpgradeStatus{
+			History: []*ibuv1.History{{
+				Stage:     ibuv1.Stages.Upgrade,
+				StartTime: metav1.NewTime(postPivotStart.Add(-time.Hour)),
+				Phases:    []*ibuv1.Phase{{Phase: UpgradePhasePostpivot, StartTime: postPivotStart}},
+			}},
+		},
+	}
+
 	type fields struct {
 		BackupRestore  backuprestore.BackuperRestorer
 		ExtraManifest  extramanifest.EManifestHandler
@@ -916,8 +1230,8 @@ func TestImageBasedUpgradeReconciler_postPivot(t *testing.T) {
 		want                              controllerruntime.Result
 		wantErr                           assert.ErrorAssertionFunc
 		checkHealthReturn                 func(ctx context.Context, c client.Reader, l logr.Logger) error
-		applyExtraManifestsReturn         func() error
-		applyPolicyManifestsReturn        func() error
+		applyExtraManifestsReturn         func() (bool, error)
+		applyPolicyManifestsReturn        func() (bool, error)
 		ensureOadpConfigurationReturn     func() error
 		loadRestoresFromOadpRestoreReturn func() ([][]*velerov1.Restore, error)
 		startOrTrackRestoreReturn         func() (*backuprestore.RestoreTracker, error)
@@ -943,6 +1257,28 @@ func TestImageBasedUpgradeReconciler_postPivot(t *testing.T) {
 			want:    requeueWithHealthCheckInterval(),
 			wantErr: assert.NoError,
 		},
+		{
+			name: "post-pivot timeout expired",
+			args: args{ibu: timedOutIBU},
+			initiateRollbackReturn: func() error {
+				return nil
+			},
+			wantConditions: []metav1.Condition{
+				{
+					Type:    string(utils.ConditionTypes.UpgradeCompleted),
+					Reason:  string(utils.ConditionReasons.TimedOut),
+					Status:  metav1.ConditionFalse,
+					Message: utils.UpgradeFailed,
+				},
+				{
+					Type:    string(utils.ConditionTypes.UpgradeInProgress),
+					Reason:  string(utils.ConditionReasons.TimedOut),
+					Status:  metav1.ConditionFalse,
+					Message: "Upgrade PostPivot phase timed out after 1h0m0s, started at " + postPivotStart.UTC().Format(time.RFC3339),
+				},
+			},
+			wantErr: assert.NoError,
+		},
 		{
 			name: "extraManifests return error",
 			args: args{ibu: &ibuv1.ImageBasedUpgrade{}},
@@ -952,11 +1288,11 @@ func TestImageBasedUpgradeReconciler_postPivot(t *testing.T) {
 			ensureOadpConfigurationReturn: func() error {
 				return nil
 			},
-			applyPolicyManifestsReturn: func() error {
-				return nil
+			applyPolicyManifestsReturn: func() (bool, error) {
+				return true, nil
 			},
-			applyExtraManifestsReturn: func() error {
-				return extramanifest.NewEMFailedError("Test error EM")
+			applyExtraManifestsReturn: func() (bool, error) {
+				return false, extramanifest.NewEMFailedError("Test error EM")
 			},
 			initiateRollbackReturn: func() error {
 				return nil
@@ -977,6 +1313,29 @@ func TestImageBasedUpgradeReconciler_postPivot(t *testing.T) {
 			},
 			wantErr: assert.NoError,
 		},
+		{
+			name: "policy manifests not ready yet",
+			args: args{ibu: &ibuv1.ImageBasedUpgrade{}},
+			checkHealthReturn: func(ctx context.Context, c client.Reader, l logr.Logger) error {
+				return nil
+			},
+			ensureOadpConfigurationReturn: func() error {
+				return nil
+			},
+			applyPolicyManifestsReturn: func() (bool, error) {
+				return false, nil
+			},
+			wantConditions: []metav1.Condition{
+				{
+					Type:    string(utils.ConditionTypes.UpgradeInProgress),
+					Reason:  string(utils.ConditionReasons.InProgress),
+					Status:  metav1.ConditionTrue,
+					Message: "Applying Policy Manifests: Waiting for the manifests to be ready",
+				},
+			},
+			want:    requeueWithShortInterval(),
+			wantErr: assert.NoError,
+		},
 		{
 			name: "RestoreOadpConfigurations return error",
 			args: args{ibu: &ibuv1.ImageBasedUpgrade{}},
@@ -1011,11 +1370,11 @@ func TestImageBasedUpgradeReconciler_postPivot(t *testing.T) {
 			checkHealthReturn: func(ctx context.Context, c client.Reader, l logr.Logger) error {
 				return nil
 			},
-			applyPolicyManifestsReturn: func() error {
-				return nil
+			applyPolicyManifestsReturn: func() (bool, error) {
+				return true, nil
 			},
-			applyExtraManifestsReturn: func() error {
-				return nil
+			applyExtraManifestsReturn: func() (bool, error) {
+				return true, nil
 			},
 			ensureOadpConfigurationReturn: func() error {
 				return nil
@@ -1051,11 +1410,11 @@ func TestImageBasedUpgradeReconciler_postPivot(t *testing.T) {
 			checkHealthReturn: func(ctx context.Context, c client.Reader, l logr.Logger) error {
 				return nil
 			},
-			applyPolicyManifestsReturn: func() error {
-				return nil
+			applyPolicyManifestsReturn: func() (bool, error) {
+				return true, nil
 			},
-			applyExtraManifestsReturn: func() error {
-				return nil
+			applyExtraManifestsReturn: func() (bool, error) {
+				return true, nil
 			},
 			ensureOadpConfigurationReturn: func() error {
 				return nil
@@ -1143,3 +1502,46 @@ func TestImageBasedUpgradeReconciler_postPivot(t *testing.T) {
 		})
 	}
 }
+
+func TestProbeBackupStorageLocationsOncePerStage(t *testing.T) {
+	mockController := gomock.NewController(t)
+	mockBackuprestore := mock_backuprestore.NewMockBackuperRestorer(mockController)
+	defer func() {
+		mockController.Finish()
+	}()
+	ibu := &ibuv1.ImageBasedUpgrade{ObjectMeta: metav1.ObjectMeta{Generation: 2}}
+
+	// The probe is in progress
+	mockBackuprestore.EXPECT().ProbeBackupStorageLocations(gomock.Any()).Return(false, nil)
+	probed, err := probeBackupStorageLocations(context.Background(), mockBackuprestore, logr.Discard(), ibu)
+	assert.NoError(t, err)
+	assert.False(t, probed)
+	assert.Empty(t, ibu.Status.Conditions)
+
+	// The failure is recorded, and the probe is run again
+	probeErr := backuprestore.NewBRStorageBackendUnavailableError("BackupStorageLocations probe backups failed: default: Failed")
+	mockBackuprestore.EXPECT().ProbeBackupStorageLocations(gomock.Any()).Return(true, probeErr)
+	probed, err = probeBackupStorageLocations(context.Background(), mockBackuprestore, logr.Discard(), ibu)
+	assert.Equal(t, probeErr, err)
+	assert.False(t, probed)
+	condition := meta.FindStatusCondition(ibu.Status.Conditions, string(utils.ConditionTypes.BackupStorageProbed))
+	assert.Equal(t, metav1.ConditionFalse, condition.Status)
+	assert.Equal(t, probeErr.Error(), condition.Message)
+
+	mockBackuprestore.EXPECT().ProbeBackupStorageLocations(gomock.Any()).Return(true, nil)
+	probed, err = probeBackupStorageLocations(context.Background(), mockBackuprestore, logr.Discard(), ibu)
+	assert.NoError(t, err)
+	assert.True(t, probed)
+
+	// The probe passed in this stage, it is not run again
+	probed, err = probeBackupStorageLocations(context.Background(), mockBackuprestore, logr.Discard(), ibu)
+	assert.NoError(t, err)
+	assert.True(t, probed)
+
+	// The next stage runs it again
+	ibu.Generation++
+	mockBackuprestore.EXPECT().ProbeBackupStorageLocations(gomock.Any()).Return(false, nil)
+	probed, err = probeBackupStorageLocations(context.Background(), mockBackuprestore, logr.Discard(), ibu)
+	assert.NoError(t, err)
+	assert.False(t, probed)
+}
@@ -3,12 +3,14 @@ package utils
 import (
 	"context"
 	"fmt"
+	"sync"
 
 	"k8s.io/apimachinery/pkg/api/meta"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	ibuv1 "github.com/openshift-kni/lifecycle-agent/api/imagebasedupgrade/v1"
 	ipcv1 "github.com/openshift-kni/lifecycle-agent/api/ipconfig/v1"
+	"github.com/openshift-kni/lifecycle-agent/internal/metrics"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -28,18 +30,21 @@ var ConditionTypes = struct {
 	SeedGenCompleted   ConditionType
 	ConfigInProgress   ConditionType
 	ConfigCompleted    ConditionType
+	// BackupStorageProbed records the result of the probe of the OADP BackupStorageLocations, run once per stage
+	BackupStorageProbed ConditionType
 }{
-	Idle:               "Idle",
-	PrepInProgress:     "PrepInProgress",
-	PrepCompleted:      "PrepCompleted",
-	UpgradeInProgress:  "UpgradeInProgress",
-	UpgradeCompleted:   "UpgradeCompleted",
-	RollbackInProgress: "RollbackInProgress",
-	RollbackCompleted:  "RollbackCompleted",
-	SeedGenInProgress:  "SeedGenInProgress",
-	SeedGenCompleted:   "SeedGenCompleted",
-	ConfigInProgress:   "ConfigInProgress",
-	ConfigCompleted:    "ConfigCompleted",
+	Idle:                "Idle",
+	PrepInProgress:      "PrepInProgress",
+	PrepCompleted:       "PrepCompleted",
+	UpgradeInProgress:   "UpgradeInProgress",
+	UpgradeCompleted:    "UpgradeCompleted",
+	RollbackInProgress:  "RollbackInProgress",
+	RollbackCompleted:   "RollbackCompleted",
+	SeedGenInProgress:   "SeedGenInProgress",
+	SeedGenCompleted:    "SeedGenCompleted",
+	ConfigInProgress:    "ConfigInProgress",
+	ConfigCompleted:     "ConfigCompleted",
+	BackupStorageProbed: "BackupStorageProbed",
 }
 
 var SeedGenConditionTypes = struct {
@@ -58,20 +63,22 @@ type ConditionReason string
 
 // ConditionReasons define the different reasons that conditions will be set for
 var ConditionReasons = struct {
-	Idle                    ConditionReason
-	ConfigurationInProgress ConditionReason
-	Completed               ConditionReason
-	Failed                  ConditionReason
-	TimedOut                ConditionReason
-	InProgress              ConditionReason
-	Aborting                ConditionReason
-	AbortCompleted          ConditionReason
-	AbortFailed             ConditionReason
-	Finalizing              ConditionReason
-	FinalizeCompleted       ConditionReason
-	FinalizeFailed          ConditionReason
-	InvalidTransition       ConditionReason
-	Blocked                 ConditionReason
+	Idle                        ConditionReason
+	ConfigurationInProgress     ConditionReason
+	Completed                   ConditionReason
+	Failed                      ConditionReason
+	TimedOut                    ConditionReason
+	InProgress                  ConditionReason
+	Aborting                    ConditionReason
+	AbortCompleted              ConditionReason
+	AbortFailed                 ConditionReason
+	Finalizing                  ConditionReason
+	FinalizeCompleted           ConditionReason
+	FinalizeFailed              ConditionReason
+	InvalidTransition           ConditionReason
+	Blocked                     ConditionReason
+	Scheduled                   ConditionReason
+	SignatureVerificationFailed ConditionReason
 }{
 	Idle:                    "Idle",
 	ConfigurationInProgress: "ConfigurationInProgress",
@@ -89,6 +96,10 @@ var ConditionReasons = struct {
 	// Blocked condition reason is used to specify IPC or IBU is blocked by each other.
 	// They are not allowed to run their flows simultaneously due to conflicts.
 	Blocked: "Blocked",
+	// Scheduled condition reason is used to specify the stage is waiting for a maintenance window to open.
+	Scheduled: "Scheduled",
+	// SignatureVerificationFailed condition reason is used to specify the seed image signature could not be verified.
+	SignatureVerificationFailed: "SignatureVerificationFailed",
 }
 
 // Common condition messages
@@ -373,17 +384,67 @@ func SetStatusInvalidTransition(ibu *ibuv1.ImageBasedUpgrade, msg string) {
 	)
 }
 
+// pendingFailures holds the stage failures set in the status that has not been written to the cluster yet, keyed by
+// stage and reason. They are only counted once the status is written, so a failed status update followed by a retry
+// of the failing step counts the failure once.
+type pendingFailures struct {
+	mux      sync.Mutex
+	failures map[string]func()
+}
+
+func (p *pendingFailures) add(key string, record func()) {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	if p.failures == nil {
+		p.failures = make(map[string]func())
+	}
+	p.failures[key] = record
+}
+
+// flush counts the pending failures once the status is written
+func (p *pendingFailures) flush() {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	for _, record := range p.failures {
+		record()
+	}
+	p.failures = nil
+}
+
+var (
+	pendingIBUFailures pendingFailures
+	pendingIPCFailures pendingFailures
+)
+
+// recordIBUFailure counts the failure of the stage once the status is written, unless the stage has already failed
+// with the same reason
+func recordIBUFailure(ibu *ibuv1.ImageBasedUpgrade, stage ibuv1.ImageBasedUpgradeStage, reason ConditionReason) {
+	condition := meta.FindStatusCondition(ibu.Status.Conditions, string(GetCompletedConditionType(stage)))
+	if condition != nil && condition.Status == metav1.ConditionFalse && condition.Reason == string(reason) {
+		return
+	}
+	pendingIBUFailures.add(fmt.Sprintf("%s/%s", stage, reason), func() {
+		metrics.RecordIBUFailure(stage, string(reason))
+	})
+}
+
 // SetUpgradeStatusFailed updates the upgrade status to failed with message
 func SetUpgradeStatusFailed(ibu *ibuv1.ImageBasedUpgrade, msg string) {
+	SetUpgradeStatusFailedWithReason(ibu, ConditionReasons.Failed, msg)
+}
+
+// SetUpgradeStatusFailedWithReason updates the upgrade status to failed with reason and message
+func SetUpgradeStatusFailedWithReason(ibu *ibuv1.ImageBasedUpgrade, reason ConditionReason, msg string) {
+	recordIBUFailure(ibu, ibuv1.Stages.Upgrade, reason)
 	SetStatusCondition(&ibu.Status.Conditions,
 		GetCompletedConditionType(ibuv1.Stages.Upgrade),
-		ConditionReasons.Failed,
+		reason,
 		metav1.ConditionFalse,
 		UpgradeFailed,
 		ibu.Generation)
 	SetStatusCondition(&ibu.Status.Conditions,
 		GetInProgressConditionType(ibuv1.Stages.Upgrade),
-		ConditionReasons.Failed,
+		reason,
 		metav1.ConditionFalse,
 		msg,
 		ibu.Generation)
@@ -443,15 +504,21 @@ func SetPrepStatusInProgress(ibu *ibuv1.ImageBasedUpgrade, msg string) {
 
 // SetPrepStatusFailed updates the prep status to failed with message
 func SetPrepStatusFailed(ibu *ibuv1.ImageBasedUpgrade, msg string) {
+	SetPrepStatusFailedWithReason(ibu, ConditionReasons.Failed, msg)
+}
+
+// SetPrepStatusFailedWithReason updates the prep status to failed with reason and message
+func SetPrepStatusFailedWithReason(ibu *ibuv1.ImageBasedUpgrade, reason ConditionReason, msg string) {
+	recordIBUFailure(ibu, ibuv1.Stages.Prep, reason)
 	SetStatusCondition(&ibu.Status.Conditions,
 		GetCompletedConditionType(ibuv1.Stages.Prep),
-		ConditionReasons.Failed,
+		reason,
 		metav1.ConditionFalse,
 		PrepFailed,
 		ibu.Generation)
 	SetStatusCondition(&ibu.Status.Conditions,
 		GetInProgressConditionType(ibuv1.Stages.Prep),
-		ConditionReasons.Failed,
+		reason,
 		metav1.ConditionFalse,
 		msg,
 		ibu.Generation)
@@ -475,6 +542,7 @@ func SetPrepStatusCompleted(ibu *ibuv1.ImageBasedUpgrade, msg string) {
 
 // SetRollbackStatusFailed updates the Rollback status to failed with message
 func SetRollbackStatusFailed(ibu *ibuv1.ImageBasedUpgrade, msg string) {
+	recordIBUFailure(ibu, ibuv1.Stages.Rollback, ConditionReasons.Failed)
 	SetStatusCondition(&ibu.Status.Conditions,
 		GetCompletedConditionType(ibuv1.Stages.Rollback),
 		ConditionReasons.Failed,
@@ -558,6 +626,27 @@ func IsIBUStatusBlocked(ibu *ibuv1.ImageBasedUpgrade, stage ibuv1.ImageBasedUpgr
 	return condition != nil && condition.Reason == string(ConditionReasons.Blocked)
 }
 
+// SetIBUStatusScheduled updates the given IBU stage in-progress status to Scheduled with message.
+func SetIBUStatusScheduled(ibu *ibuv1.ImageBasedUpgrade, msg string) {
+	ct := GetInProgressConditionType(ibu.Spec.Stage)
+	if ct == "" {
+		return
+	}
+	SetStatusCondition(&ibu.Status.Conditions,
+		ct,
+		ConditionReasons.Scheduled,
+		metav1.ConditionFalse,
+		msg,
+		ibu.Generation,
+	)
+}
+
+// IsIBUStatusScheduled checks if the given IBU stage in-progress status is Scheduled.
+func IsIBUStatusScheduled(ibu *ibuv1.ImageBasedUpgrade, stage ibuv1.ImageBasedUpgradeStage) bool {
+	condition := GetInProgressCondition(ibu, stage)
+	return condition != nil && condition.Reason == string(ConditionReasons.Scheduled)
+}
+
 func UpdateIBUStatus(ctx context.Context, c client.Client, ibu *ibuv1.ImageBasedUpgrade) error {
 	if c == nil {
 		// In UT code
@@ -578,6 +667,8 @@ func UpdateIBUStatus(ctx context.Context, c client.Client, ibu *ibuv1.ImageBased
 		return fmt.Errorf("failed to update IBU status: %w", err)
 	}
 
+	metrics.RecordIBUStatus(ibu)
+	pendingIBUFailures.flush()
 	return nil
 }
 
@@ -675,17 +766,35 @@ func SetIPConfigStatusInProgress(ipc *ipcv1.IPConfig, msg string) {
 		ipc.Generation)
 }
 
+// recordIPCFailure counts the failure of the stage once the status is written, unless the stage has already failed
+// with the same reason
+func recordIPCFailure(ipc *ipcv1.IPConfig, stage ipcv1.IPConfigStage, reason ConditionReason) {
+	condition := meta.FindStatusCondition(ipc.Status.Conditions, string(GetIPCompletedConditionType(stage)))
+	if condition != nil && condition.Status == metav1.ConditionFalse && condition.Reason == string(reason) {
+		return
+	}
+	pendingIPCFailures.add(fmt.Sprintf("%s/%s", stage, reason), func() {
+		metrics.RecordIPCFailure(stage, string(reason))
+	})
+}
+
 // SetIPConfigStatusFailed updates the IP Config status to failed with message
 func SetIPConfigStatusFailed(ipc *ipcv1.IPConfig, msg string) {
+	SetIPConfigStatusFailedWithReason(ipc, ConditionReasons.Failed, msg)
+}
+
+// SetIPConfigStatusFailedWithReason updates the IP Config status to failed with reason and message
+func SetIPConfigStatusFailedWithReason(ipc *ipcv1.IPConfig, reason ConditionReason, msg string) {
+	recordIPCFailure(ipc, ipcv1.IPStages.Config, reason)
 	SetStatusCondition(&ipc.Status.Conditions,
 		GetIPCompletedConditionType(ipcv1.IPStages.Config),
-		ConditionReasons.Failed,
+		reason,
 		metav1.ConditionFalse,
 		msg,
 		ipc.Generation)
 	SetStatusCondition(&ipc.Status.Conditions,
 		GetIPInProgressConditionType(ipcv1.IPStages.Config),
-		ConditionReasons.Failed,
+		reason,
 		metav1.ConditionFalse,
 		msg,
 		ipc.Generation)
@@ -719,6 +828,7 @@ func SetIPRollbackStatusInProgress(ipc *ipcv1.IPConfig, msg string) {
 
 // SetIPRollbackStatusFailed updates the IP Rollback status to failed with message
 func SetIPRollbackStatusFailed(ipc *ipcv1.IPConfig, msg string) {
+	recordIPCFailure(ipc, ipcv1.IPStages.Rollback, ConditionReasons.Failed)
 	SetStatusCondition(&ipc.Status.Conditions,
 		GetIPCompletedConditionType(ipcv1.IPStages.Rollback),
 		ConditionReasons.Failed,
@@ -775,6 +885,8 @@ func UpdateIPCStatus(ctx context.Context, c client.Client, ipc *ipcv1.IPConfig)
 		return fmt.Errorf("failed to update IPConfig status: %w", err)
 	}
 
+	metrics.RecordIPCStatus(ipc)
+	pendingIPCFailures.flush()
 	return nil
 }
 
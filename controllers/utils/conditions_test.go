@@ -0,0 +1,95 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	ibuv1 "github.com/openshift-kni/lifecycle-agent/api/imagebasedupgrade/v1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// ibuFailuresCount returns the value of the IBU failures counter for the stage and reason
+func ibuFailuresCount(t *testing.T, stage, reason string) float64 {
+	families, err := ctrlmetrics.Registry.Gather()
+	assert.NoError(t, err)
+	for _, family := range families {
+		if family.GetName() != "lca_ibu_failures_total" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			labels := map[string]string{}
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			if labels["stage"] == stage && labels["reason"] == reason {
+				return metric.GetCounter().GetValue()
+			}
+		}
+	}
+	return 0
+}
+
+// newFailingStatusClient returns a client holding the IBU, whose status updates fail while failUpdate is set
+func newFailingStatusClient(failUpdate *bool) client.Client {
+	testscheme.AddKnownTypes(ibuv1.GroupVersion, &ibuv1.ImageBasedUpgrade{})
+	ibu := &ibuv1.ImageBasedUpgrade{ObjectMeta: metav1.ObjectMeta{Name: IBUName}}
+	return fake.NewClientBuilder().WithScheme(testscheme).WithObjects(ibu).WithStatusSubresource(ibu).
+		WithInterceptorFuncs(interceptor.Funcs{
+			SubResourceUpdate: func(ctx context.Context, c client.Client, subResourceName string, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+				if *failUpdate {
+					return errors.New("connection refused")
+				}
+				return c.SubResource(subResourceName).Update(ctx, obj, opts...) //nolint:wrapcheck
+			},
+		}).Build()
+}
+
+func TestSetPrepStatusFailedCountsTransitionsOnly(t *testing.T) {
+	failUpdate := false
+	c := newFailingStatusClient(&failUpdate)
+	ibu := &ibuv1.ImageBasedUpgrade{}
+	assert.NoError(t, c.Get(context.Background(), client.ObjectKey{Name: IBUName}, ibu))
+	SetPrepStatusInProgress(ibu, InProgress)
+	before := ibuFailuresCount(t, "Prep", "Failed")
+
+	// The failure is set again on every reconcile until the stage changes, but must only be counted once
+	SetPrepStatusFailed(ibu, "precache failed")
+	assert.NoError(t, UpdateIBUStatus(context.Background(), c, ibu))
+	SetPrepStatusFailed(ibu, "precache failed")
+	assert.NoError(t, UpdateIBUStatus(context.Background(), c, ibu))
+	assert.Equal(t, before+1, ibuFailuresCount(t, "Prep", "Failed"))
+
+	// A different reason is a new failure
+	timedOutBefore := ibuFailuresCount(t, "Prep", "TimedOut")
+	SetPrepStatusFailedWithReason(ibu, ConditionReasons.TimedOut, "timed out")
+	assert.NoError(t, UpdateIBUStatus(context.Background(), c, ibu))
+	assert.Equal(t, timedOutBefore+1, ibuFailuresCount(t, "Prep", "TimedOut"))
+	assert.Equal(t, before+1, ibuFailuresCount(t, "Prep", "Failed"))
+}
+
+func TestSetRollbackStatusFailedCountsWrittenFailuresOnly(t *testing.T) {
+	failUpdate := true
+	c := newFailingStatusClient(&failUpdate)
+	before := ibuFailuresCount(t, "Rollback", "Failed")
+
+	// The status update fails, so the failure is not reported yet
+	ibu := &ibuv1.ImageBasedUpgrade{}
+	assert.NoError(t, c.Get(context.Background(), client.ObjectKey{Name: IBUName}, ibu))
+	SetRollbackStatusFailed(ibu, "rollback failed")
+	assert.Error(t, UpdateIBUStatus(context.Background(), c, ibu))
+	assert.Equal(t, before, ibuFailuresCount(t, "Rollback", "Failed"))
+
+	// The retry starts again from the status in the cluster and fails the same way, which is counted once
+	failUpdate = false
+	ibu = &ibuv1.ImageBasedUpgrade{}
+	assert.NoError(t, c.Get(context.Background(), client.ObjectKey{Name: IBUName}, ibu))
+	SetRollbackStatusFailed(ibu, "rollback failed")
+	assert.NoError(t, UpdateIBUStatus(context.Background(), c, ibu))
+	assert.Equal(t, before+1, ibuFailuresCount(t, "Rollback", "Failed"))
+}
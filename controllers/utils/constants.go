@@ -12,9 +12,15 @@ const (
 	// IBUName defines the valid name of the CR for the controller to reconcile
 	IBUName     string = "upgrade"
 	IBUFilePath string = common.LCAConfigDir + "/ibu.json"
+	// QuiesceRecordFilePath holds the original replicas of the workloads scaled down by spec.quiesce
+	QuiesceRecordFilePath string = common.LCAConfigDir + "/quiesce.json"
+	// InventoryFilePath holds the inventory of the cluster taken before the pivot
+	InventoryFilePath string = IBUWorkspacePath + "/inventory.json"
 
 	ManualCleanupAnnotation                                    string = "lca.openshift.io/manual-cleanup-done"
 	TriggerReconcileAnnotation                                 string = "lca.openshift.io/trigger-reconcile"
+	PreflightAnnotation                                        string = "lca.openshift.io/preflight"
+	AutoRollbackStartedAnnotation                              string = "lca.openshift.io/auto-rollback-started"
 	RecertImageAnnotation                                      string = "lca.openshift.io/recert-image"
 	RecertPullSecretAnnotation                                 string = "lca.openshift.io/recert-pull-secret" //nolint:gosec // annotation key, not credentials
 	RecertCachedImageAnnotation                                string = "lca.openshift.io/recert-image-cached"
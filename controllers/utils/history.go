@@ -7,6 +7,7 @@ import (
 	"github.com/go-logr/logr"
 	ibuv1 "github.com/openshift-kni/lifecycle-agent/api/imagebasedupgrade/v1"
 	ipcv1 "github.com/openshift-kni/lifecycle-agent/api/ipconfig/v1"
+	"github.com/openshift-kni/lifecycle-agent/internal/metrics"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -55,6 +56,7 @@ func StopStageHistory(client client.Client, log logr.Logger, ibu *ibuv1.ImageBas
 				}
 			}
 			h.CompletionTime = getMetav1Now()
+			metrics.ObserveIBUStageDuration(h.Stage, h.StartTime, h.CompletionTime)
 			ibu.Status.History = curHistory
 			updateStatus(client, log, ibu)
 		}
@@ -96,6 +98,7 @@ func StopPhase(client client.Client, log logr.Logger, ibu *ibuv1.ImageBasedUpgra
 			for _, p := range h.Phases {
 				if p.Phase == phase && !p.StartTime.IsZero() && p.CompletionTime.IsZero() {
 					p.CompletionTime = getMetav1Now()
+					metrics.ObserveIBUPhaseDuration(h.Stage, p.Phase, p.StartTime, p.CompletionTime)
 					updateStatus(client, log, ibu)
 				}
 			}
@@ -103,6 +106,32 @@ func StopPhase(client client.Client, log logr.Logger, ibu *ibuv1.ImageBasedUpgra
 	}
 }
 
+// GetStageStartTime returns the start time of the current stage, or nil if it is not started or already completed
+func GetStageStartTime(ibu *ibuv1.ImageBasedUpgrade) *metav1.Time {
+	for _, h := range ibu.Status.History {
+		if h.Stage == ibu.Spec.Stage && !h.StartTime.IsZero() && h.CompletionTime.IsZero() {
+			return &h.StartTime
+		}
+	}
+	return nil
+}
+
+// GetPhaseStartTime returns the start time of a phase of the current stage, or nil if it is not started or already
+// completed
+func GetPhaseStartTime(ibu *ibuv1.ImageBasedUpgrade, phase string) *metav1.Time {
+	for _, h := range ibu.Status.History {
+		if h.Stage != ibu.Spec.Stage {
+			continue
+		}
+		for _, p := range h.Phases {
+			if p.Phase == phase && !p.StartTime.IsZero() && p.CompletionTime.IsZero() {
+				return &p.StartTime
+			}
+		}
+	}
+	return nil
+}
+
 // A helper function to return the current time. This also used to override time during tests
 var getMetav1Now = func() metav1.Time {
 	return metav1.Time{Time: time.Now()}
@@ -111,7 +140,9 @@ var getMetav1Now = func() metav1.Time {
 func updateStatus(client client.Client, log logr.Logger, ibu *ibuv1.ImageBasedUpgrade) {
 	if err := client.Status().Update(context.Background(), ibu); err != nil {
 		log.Error(err, "failed to update status with history info")
+		return
 	}
+	pendingIBUFailures.flush()
 }
 
 // ResetIPHistory resets the IPConfig .status.history by setting the list to empty when stage is Idle
@@ -162,6 +193,7 @@ func StopIPStageHistory(client client.Client, log logr.Logger, ipc *ipcv1.IPConf
 				}
 			}
 			h.CompletionTime = getMetav1Now()
+			metrics.ObserveIPCStageDuration(h.Stage, h.StartTime, h.CompletionTime)
 			ipc.Status.History = curHistory
 			updateIPStatus(client, log, ipc)
 		}
@@ -202,6 +234,7 @@ func StopIPPhase(client client.Client, log logr.Logger, ipc *ipcv1.IPConfig, pha
 			for _, p := range h.Phases {
 				if p.Phase == phase && !p.StartTime.IsZero() && p.CompletionTime.IsZero() {
 					p.CompletionTime = metav1.Time{Time: getMetav1Now().Time}
+					metrics.ObserveIPCPhaseDuration(h.Stage, p.Phase, p.StartTime, p.CompletionTime)
 					updateIPStatus(client, log, ipc)
 				}
 			}
@@ -209,8 +242,37 @@ func StopIPPhase(client client.Client, log logr.Logger, ipc *ipcv1.IPConfig, pha
 	}
 }
 
+// GetIPStageStartTime returns the start time of the current IPConfig stage, or nil if it is not started or already
+// completed
+func GetIPStageStartTime(ipc *ipcv1.IPConfig) *metav1.Time {
+	for _, h := range ipc.Status.History {
+		if h.Stage == ipc.Spec.Stage && !h.StartTime.IsZero() && h.CompletionTime.IsZero() {
+			return &h.StartTime
+		}
+	}
+	return nil
+}
+
+// GetIPPhaseStartTime returns the start time of a phase of the current IPConfig stage, or nil if it is not started or
+// already completed
+func GetIPPhaseStartTime(ipc *ipcv1.IPConfig, phase string) *metav1.Time {
+	for _, h := range ipc.Status.History {
+		if h.Stage != ipc.Spec.Stage {
+			continue
+		}
+		for _, p := range h.Phases {
+			if p.Phase == phase && !p.StartTime.IsZero() && p.CompletionTime.IsZero() {
+				return &p.StartTime
+			}
+		}
+	}
+	return nil
+}
+
 func updateIPStatus(client client.Client, log logr.Logger, ipc *ipcv1.IPConfig) {
 	if err := client.Status().Update(context.Background(), ipc); err != nil {
 		log.Error(err, "failed to update ipconfig status with history info")
+		return
 	}
+	pendingIPCFailures.flush()
 }
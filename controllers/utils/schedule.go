@@ -0,0 +1,70 @@
+package utils
+
+import (
+	"fmt"
+	"time"
+
+	ibuv1 "github.com/openshift-kni/lifecycle-agent/api/imagebasedupgrade/v1"
+	"github.com/robfig/cron"
+	"github.com/samber/lo"
+)
+
+// IsStageScheduled checks if the stage is only allowed to start within a maintenance window
+func IsStageScheduled(ibu *ibuv1.ImageBasedUpgrade, stage ibuv1.ImageBasedUpgradeStage) bool {
+	if ibu.Spec.Schedule == nil {
+		return false
+	}
+	if len(ibu.Spec.Schedule.Stages) == 0 {
+		return stage == ibuv1.Stages.Upgrade
+	}
+	return lo.Contains(ibu.Spec.Schedule.Stages, ibuv1.ScheduledStage(stage))
+}
+
+// GetMaintenanceWindowState determines whether a maintenance window of the schedule is open at the given time.
+// If no window is open, it also returns the time at which the next window opens, or a zero time if there is no
+// upcoming window. A schedule without any window does not restrict the stages.
+func GetMaintenanceWindowState(schedule *ibuv1.Schedule, now time.Time) (open bool, nextOpen time.Time, err error) {
+	if schedule == nil || (len(schedule.Windows) == 0 && len(schedule.RecurringWindows) == 0) {
+		return true, time.Time{}, nil
+	}
+
+	loc := time.UTC
+	if schedule.TimeZone != "" {
+		loc, err = time.LoadLocation(schedule.TimeZone)
+		if err != nil {
+			return false, time.Time{}, fmt.Errorf("invalid schedule time zone %s: %w", schedule.TimeZone, err)
+		}
+	}
+	now = now.In(loc)
+
+	updateNextOpen := func(t time.Time) {
+		if nextOpen.IsZero() || t.Before(nextOpen) {
+			nextOpen = t
+		}
+	}
+
+	for _, window := range schedule.Windows {
+		if !now.Before(window.Start.Time) && now.Before(window.End.Time) {
+			return true, time.Time{}, nil
+		}
+		if window.Start.After(now) {
+			updateNextOpen(window.Start.Time)
+		}
+	}
+
+	for _, window := range schedule.RecurringWindows {
+		cronSchedule, err := cron.ParseStandard(window.Cron)
+		if err != nil {
+			return false, time.Time{}, fmt.Errorf("invalid schedule cron expression %q: %w", window.Cron, err)
+		}
+		// The window is open if it was activated within the last duration
+		if lastOpen := cronSchedule.Next(now.Add(-window.Duration.Duration)); !lastOpen.After(now) {
+			return true, time.Time{}, nil
+		}
+		if next := cronSchedule.Next(now); !next.IsZero() {
+			updateNextOpen(next)
+		}
+	}
+
+	return false, nextOpen, nil
+}
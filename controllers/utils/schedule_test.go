@@ -0,0 +1,180 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	ibuv1 "github.com/openshift-kni/lifecycle-agent/api/imagebasedupgrade/v1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestIsStageScheduled(t *testing.T) {
+	tests := []struct {
+		name     string
+		schedule *ibuv1.Schedule
+		stage    ibuv1.ImageBasedUpgradeStage
+		expected bool
+	}{
+		{
+			name:     "no schedule",
+			schedule: nil,
+			stage:    ibuv1.Stages.Upgrade,
+			expected: false,
+		},
+		{
+			name:     "upgrade is gated by default",
+			schedule: &ibuv1.Schedule{},
+			stage:    ibuv1.Stages.Upgrade,
+			expected: true,
+		},
+		{
+			name:     "prep is not gated by default",
+			schedule: &ibuv1.Schedule{},
+			stage:    ibuv1.Stages.Prep,
+			expected: false,
+		},
+		{
+			name:     "prep is gated when listed",
+			schedule: &ibuv1.Schedule{Stages: []ibuv1.ScheduledStage{"Prep"}},
+			stage:    ibuv1.Stages.Prep,
+			expected: true,
+		},
+		{
+			name:     "upgrade is not gated when not listed",
+			schedule: &ibuv1.Schedule{Stages: []ibuv1.ScheduledStage{"Prep"}},
+			stage:    ibuv1.Stages.Upgrade,
+			expected: false,
+		},
+		{
+			name:     "rollback is never gated",
+			schedule: &ibuv1.Schedule{},
+			stage:    ibuv1.Stages.Rollback,
+			expected: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ibu := &ibuv1.ImageBasedUpgrade{Spec: ibuv1.ImageBasedUpgradeSpec{Schedule: tt.schedule}}
+			assert.Equal(t, tt.expected, IsStageScheduled(ibu, tt.stage))
+		})
+	}
+}
+
+func TestGetMaintenanceWindowState(t *testing.T) {
+	// Saturday
+	now := time.Date(2024, time.June, 1, 3, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name             string
+		schedule         *ibuv1.Schedule
+		expectedOpen     bool
+		expectedNextOpen time.Time
+		expectErr        bool
+	}{
+		{
+			name:         "no windows",
+			schedule:     &ibuv1.Schedule{},
+			expectedOpen: true,
+		},
+		{
+			name: "inside explicit window",
+			schedule: &ibuv1.Schedule{Windows: []ibuv1.MaintenanceWindow{
+				{Start: metav1.NewTime(now.Add(-time.Hour)), End: metav1.NewTime(now.Add(time.Hour))},
+			}},
+			expectedOpen: true,
+		},
+		{
+			name: "explicit window end is exclusive",
+			schedule: &ibuv1.Schedule{Windows: []ibuv1.MaintenanceWindow{
+				{Start: metav1.NewTime(now.Add(-time.Hour)), End: metav1.NewTime(now)},
+			}},
+			expectedOpen: false,
+		},
+		{
+			name: "earliest upcoming explicit window",
+			schedule: &ibuv1.Schedule{Windows: []ibuv1.MaintenanceWindow{
+				{Start: metav1.NewTime(now.Add(48 * time.Hour)), End: metav1.NewTime(now.Add(50 * time.Hour))},
+				{Start: metav1.NewTime(now.Add(24 * time.Hour)), End: metav1.NewTime(now.Add(26 * time.Hour))},
+				{Start: metav1.NewTime(now.Add(-3 * time.Hour)), End: metav1.NewTime(now.Add(-2 * time.Hour))},
+			}},
+			expectedOpen:     false,
+			expectedNextOpen: now.Add(24 * time.Hour),
+		},
+		{
+			name: "inside recurring window",
+			schedule: &ibuv1.Schedule{RecurringWindows: []ibuv1.RecurringMaintenanceWindow{
+				{Cron: "0 2 * * 6", Duration: metav1.Duration{Duration: 4 * time.Hour}},
+			}},
+			expectedOpen: true,
+		},
+		{
+			name: "after recurring window closed",
+			schedule: &ibuv1.Schedule{RecurringWindows: []ibuv1.RecurringMaintenanceWindow{
+				{Cron: "0 1 * * 6", Duration: metav1.Duration{Duration: time.Hour}},
+			}},
+			expectedOpen:     false,
+			expectedNextOpen: time.Date(2024, time.June, 8, 1, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "recurring window in time zone",
+			schedule: &ibuv1.Schedule{
+				TimeZone: "America/New_York",
+				RecurringWindows: []ibuv1.RecurringMaintenanceWindow{
+					{Cron: "0 2 * * 6", Duration: metav1.Duration{Duration: 4 * time.Hour}},
+				},
+			},
+			expectedOpen:     false,
+			expectedNextOpen: time.Date(2024, time.June, 1, 6, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "earliest of explicit and recurring windows",
+			schedule: &ibuv1.Schedule{
+				Windows: []ibuv1.MaintenanceWindow{
+					{Start: metav1.NewTime(now.Add(time.Hour)), End: metav1.NewTime(now.Add(2 * time.Hour))},
+				},
+				RecurringWindows: []ibuv1.RecurringMaintenanceWindow{
+					{Cron: "0 22 * * *", Duration: metav1.Duration{Duration: time.Hour}},
+				},
+			},
+			expectedOpen:     false,
+			expectedNextOpen: now.Add(time.Hour),
+		},
+		{
+			name: "no upcoming window",
+			schedule: &ibuv1.Schedule{Windows: []ibuv1.MaintenanceWindow{
+				{Start: metav1.NewTime(now.Add(-3 * time.Hour)), End: metav1.NewTime(now.Add(-2 * time.Hour))},
+			}},
+			expectedOpen: false,
+		},
+		{
+			name: "invalid cron expression",
+			schedule: &ibuv1.Schedule{RecurringWindows: []ibuv1.RecurringMaintenanceWindow{
+				{Cron: "every saturday", Duration: metav1.Duration{Duration: time.Hour}},
+			}},
+			expectErr: true,
+		},
+		{
+			name: "invalid time zone",
+			schedule: &ibuv1.Schedule{
+				TimeZone: "Mars/Olympus_Mons",
+				Windows: []ibuv1.MaintenanceWindow{
+					{Start: metav1.NewTime(now.Add(-time.Hour)), End: metav1.NewTime(now.Add(time.Hour))},
+				},
+			},
+			expectErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			open, nextOpen, err := GetMaintenanceWindowState(tt.schedule, now)
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedOpen, open)
+			assert.True(t, tt.expectedNextOpen.Equal(nextOpen), "expected next open %s, got %s", tt.expectedNextOpen, nextOpen)
+		})
+	}
+}
@@ -31,6 +31,7 @@ import (
 	"github.com/openshift-kni/lifecycle-agent/utils"
 
 	ibuv1 "github.com/openshift-kni/lifecycle-agent/api/imagebasedupgrade/v1"
+	"github.com/samber/lo"
 	velerov1 "github.com/vmware-tanzu/velero/pkg/apis/velero/v1"
 	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
@@ -427,8 +428,9 @@ func (h *BRHandler) ExportOadpConfigurationToDir(ctx context.Context, toDir, oad
 	return nil
 }
 
-// CleanupBackups deletes all backups for this cluster from object storage
-func (h *BRHandler) CleanupBackups(ctx context.Context) error {
+// CleanupBackups deletes all backups for this cluster from object storage, except the ones retained by the upgrades.
+// The retained backups with the discarded suffix, created for an upgrade that is rolled back, are deleted as well.
+func (h *BRHandler) CleanupBackups(ctx context.Context, discardedSuffix string) error {
 	// Get the cluster ID
 	clusterID, err := getClusterID(ctx, h.Client)
 	if err != nil {
@@ -448,14 +450,24 @@ func (h *BRHandler) CleanupBackups(ctx context.Context) error {
 		return fmt.Errorf("failed to list Backup: %w", err)
 	}
 
-	if len(backupList.Items) == 0 {
+	// The backups retained by the upgrades are left to their retention policy
+	backups := lo.Filter(backupList.Items, func(backup velerov1.Backup, _ int) bool {
+		suffix, retained := backup.GetLabels()[retainedForLabel]
+		return !retained || suffix == discardedSuffix
+	})
+	if len(backups) == 0 {
 		h.Log.Info("No Backups found in the cluster, skipping")
 		return nil
 	}
 
+	return h.deleteBackups(ctx, clusterID, backups)
+}
+
+// deleteBackups deletes the backups from object storage and waits for their deletion
+func (h *BRHandler) deleteBackups(ctx context.Context, clusterID string, backups []velerov1.Backup) error {
 	// Create deleteBackupRequest CR to delete the backup in the object storage,
 	// and cleanup labels from objects defined in Backup CRs
-	for _, backup := range backupList.Items {
+	for _, backup := range backups {
 		deleteBackupRequest := &velerov1.DeleteBackupRequest{
 			ObjectMeta: metav1.ObjectMeta{
 				Name:      backup.Name,
@@ -478,7 +490,7 @@ func (h *BRHandler) CleanupBackups(ctx context.Context) error {
 		}
 	}
 
-	return h.ensureBackupsDeleted(ctx, backupList.Items)
+	return h.ensureBackupsDeleted(ctx, backups)
 }
 
 // CleanupStaleBackups checks and deletes if there are any stale Backups (with the same name) that
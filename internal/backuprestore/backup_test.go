@@ -29,6 +29,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	ibuv1 "github.com/openshift-kni/lifecycle-agent/api/imagebasedupgrade/v1"
 	"github.com/openshift-kni/lifecycle-agent/internal/common"
+	"github.com/openshift-kni/lifecycle-agent/utils"
 	configv1 "github.com/openshift/api/config/v1"
 	"github.com/stretchr/testify/assert"
 	velerov1 "github.com/vmware-tanzu/velero/pkg/apis/velero/v1"
@@ -663,7 +664,7 @@ func TestExportRestoresToDir(t *testing.T) {
 		Log:    ctrl.Log.WithName("BackupRestore"),
 	}
 
-	err = handler.ExportRestoresToDir(context.Background(), configMaps, toDir)
+	err = handler.ExportRestoresToDir(context.Background(), configMaps, "", toDir)
 	if err != nil {
 		t.Fatalf("ExportRestoresToDir failed: %v", err)
 	}
@@ -692,6 +693,14 @@ func TestExportRestoresToDir(t *testing.T) {
 			t.Errorf("ExportRestoresToDir failed to create file %s: %v", file, err)
 		}
 	}
+
+	// The restores refer to the backups renamed to be retained
+	restore := &velerov1.Restore{}
+	assert.NoError(t, utils.ReadYamlOrJSONFile(expectedFiles[0], restore))
+	backupName := restore.Spec.BackupName
+	assert.NoError(t, handler.ExportRestoresToDir(context.Background(), configMaps, "4.16.2", toDir))
+	assert.NoError(t, utils.ReadYamlOrJSONFile(expectedFiles[0], restore))
+	assert.Equal(t, backupName+"-4.16.2", restore.Spec.BackupName)
 }
 
 func TestExportOadpConfigurationToDir(t *testing.T) {
@@ -840,7 +849,7 @@ func TestCleanupBackups(t *testing.T) {
 		// CleanupBackups will create a DeleteBackupRequest for cluster1 only,
 		// wait for that DeleteBackupRequest to disappear, and verify that
 		// the Backup is deleted for cluster1.
-		err := handler.CleanupBackups(context.Background())
+		err := handler.CleanupBackups(context.Background(), "")
 		errorChan <- err
 	}()
 
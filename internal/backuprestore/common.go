@@ -43,6 +43,7 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/dynamic"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -87,20 +88,27 @@ var (
 
 // BackuperRestorer interface also used for mocks
 type BackuperRestorer interface {
-	CleanupBackups(ctx context.Context) error
+	CleanupBackups(ctx context.Context, discardedSuffix string) error
+	RetainBackups(ctx context.Context, retention *ibuv1.BackupRetention) error
 	CleanupStaleBackups(ctx context.Context, backups []*velerov1.Backup) error
 	CleanupDeleteBackupRequests(ctx context.Context) error
 	CheckOadpOperatorAvailability(ctx context.Context) error
 	PatchPVsReclaimPolicy(ctx context.Context) error
 	RestorePVsReclaimPolicy(ctx context.Context) error
 	EnsureOadpConfiguration(ctx context.Context) error
+	ProbeBackupStorageLocations(ctx context.Context) (bool, error)
+	EstimateBackups(ctx context.Context, content []ibuv1.ConfigMapRef) (*ibuv1.BackupEstimateStatus, error)
 	ExportOadpConfigurationToDir(ctx context.Context, toDir, oadpNamespace string) error
-	ExportRestoresToDir(ctx context.Context, configMaps []ibuv1.ConfigMapRef, toDir string) error
+	ExportRestoresToDir(ctx context.Context, configMaps []ibuv1.ConfigMapRef, backupSuffix, toDir string) error
+	ExportLocalBackupToDir(ctx context.Context, localBackup *ibuv1.LocalBackup, toDir string) error
+	RestoreLocalBackup(ctx context.Context) error
 	GetSortedBackupsFromConfigmap(ctx context.Context, content []ibuv1.ConfigMapRef) ([][]*velerov1.Backup, error)
 	LoadRestoresFromOadpRestorePath() ([][]*velerov1.Restore, error)
 	StartOrTrackBackup(ctx context.Context, backups []*velerov1.Backup) (*BackupTracker, error)
 	StartOrTrackRestore(ctx context.Context, restores []*velerov1.Restore) (*RestoreTracker, error)
-	ValidateOadpConfigmaps(ctx context.Context, content []ibuv1.ConfigMapRef) error
+	ValidateOadpConfigmaps(ctx context.Context, content []ibuv1.ConfigMapRef, cleanupStaleBackups bool) error
+	VerifyBackups(ctx context.Context, backups []*velerov1.Backup) (bool, error)
+	CheckRestoreReadiness(ctx context.Context, restores []*velerov1.Restore) (bool, error)
 	IsOadpInstalled(ctx context.Context) bool
 	GetDataProtectionApplicationList(ctx context.Context) (*unstructured.UnstructuredList, error)
 	CheckOadpMinimumVersion(ctx context.Context) (bool, error)
@@ -110,6 +118,7 @@ type BackuperRestorer interface {
 type BRHandler struct {
 	client.Client
 	DynamicClient dynamic.Interface
+	Discovery     discovery.ServerResourcesInterface
 	Log           logr.Logger
 }
 
@@ -396,7 +405,9 @@ func patchObj(ctx context.Context, client dynamic.Interface, obj *ObjMetadata,
 	return nil
 }
 
-func (h *BRHandler) ValidateOadpConfigmaps(ctx context.Context, content []ibuv1.ConfigMapRef) error {
+// ValidateOadpConfigmaps validates the Backup and Restore CRs of the OADP configmaps. If cleanupStaleBackups is set, the
+// stale Backups with the same names that do not belong to this cluster are also deleted from the object storage.
+func (h *BRHandler) ValidateOadpConfigmaps(ctx context.Context, content []ibuv1.ConfigMapRef, cleanupStaleBackups bool) error {
 	configmaps, err := common.GetConfigMaps(ctx, h.Client, content)
 	if err != nil {
 		if k8serrors.IsNotFound(err) {
@@ -438,6 +449,11 @@ func (h *BRHandler) ValidateOadpConfigmaps(ctx context.Context, content []ibuv1.
 				return NewBRFailedValidationError("OADP", fmt.Sprintf("failed apply backup label to objects included in apply-backup annotation: %s", err.Error()))
 			}
 		}
+
+		// Check the expectations used to verify the backup contents
+		if _, err := getBackupExpectations(backup); err != nil {
+			return NewBRFailedValidationError("OADP", fmt.Sprintf("invalid backup verification annotation in backup %s: %s", backup.GetName(), err.Error()))
+		}
 	}
 
 	restores, err := common.ExtractResourcesFromConfigmaps[*velerov1.Restore](configmaps, common.RestoreGvk)
@@ -472,6 +488,16 @@ func (h *BRHandler) ValidateOadpConfigmaps(ctx context.Context, content []ibuv1.
 			h.Log.Error(nil, errMsg)
 			return NewBRFailedValidationError("OADP", errMsg)
 		}
+
+		// Check the readiness gate waited for once the restore completes
+		if _, err := getReadinessGate(restore); err != nil {
+			return NewBRFailedValidationError("OADP", fmt.Sprintf("invalid readiness annotation in restore %s: %s", restore.GetName(), err.Error()))
+		}
+
+		// Check the error tolerance of the restore if it partially fails
+		if _, err := getRestoreErrorTolerance(restore); err != nil {
+			return NewBRFailedValidationError("OADP", fmt.Sprintf("invalid error tolerance annotation in restore %s: %s", restore.GetName(), err.Error()))
+		}
 	}
 
 	if len(backups) == 0 || len(restores) == 0 || len(backups) != len(restores) {
@@ -481,10 +507,12 @@ func (h *BRHandler) ValidateOadpConfigmaps(ctx context.Context, content []ibuv1.
 	}
 
 	// Check for any stale backup CRs in this cluster
-	if err := h.CleanupStaleBackups(ctx, backups); err != nil {
-		errMsg := fmt.Sprintf("Failed to cleanup stale Backups: %s", err)
-		h.Log.Error(nil, errMsg)
-		return NewBRFailedValidationError("OADP", errMsg)
+	if cleanupStaleBackups {
+		if err := h.CleanupStaleBackups(ctx, backups); err != nil {
+			errMsg := fmt.Sprintf("Failed to cleanup stale Backups: %s", err)
+			h.Log.Error(nil, errMsg)
+			return NewBRFailedValidationError("OADP", errMsg)
+		}
 	}
 
 	h.Log.Info("OADP configMaps are validated", "configMaps", content)
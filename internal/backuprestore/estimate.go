@@ -0,0 +1,306 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backuprestore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	ibuv1 "github.com/openshift-kni/lifecycle-agent/api/imagebasedupgrade/v1"
+	"github.com/samber/lo"
+	velerov1 "github.com/vmware-tanzu/velero/pkg/apis/velero/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/discovery"
+)
+
+// +kubebuilder:rbac:groups="",resources=persistentvolumeclaims,verbs=get;list;watch
+
+// The duration of a backup is estimated from typical Velero throughputs
+const (
+	estimateBackupOverhead      = 10 * time.Second
+	estimateResourceDuration    = 20 * time.Millisecond
+	estimateSnapshotDuration    = 30 * time.Second
+	estimateFsBackupBytesPerSec = 50 * 1024 * 1024
+)
+
+var (
+	pvcGvr = schema.GroupVersionResource{Version: "v1", Resource: "persistentvolumeclaims"}
+
+	// backupVerbs are the verbs a resource type must support to be backed up by Velero
+	backupVerbs = []string{"list", "create", "get", "delete"}
+)
+
+// backupResource is a resource type selected by a backup
+type backupResource struct {
+	gvr        schema.GroupVersionResource
+	namespaced bool
+}
+
+// backupScope is the set of resources selected by a backup
+type backupScope struct {
+	namespaces sets.Set[string]
+	selectors  []labels.Selector
+}
+
+func (s *backupScope) matches(namespace string, objLabels map[string]string) bool {
+	if namespace != "" && !s.namespaces.Has(namespace) {
+		return false
+	}
+	for _, selector := range s.selectors {
+		if selector.Matches(labels.Set(objLabels)) {
+			return true
+		}
+	}
+	return false
+}
+
+// EstimateBackups estimates the number of resources, the size of the volume data and the duration of the backups of
+// the OADP configmaps, by counting the resources of the cluster matched by each backup and summing the capacities of
+// the persistent volume claims whose data is backed up
+func (h *BRHandler) EstimateBackups(ctx context.Context, content []ibuv1.ConfigMapRef) (*ibuv1.BackupEstimateStatus, error) {
+	sortedBackupGroups, err := h.GetSortedBackupsFromConfigmap(ctx, content)
+	if err != nil {
+		return nil, err
+	}
+
+	namespaceList := &corev1.NamespaceList{}
+	if err := h.List(ctx, namespaceList); err != nil {
+		return nil, fmt.Errorf("failed to list namespaces: %w", err)
+	}
+	namespaces := lo.Map(namespaceList.Items, func(ns corev1.Namespace, _ int) string { return ns.Name })
+
+	estimate := &ibuv1.BackupEstimateStatus{}
+	for _, backup := range lo.Flatten(sortedBackupGroups) {
+		backupEstimate, err := h.estimateBackup(ctx, backup, namespaces)
+		if err != nil {
+			return nil, err
+		}
+		estimate.Resources += backupEstimate.Resources
+		estimate.Volumes += backupEstimate.Volumes
+		estimate.VolumeSize.Add(backupEstimate.VolumeSize)
+		estimate.Duration.Duration += backupEstimate.Duration.Duration
+		estimate.Backups = append(estimate.Backups, *backupEstimate)
+	}
+
+	h.Log.Info("Backups estimated", "resources", estimate.Resources, "volumes", estimate.Volumes,
+		"volumeSize", estimate.VolumeSize.String(), "duration", estimate.Duration.Duration)
+	return estimate, nil
+}
+
+func (h *BRHandler) estimateBackup(ctx context.Context, backup *velerov1.Backup, namespaces []string) (*ibuv1.BackupEstimate, error) {
+	scope, err := getBackupScope(backup, namespaces)
+	if err != nil {
+		return nil, err
+	}
+
+	backupEstimate := &ibuv1.BackupEstimate{Name: backup.Name}
+	resources, err := h.getBackupResources(backup)
+	if err != nil {
+		return nil, err
+	}
+	includeClusterResources := includesClusterResources(backup)
+	for _, resource := range resources {
+		if !resource.namespaced && !includeClusterResources {
+			continue
+		}
+
+		objs, err := h.DynamicClient.Resource(resource.gvr).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			// The resources LCA is not allowed to list are not counted, the estimate is best effort
+			h.Log.Info("Skipping resource type in backup estimate", "backup", backup.Name, "resource", resource.gvr.String(), "error", err.Error())
+			continue
+		}
+		for _, obj := range objs.Items {
+			if scope.matches(obj.GetNamespace(), obj.GetLabels()) {
+				backupEstimate.Resources++
+			}
+		}
+	}
+
+	backsUpPvcs := lo.ContainsBy(resources, func(resource backupResource) bool { return resource.gvr == pvcGvr })
+	if backsUpVolumeData(backup) && backsUpPvcs {
+		pvcList := &corev1.PersistentVolumeClaimList{}
+		if err := h.List(ctx, pvcList); err != nil {
+			return nil, fmt.Errorf("failed to list persistentVolumeClaims: %w", err)
+		}
+		for _, pvc := range pvcList.Items {
+			if !scope.matches(pvc.Namespace, pvc.Labels) {
+				continue
+			}
+			capacity, found := pvc.Status.Capacity[corev1.ResourceStorage]
+			if !found {
+				capacity = pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+			}
+			backupEstimate.Volumes++
+			if uploadsVolumeData(backup) {
+				backupEstimate.VolumeSize.Add(capacity)
+			}
+		}
+	}
+
+	backupEstimate.Duration = metav1.Duration{Duration: estimateBackupDuration(backup, backupEstimate)}
+	return backupEstimate, nil
+}
+
+// getBackupScope returns the namespaces and the label selectors of the backup, a backup without label selector
+// matching every resource
+func getBackupScope(backup *velerov1.Backup, namespaces []string) (*backupScope, error) {
+	scope := &backupScope{namespaces: sets.New[string]()}
+	if len(backup.Spec.IncludedNamespaces) == 0 || lo.Contains(backup.Spec.IncludedNamespaces, "*") {
+		scope.namespaces.Insert(namespaces...)
+	} else {
+		scope.namespaces.Insert(backup.Spec.IncludedNamespaces...)
+	}
+	scope.namespaces.Delete(backup.Spec.ExcludedNamespaces...)
+
+	labelSelectors := append([]*metav1.LabelSelector{}, backup.Spec.OrLabelSelectors...)
+	if backup.Spec.LabelSelector != nil {
+		labelSelectors = append(labelSelectors, backup.Spec.LabelSelector)
+	}
+	if len(labelSelectors) == 0 {
+		scope.selectors = []labels.Selector{labels.Everything()}
+	}
+	for _, labelSelector := range labelSelectors {
+		selector, err := metav1.LabelSelectorAsSelector(labelSelector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid label selector in backup %s: %w", backup.Name, err)
+		}
+		scope.selectors = append(scope.selectors, selector)
+	}
+	return scope, nil
+}
+
+// getBackupResources returns the resource types of the backup. As with Velero, a backup that does not list its resource
+// types selects every resource type served by the cluster that can be backed up and restored, with its preferred version.
+func (h *BRHandler) getBackupResources(backup *velerov1.Backup) ([]backupResource, error) {
+	included := lo.Flatten([][]string{backup.Spec.IncludedResources,
+		backup.Spec.IncludedNamespaceScopedResources, backup.Spec.IncludedClusterScopedResources})
+	if len(included) == 0 || lo.Contains(included, "*") {
+		return h.discoverBackupResources(backup)
+	}
+
+	var resources []backupResource
+	for _, resource := range included {
+		gvr, err := h.RESTMapper().ResourceFor(schema.ParseGroupResource(resource).WithVersion(""))
+		if err != nil {
+			h.Log.Info("Skipping unknown resource type in backup estimate", "backup", backup.Name, "resource", resource)
+			continue
+		}
+		gvk, err := h.RESTMapper().KindFor(gvr)
+		if err != nil {
+			h.Log.Info("Skipping unknown resource type in backup estimate", "backup", backup.Name, "resource", resource)
+			continue
+		}
+		mapping, err := h.RESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version)
+		if err != nil {
+			h.Log.Info("Skipping unknown resource type in backup estimate", "backup", backup.Name, "resource", resource)
+			continue
+		}
+		if !isResourceExcluded(backup, mapping.Resource) {
+			resources = append(resources, backupResource{
+				gvr:        mapping.Resource,
+				namespaced: mapping.Scope.Name() == meta.RESTScopeNameNamespace,
+			})
+		}
+	}
+	return resources, nil
+}
+
+// discoverBackupResources returns the resource types served by the cluster that support the backup verbs, except the
+// subresources and the types excluded from the backup
+func (h *BRHandler) discoverBackupResources(backup *velerov1.Backup) ([]backupResource, error) {
+	resourceLists, err := h.Discovery.ServerPreferredResources()
+	if err != nil {
+		var groupDiscoveryErr *discovery.ErrGroupDiscoveryFailed
+		if !errors.As(err, &groupDiscoveryErr) {
+			return nil, fmt.Errorf("failed to discover resource types: %w", err)
+		}
+		// The resource types of the groups that cannot be discovered, e.g. as their API service is unavailable, are
+		// not counted
+		h.Log.Info("Skipping undiscoverable resource types in backup estimate", "error", err.Error())
+	}
+
+	var resources []backupResource
+	for _, resourceList := range discovery.FilteredBy(discovery.SupportsAllVerbs{Verbs: backupVerbs}, resourceLists) {
+		gv, err := schema.ParseGroupVersion(resourceList.GroupVersion)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse group version %s: %w", resourceList.GroupVersion, err)
+		}
+		for _, apiResource := range resourceList.APIResources {
+			gvr := gv.WithResource(apiResource.Name)
+			if strings.Contains(apiResource.Name, "/") || isResourceExcluded(backup, gvr) {
+				continue
+			}
+			resources = append(resources, backupResource{gvr: gvr, namespaced: apiResource.Namespaced})
+		}
+	}
+	return resources, nil
+}
+
+// includesClusterResources checks whether the backup includes the cluster-scoped resources. As with Velero, they are
+// included when listed in the cluster-scoped resource types or when includeClusterResources is true, and when it is
+// unset, only when the backup includes every namespace.
+func includesClusterResources(backup *velerov1.Backup) bool {
+	if len(backup.Spec.IncludedClusterScopedResources) > 0 {
+		return true
+	}
+	if backup.Spec.IncludeClusterResources != nil {
+		return *backup.Spec.IncludeClusterResources
+	}
+	return (len(backup.Spec.IncludedNamespaces) == 0 || lo.Contains(backup.Spec.IncludedNamespaces, "*")) &&
+		len(backup.Spec.ExcludedNamespaces) == 0
+}
+
+// isResourceExcluded checks whether the resource type is excluded from the backup, by its resource name with or
+// without group
+func isResourceExcluded(backup *velerov1.Backup, gvr schema.GroupVersionResource) bool {
+	excluded := lo.Flatten([][]string{backup.Spec.ExcludedResources,
+		backup.Spec.ExcludedNamespaceScopedResources, backup.Spec.ExcludedClusterScopedResources})
+	return lo.Contains(excluded, gvr.Resource) || lo.Contains(excluded, gvr.GroupResource().String())
+}
+
+// backsUpVolumeData checks whether the backup takes volume snapshots or backs up the volume data with file-system
+// backup. Velero snapshots the volumes unless snapshotVolumes is false.
+func backsUpVolumeData(backup *velerov1.Backup) bool {
+	return lo.FromPtrOr(backup.Spec.DefaultVolumesToFsBackup, false) || lo.FromPtrOr(backup.Spec.SnapshotVolumes, true)
+}
+
+// uploadsVolumeData checks whether the volume data is uploaded to the object storage, with file-system backup or when
+// the snapshot data is moved. Otherwise the volume snapshots stay in the cluster storage.
+func uploadsVolumeData(backup *velerov1.Backup) bool {
+	return lo.FromPtrOr(backup.Spec.DefaultVolumesToFsBackup, false) || lo.FromPtrOr(backup.Spec.SnapshotMoveData, false)
+}
+
+// estimateBackupDuration estimates the duration of the backup from its resources and volumes
+func estimateBackupDuration(backup *velerov1.Backup, estimate *ibuv1.BackupEstimate) time.Duration {
+	duration := estimateBackupOverhead + time.Duration(estimate.Resources)*estimateResourceDuration
+	if uploadsVolumeData(backup) {
+		duration += time.Duration(estimate.VolumeSize.Value()/estimateFsBackupBytesPerSec) * time.Second
+	}
+	if !lo.FromPtrOr(backup.Spec.DefaultVolumesToFsBackup, false) {
+		duration += time.Duration(estimate.Volumes) * estimateSnapshotDuration
+	}
+	return duration
+}
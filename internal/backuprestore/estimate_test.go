@@ -0,0 +1,191 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backuprestore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	ibuv1 "github.com/openshift-kni/lifecycle-agent/api/imagebasedupgrade/v1"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	velerov1 "github.com/vmware-tanzu/velero/pkg/apis/velero/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	apiruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/yaml"
+)
+
+func TestEstimateBackups(t *testing.T) {
+	web := map[string]string{"app": "web"}
+
+	appsBackup := fakeBackupCr("apps", "1", "")
+	appsBackup.Spec = velerov1.BackupSpec{
+		IncludedNamespaces:               []string{"my-app"},
+		IncludedNamespaceScopedResources: []string{"deployments", "persistentvolumeclaims"},
+		LabelSelector:                    &metav1.LabelSelector{MatchLabels: web},
+		DefaultVolumesToFsBackup:         lo.ToPtr(true),
+	}
+	allBackup := fakeBackupCr("all", "2", "")
+	allBackup.Spec = velerov1.BackupSpec{
+		IncludedNamespaces: []string{"*"},
+		ExcludedNamespaces: []string{"other"},
+		SnapshotVolumes:    lo.ToPtr(false),
+	}
+	clusterBackup := fakeBackupCr("cluster", "3", "")
+	clusterBackup.Spec = velerov1.BackupSpec{
+		IncludedClusterScopedResources: []string{"clusterroles.rbac.authorization.k8s.io"},
+		LabelSelector:                  &metav1.LabelSelector{MatchLabels: web},
+	}
+	snapshotBackup := fakeBackupCr("snapshot", "4", "")
+	snapshotBackup.Spec = velerov1.BackupSpec{
+		IncludedNamespaces:      []string{"my-app"},
+		IncludeClusterResources: lo.ToPtr(true),
+		LabelSelector:           &metav1.LabelSelector{MatchLabels: web},
+	}
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "oadp-cm", Namespace: oadpNs},
+		Data:       map[string]string{},
+	}
+	for _, backup := range []*velerov1.Backup{appsBackup, allBackup, clusterBackup, snapshotBackup} {
+		data, err := yaml.Marshal(backup)
+		assert.NoError(t, err)
+		cm.Data[backup.Name] = string(data)
+	}
+
+	pvc := &corev1.PersistentVolumeClaim{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "PersistentVolumeClaim"},
+		ObjectMeta: metav1.ObjectMeta{Name: "data", Namespace: "my-app", Labels: web},
+		Status: corev1.PersistentVolumeClaimStatus{
+			Capacity: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("10Gi")},
+		},
+	}
+	pvcObj, err := apiruntime.DefaultUnstructuredConverter.ToUnstructured(pvc)
+	assert.NoError(t, err)
+
+	restMapper := meta.NewDefaultRESTMapper(nil)
+	for _, gvk := range []schema.GroupVersionKind{
+		{Version: "v1", Kind: "ConfigMap"},
+		{Version: "v1", Kind: "PersistentVolumeClaim"},
+		{Group: "apps", Version: "v1", Kind: "Deployment"},
+	} {
+		restMapper.Add(gvk, meta.RESTScopeNamespace)
+	}
+	restMapper.Add(schema.GroupVersionKind{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "ClusterRole"}, meta.RESTScopeRoot)
+
+	backupVerbs := metav1.Verbs{"create", "delete", "get", "list", "patch", "update", "watch"}
+	discoveryClient := &fakeDiscovery{resourceLists: []*metav1.APIResourceList{
+		{GroupVersion: "v1", APIResources: []metav1.APIResource{
+			{Name: "configmaps", Namespaced: true, Kind: "ConfigMap", Verbs: backupVerbs},
+			{Name: "persistentvolumeclaims", Namespaced: true, Kind: "PersistentVolumeClaim", Verbs: backupVerbs},
+			{Name: "persistentvolumeclaims/status", Namespaced: true, Kind: "PersistentVolumeClaim", Verbs: backupVerbs},
+			{Name: "bindings", Namespaced: true, Kind: "Binding", Verbs: metav1.Verbs{"create"}},
+		}},
+		{GroupVersion: "apps/v1", APIResources: []metav1.APIResource{
+			{Name: "deployments", Namespaced: true, Kind: "Deployment", Verbs: backupVerbs},
+		}},
+		{GroupVersion: "rbac.authorization.k8s.io/v1", APIResources: []metav1.APIResource{
+			{Name: "clusterroles", Kind: "ClusterRole", Verbs: backupVerbs},
+		}},
+		{GroupVersion: "example.com/v1", APIResources: []metav1.APIResource{
+			{Name: "widgets", Namespaced: true, Kind: "Widget", Verbs: backupVerbs},
+		}},
+	}}
+	listKinds := map[schema.GroupVersionResource]string{
+		{Version: "v1", Resource: "configmaps"}: "ConfigMapList",
+		pvcGvr:                                  "PersistentVolumeClaimList",
+		{Group: "apps", Version: "v1", Resource: "deployments"}:                       "DeploymentList",
+		{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "clusterroles"}: "ClusterRoleList",
+		{Group: "example.com", Version: "v1", Resource: "widgets"}:                    "WidgetList",
+	}
+
+	dynamicObjs := []apiruntime.Object{
+		newLocalBackupObject("apps/v1", "Deployment", "my-app", "web", web),
+		newLocalBackupObject("apps/v1", "Deployment", "my-app", "db", map[string]string{"app": "db"}),
+		newLocalBackupObject("apps/v1", "Deployment", "other", "web", web),
+		newLocalBackupObject("v1", "ConfigMap", "my-app", "settings", nil),
+		newLocalBackupObject("example.com/v1", "Widget", "my-app", "widget", nil),
+		newLocalBackupObject("rbac.authorization.k8s.io/v1", "ClusterRole", "", "web", web),
+		newLocalBackupObject("rbac.authorization.k8s.io/v1", "ClusterRole", "", "admin", nil),
+		&unstructured.Unstructured{Object: pvcObj},
+	}
+	c := fake.NewClientBuilder().WithScheme(testscheme).WithRESTMapper(restMapper).WithObjects(
+		cm, pvc,
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "my-app"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "other"}},
+	).Build()
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(apiruntime.NewScheme(), listKinds, dynamicObjs...)
+	handler := &BRHandler{Client: c, DynamicClient: dynamicClient, Discovery: discoveryClient, Log: ctrl.Log.WithName("BackupRestore")}
+
+	estimate, err := handler.EstimateBackups(context.Background(), []ibuv1.ConfigMapRef{{Name: cm.Name, Namespace: oadpNs}})
+	assert.NoError(t, err)
+	assert.Equal(t, 11, estimate.Resources)
+	assert.Equal(t, 2, estimate.Volumes)
+	assert.Equal(t, "10Gi", estimate.VolumeSize.String())
+	assert.Equal(t, 274220*time.Millisecond, estimate.Duration.Duration)
+
+	assert.Len(t, estimate.Backups, 4)
+	assert.Equal(t, "apps", estimate.Backups[0].Name)
+	assert.Equal(t, 2, estimate.Backups[0].Resources)
+	assert.Equal(t, 1, estimate.Backups[0].Volumes)
+	assert.Equal(t, "10Gi", estimate.Backups[0].VolumeSize.String())
+	// 10s overhead, 20ms per resource, and 10Gi of file-system backup at 50MiB/s
+	assert.Equal(t, 214040*time.Millisecond, estimate.Backups[0].Duration.Duration)
+	// Every discovered resource type is counted, including the custom resources, but not the cluster-scoped ones
+	// since a namespace is excluded
+	assert.Equal(t, "all", estimate.Backups[1].Name)
+	assert.Equal(t, 5, estimate.Backups[1].Resources)
+	assert.Equal(t, 0, estimate.Backups[1].Volumes)
+	assert.Equal(t, "cluster", estimate.Backups[2].Name)
+	assert.Equal(t, 1, estimate.Backups[2].Resources)
+	// The volume snapshots stay in the cluster storage, so their data is not counted
+	assert.Equal(t, "snapshot", estimate.Backups[3].Name)
+	assert.Equal(t, 3, estimate.Backups[3].Resources)
+	assert.Equal(t, 1, estimate.Backups[3].Volumes)
+	assert.Equal(t, "0", estimate.Backups[3].VolumeSize.String())
+	assert.Equal(t, 40060*time.Millisecond, estimate.Backups[3].Duration.Duration)
+}
+
+// fakeDiscovery serves the preferred resource types
+type fakeDiscovery struct {
+	discovery.ServerResourcesInterface
+	resourceLists []*metav1.APIResourceList
+}
+
+func (f *fakeDiscovery) ServerPreferredResources() ([]*metav1.APIResourceList, error) {
+	return f.resourceLists, nil
+}
+
+func TestEstimateBackupDuration(t *testing.T) {
+	estimate := &ibuv1.BackupEstimate{Resources: 100, Volumes: 2, VolumeSize: resource.MustParse("1Gi")}
+
+	// The volume snapshots stay in the cluster storage
+	assert.Equal(t, 72*time.Second, estimateBackupDuration(&velerov1.Backup{}, estimate))
+
+	// The snapshot data is moved to the object storage
+	backup := &velerov1.Backup{Spec: velerov1.BackupSpec{SnapshotMoveData: lo.ToPtr(true)}}
+	assert.Equal(t, 92*time.Second, estimateBackupDuration(backup, estimate))
+}
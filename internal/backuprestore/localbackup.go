@@ -0,0 +1,345 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backuprestore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	ibuv1 "github.com/openshift-kni/lifecycle-agent/api/imagebasedupgrade/v1"
+	"github.com/openshift-kni/lifecycle-agent/utils"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;create;update;patch
+// +kubebuilder:rbac:groups="",resources=serviceaccounts;services,verbs=get;list;create;update;patch
+// +kubebuilder:rbac:groups=apps,resources=deployments;statefulsets;daemonsets,verbs=get;list;create;update;patch
+// +kubebuilder:rbac:groups=batch,resources=cronjobs,verbs=get;list;create;update;patch
+// +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=roles;rolebindings,verbs=get;list;create;update;patch
+// +kubebuilder:rbac:groups=route.openshift.io,resources=routes;routes/custom-host,verbs=get;list;create;update;patch
+// +kubebuilder:rbac:groups=networking.k8s.io,resources=ingresses,verbs=get;list;create;update;patch
+
+// LocalBackupPath is where the resources selected by spec.localBackup are exported in the new stateroot /var
+const LocalBackupPath = "/opt/local-backup"
+
+// localBackupResource is a namespaced resource backed up by spec.localBackup
+type localBackupResource struct {
+	gvr  schema.GroupVersionResource
+	kind string
+}
+
+var (
+	namespaceGvr = schema.GroupVersionResource{Version: "v1", Resource: "namespaces"}
+
+	// localBackupResources lists the backed up resources in dependency order, each group being applied after the
+	// previous one. The namespaces of the resources are applied before all the groups.
+	localBackupResources = [][]localBackupResource{
+		{
+			{gvr: schema.GroupVersionResource{Version: "v1", Resource: "serviceaccounts"}, kind: "ServiceAccount"},
+			{gvr: schema.GroupVersionResource{Version: "v1", Resource: "secrets"}, kind: "Secret"},
+			{gvr: schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}, kind: "ConfigMap"},
+			{gvr: schema.GroupVersionResource{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "roles"}, kind: "Role"},
+			{gvr: schema.GroupVersionResource{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "rolebindings"}, kind: "RoleBinding"},
+		},
+		{
+			{gvr: schema.GroupVersionResource{Version: "v1", Resource: "services"}, kind: "Service"},
+			{gvr: schema.GroupVersionResource{Group: "networking.k8s.io", Version: "v1", Resource: "networkpolicies"}, kind: "NetworkPolicy"},
+		},
+		{
+			{gvr: schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}, kind: "Deployment"},
+			{gvr: schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "statefulsets"}, kind: "StatefulSet"},
+			{gvr: schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "daemonsets"}, kind: "DaemonSet"},
+			{gvr: schema.GroupVersionResource{Group: "batch", Version: "v1", Resource: "cronjobs"}, kind: "CronJob"},
+		},
+		{
+			{gvr: schema.GroupVersionResource{Group: "route.openshift.io", Version: "v1", Resource: "routes"}, kind: "Route"},
+			{gvr: schema.GroupVersionResource{Group: "networking.k8s.io", Version: "v1", Resource: "ingresses"}, kind: "Ingress"},
+		},
+	}
+
+	// localBackupSkippedConfigMaps are created in every namespace by the platform
+	localBackupSkippedConfigMaps = []string{"kube-root-ca.crt", "openshift-service-ca.crt"}
+)
+
+// isPlatformNamespace checks whether the namespace belongs to the platform, which is provided by the seed image
+func isPlatformNamespace(namespace string) bool {
+	return namespace == "default" || namespace == "openshift" ||
+		strings.HasPrefix(namespace, "openshift-") || strings.HasPrefix(namespace, "kube-")
+}
+
+// ValidateLocalBackup validates spec.localBackup
+func ValidateLocalBackup(localBackup *ibuv1.LocalBackup) error {
+	if localBackup == nil {
+		return nil
+	}
+	if len(localBackup.Namespaces) == 0 && localBackup.LabelSelector == nil {
+		return NewBRFailedValidationError("Backup", "at least one of namespaces or labelSelector must be set")
+	}
+	for _, namespace := range localBackup.Namespaces {
+		if isPlatformNamespace(namespace) {
+			return NewBRFailedValidationError("Backup", fmt.Sprintf("platform namespace %s cannot be backed up", namespace))
+		}
+	}
+	if localBackup.LabelSelector != nil {
+		if _, err := metav1.LabelSelectorAsSelector(localBackup.LabelSelector); err != nil {
+			return NewBRFailedValidationError("Backup", fmt.Sprintf("invalid labelSelector: %s", err.Error()))
+		}
+	}
+	return nil
+}
+
+// ExportLocalBackupToDir exports the resources selected by spec.localBackup to the given location, grouped by
+// dependency order so they can be applied like the extra manifests once the cluster is rebooted to the new stateroot
+func (h *BRHandler) ExportLocalBackupToDir(ctx context.Context, localBackup *ibuv1.LocalBackup, toDir string) error {
+	if localBackup == nil {
+		h.Log.Info("No local backup is requested")
+		return nil
+	}
+	if err := ValidateLocalBackup(localBackup); err != nil {
+		return err
+	}
+
+	selector := labels.Everything()
+	if localBackup.LabelSelector != nil {
+		// Already validated
+		selector, _ = metav1.LabelSelectorAsSelector(localBackup.LabelSelector)
+	}
+	namespaces := localBackup.Namespaces
+	if len(namespaces) == 0 {
+		namespaces = []string{metav1.NamespaceAll}
+	}
+
+	backedUpNamespaces := sets.New[string](localBackup.Namespaces...)
+	var groups [][]*unstructured.Unstructured
+	for _, resources := range localBackupResources {
+		var group []*unstructured.Unstructured
+		for _, resource := range resources {
+			for _, namespace := range namespaces {
+				list, err := h.DynamicClient.Resource(resource.gvr).Namespace(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+				if err != nil {
+					if k8serrors.IsNotFound(err) {
+						h.Log.Info("Resource is not served, skipping", "resource", resource.gvr.String())
+						continue
+					}
+					return fmt.Errorf("failed to list %s: %w", resource.gvr.String(), err)
+				}
+				for i := range list.Items {
+					obj := &list.Items[i]
+					obj.SetAPIVersion(resource.gvr.GroupVersion().String())
+					obj.SetKind(resource.kind)
+					if !shouldBackupLocally(obj) {
+						continue
+					}
+					sanitizeLocalBackupObject(obj)
+					group = append(group, obj)
+					backedUpNamespaces.Insert(obj.GetNamespace())
+				}
+			}
+		}
+		if len(group) > 0 {
+			groups = append(groups, group)
+		}
+	}
+
+	if backedUpNamespaces.Len() == 0 {
+		h.Log.Info("No resources matched the local backup")
+		return nil
+	}
+
+	var namespaceGroup []*unstructured.Unstructured
+	for _, name := range sets.List(backedUpNamespaces) {
+		namespace, err := h.DynamicClient.Resource(namespaceGvr).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			if k8serrors.IsNotFound(err) {
+				return NewBRFailedError("Backup", fmt.Sprintf("namespace %s is not found", name))
+			}
+			return fmt.Errorf("failed to get namespace %s: %w", name, err)
+		}
+		namespace.SetAPIVersion("v1")
+		namespace.SetKind("Namespace")
+		sanitizeLocalBackupObject(namespace)
+		namespaceGroup = append(namespaceGroup, namespace)
+	}
+	groups = append([][]*unstructured.Unstructured{namespaceGroup}, groups...)
+
+	// Remove any resources exported by a previous attempt
+	localBackupDir := filepath.Join(toDir, LocalBackupPath)
+	if err := os.RemoveAll(localBackupDir); err != nil {
+		return fmt.Errorf("failed to remove %s: %w", localBackupDir, err)
+	}
+	for i, objs := range groups {
+		group := filepath.Join(localBackupDir, "group"+strconv.Itoa(i+1))
+		if err := os.MkdirAll(group, 0o700); err != nil {
+			return fmt.Errorf("failed make dir in %s: %w", group, err)
+		}
+
+		for j, obj := range objs {
+			fileName := fmt.Sprintf("%d_%s_%s_%s.yaml", j+1, obj.GetKind(), obj.GetName(), obj.GetNamespace())
+			filePath := filepath.Join(group, fileName)
+			if err := utils.MarshalToYamlFile(obj, filePath); err != nil {
+				return fmt.Errorf("failed to marshal %s %s to yaml: %w", obj.GetKind(), obj.GetName(), err)
+			}
+			h.Log.Info("Exported resource to file", "path", filePath)
+		}
+	}
+
+	return nil
+}
+
+// RestoreLocalBackup applies the resources exported by ExportLocalBackupToDir, one group after the other. Objects that
+// already exist are replaced.
+func (h *BRHandler) RestoreLocalBackup(ctx context.Context) error {
+	localBackupDir := filepath.Join(hostPath, LocalBackupPath)
+	groups, err := utils.LoadGroupedManifestsFromPath(localBackupDir, &h.Log)
+	if err != nil {
+		return fmt.Errorf("failed to read local backup from path: %w", err)
+	}
+	if len(groups) == 0 {
+		h.Log.Info("No local backup to restore", "path", localBackupDir)
+		return nil
+	}
+
+	for _, group := range groups {
+		for _, obj := range group {
+			if err := h.applyLocalBackupObject(ctx, obj); err != nil {
+				errMsg := fmt.Sprintf("failed to restore %s %s in namespace %s: %s", obj.GetKind(), obj.GetName(), obj.GetNamespace(), err.Error())
+				// The object is rejected, e.g. as a role grants permissions LCA does not hold
+				if k8serrors.IsInvalid(err) || k8serrors.IsBadRequest(err) || k8serrors.IsForbidden(err) {
+					return NewBRFailedError("Restore", errMsg)
+				}
+				return errors.New(errMsg)
+			}
+			h.Log.Info("Restored resource", "kind", obj.GetKind(), "name", obj.GetName(), "namespace", obj.GetNamespace())
+		}
+	}
+	return nil
+}
+
+// applyLocalBackupObject creates the object, or replaces it if it exists
+func (h *BRHandler) applyLocalBackupObject(ctx context.Context, obj *unstructured.Unstructured) error {
+	gvr, found := localBackupGvr(obj.GetKind())
+	if !found {
+		return NewBRFailedError("Restore", fmt.Sprintf("unexpected kind %s in local backup", obj.GetKind()))
+	}
+	resource := h.DynamicClient.Resource(gvr).Namespace(obj.GetNamespace())
+
+	_, err := resource.Create(ctx, obj, metav1.CreateOptions{})
+	if err == nil || !k8serrors.IsAlreadyExists(err) {
+		return err
+	}
+	existing, err := resource.Get(ctx, obj.GetName(), metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	obj.SetResourceVersion(existing.GetResourceVersion())
+	_, err = resource.Update(ctx, obj, metav1.UpdateOptions{})
+	return err
+}
+
+// localBackupGvr returns the resource of a kind backed up by spec.localBackup
+func localBackupGvr(kind string) (schema.GroupVersionResource, bool) {
+	if kind == "Namespace" {
+		return namespaceGvr, true
+	}
+	for _, resources := range localBackupResources {
+		for _, resource := range resources {
+			if resource.kind == kind {
+				return resource.gvr, true
+			}
+		}
+	}
+	return schema.GroupVersionResource{}, false
+}
+
+// shouldBackupLocally filters out the platform resources and those generated by controllers, which are recreated
+// once their owner is restored
+func shouldBackupLocally(obj *unstructured.Unstructured) bool {
+	if isPlatformNamespace(obj.GetNamespace()) || len(obj.GetOwnerReferences()) > 0 {
+		return false
+	}
+
+	switch obj.GetKind() {
+	case "ConfigMap":
+		for _, name := range localBackupSkippedConfigMaps {
+			if obj.GetName() == name {
+				return false
+			}
+		}
+	case "Secret":
+		// Service account tokens and image pull secrets are generated for each service account
+		if _, found := obj.GetAnnotations()["kubernetes.io/service-account.name"]; found {
+			return false
+		}
+	case "Role", "RoleBinding":
+		if strings.HasPrefix(obj.GetName(), "system:") {
+			return false
+		}
+	}
+	return true
+}
+
+// sanitizeLocalBackupObject removes the fields set by the cluster, which would prevent the object from being applied
+func sanitizeLocalBackupObject(obj *unstructured.Unstructured) {
+	for _, field := range []string{"uid", "resourceVersion", "generation", "creationTimestamp", "managedFields", "selfLink"} {
+		unstructured.RemoveNestedField(obj.Object, "metadata", field)
+	}
+	unstructured.RemoveNestedField(obj.Object, "status")
+
+	annotations := obj.GetAnnotations()
+	delete(annotations, "deployment.kubernetes.io/revision")
+	if len(annotations) == 0 {
+		annotations = nil
+	}
+	obj.SetAnnotations(annotations)
+
+	switch obj.GetKind() {
+	case "Service":
+		// The cluster IPs are allocated again, unless the service is headless
+		if clusterIP, _, _ := unstructured.NestedString(obj.Object, "spec", "clusterIP"); clusterIP != "None" {
+			unstructured.RemoveNestedField(obj.Object, "spec", "clusterIP")
+			unstructured.RemoveNestedField(obj.Object, "spec", "clusterIPs")
+		}
+	case "ServiceAccount":
+		// The token and image pull secrets generated for the service account are not backed up
+		unstructured.RemoveNestedField(obj.Object, "secrets")
+		pullSecrets, _, _ := unstructured.NestedSlice(obj.Object, "imagePullSecrets")
+		var kept []interface{}
+		for _, pullSecret := range pullSecrets {
+			if ref, ok := pullSecret.(map[string]interface{}); ok {
+				if name, _ := ref["name"].(string); strings.HasPrefix(name, obj.GetName()+"-dockercfg-") {
+					continue
+				}
+			}
+			kept = append(kept, pullSecret)
+		}
+		if len(kept) > 0 {
+			_ = unstructured.SetNestedSlice(obj.Object, kept, "imagePullSecrets")
+		} else {
+			unstructured.RemoveNestedField(obj.Object, "imagePullSecrets")
+		}
+	}
+}
@@ -0,0 +1,192 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backuprestore
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	ibuv1 "github.com/openshift-kni/lifecycle-agent/api/imagebasedupgrade/v1"
+	"github.com/openshift-kni/lifecycle-agent/utils"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	apiruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func newLocalBackupObject(apiVersion, kind, namespace, name string, labels map[string]string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion(apiVersion)
+	obj.SetKind(kind)
+	obj.SetNamespace(namespace)
+	obj.SetName(name)
+	obj.SetLabels(labels)
+	obj.SetUID("uid")
+	obj.SetResourceVersion("1")
+	return obj
+}
+
+func TestValidateLocalBackup(t *testing.T) {
+	assert.NoError(t, ValidateLocalBackup(nil))
+	assert.NoError(t, ValidateLocalBackup(&ibuv1.LocalBackup{Namespaces: []string{"my-app"}}))
+	assert.NoError(t, ValidateLocalBackup(&ibuv1.LocalBackup{
+		LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+	}))
+
+	for _, localBackup := range []*ibuv1.LocalBackup{
+		{},
+		{Namespaces: []string{"my-app", "openshift-adp"}},
+		{LabelSelector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "app", Operator: "Bad"}}}},
+	} {
+		err := ValidateLocalBackup(localBackup)
+		assert.Error(t, err)
+		assert.True(t, IsBRFailedValidationError(err))
+	}
+}
+
+func TestExportLocalBackupToDir(t *testing.T) {
+	listKinds := map[schema.GroupVersionResource]string{namespaceGvr: "NamespaceList"}
+	for _, resources := range localBackupResources {
+		for _, resource := range resources {
+			listKinds[resource.gvr] = resource.kind + "List"
+		}
+	}
+
+	web := map[string]string{"app": "web"}
+	service := newLocalBackupObject("v1", "Service", "my-app", "web", web)
+	assert.NoError(t, unstructured.SetNestedField(service.Object, "172.30.0.10", "spec", "clusterIP"))
+	serviceAccount := newLocalBackupObject("v1", "ServiceAccount", "my-app", "web", web)
+	assert.NoError(t, unstructured.SetNestedSlice(serviceAccount.Object, []interface{}{
+		map[string]interface{}{"name": "web-dockercfg-abcde"}, map[string]interface{}{"name": "registry"},
+	}, "imagePullSecrets"))
+	tokenSecret := newLocalBackupObject("v1", "Secret", "my-app", "web-token", web)
+	tokenSecret.SetAnnotations(map[string]string{"kubernetes.io/service-account.name": "web"})
+	replicaSet := newLocalBackupObject("apps/v1", "Deployment", "my-app", "owned", web)
+	replicaSet.SetOwnerReferences([]metav1.OwnerReference{{Name: "owner"}})
+
+	objs := []apiruntime.Object{
+		newLocalBackupObject("v1", "Namespace", "", "my-app", nil),
+		newLocalBackupObject("v1", "Namespace", "", "other", nil),
+		newLocalBackupObject("apps/v1", "Deployment", "my-app", "web", web),
+		newLocalBackupObject("apps/v1", "Deployment", "other", "web", web),
+		newLocalBackupObject("apps/v1", "Deployment", "other", "db", map[string]string{"app": "db"}),
+		newLocalBackupObject("apps/v1", "Deployment", "openshift-console", "console", web),
+		newLocalBackupObject("v1", "ConfigMap", "my-app", "kube-root-ca.crt", web),
+		newLocalBackupObject("v1", "ConfigMap", "my-app", "settings", web),
+		newLocalBackupObject("route.openshift.io/v1", "Route", "my-app", "web", web),
+		service, serviceAccount, tokenSecret, replicaSet,
+	}
+
+	expectedFiles := []string{
+		"group1/1_Namespace_my-app_.yaml",
+		"group1/2_Namespace_other_.yaml",
+		"group2/1_ServiceAccount_web_my-app.yaml",
+		"group2/2_ConfigMap_settings_my-app.yaml",
+		"group3/1_Service_web_my-app.yaml",
+		"group4/1_Deployment_web_my-app.yaml",
+		"group4/2_Deployment_web_other.yaml",
+		"group5/1_Route_web_my-app.yaml",
+	}
+
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(apiruntime.NewScheme(), listKinds, objs...)
+	handler := &BRHandler{DynamicClient: client, Log: ctrl.Log.WithName("BackupRestore")}
+
+	toDir := t.TempDir()
+	err := handler.ExportLocalBackupToDir(context.Background(), &ibuv1.LocalBackup{
+		LabelSelector: &metav1.LabelSelector{MatchLabels: web},
+	}, toDir)
+	assert.NoError(t, err)
+
+	var files []string
+	localBackupDir := filepath.Join(toDir, LocalBackupPath)
+	err = filepath.WalkDir(localBackupDir, func(path string, d os.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			rel, _ := filepath.Rel(localBackupDir, path)
+			files = append(files, rel)
+		}
+		return err
+	})
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, expectedFiles, files)
+
+	exportedService := &unstructured.Unstructured{}
+	assert.NoError(t, utils.ReadYamlOrJSONFile(filepath.Join(localBackupDir, "group3/1_Service_web_my-app.yaml"), &exportedService.Object))
+	assert.Empty(t, exportedService.GetUID())
+	assert.Empty(t, exportedService.GetResourceVersion())
+	_, found, _ := unstructured.NestedString(exportedService.Object, "spec", "clusterIP")
+	assert.False(t, found)
+
+	exportedServiceAccount := &unstructured.Unstructured{}
+	assert.NoError(t, utils.ReadYamlOrJSONFile(filepath.Join(localBackupDir, "group2/1_ServiceAccount_web_my-app.yaml"), &exportedServiceAccount.Object))
+	pullSecrets, _, _ := unstructured.NestedSlice(exportedServiceAccount.Object, "imagePullSecrets")
+	assert.Equal(t, []interface{}{map[string]interface{}{"name": "registry"}}, pullSecrets)
+
+	// Only the selected namespaces are backed up
+	err = handler.ExportLocalBackupToDir(context.Background(), &ibuv1.LocalBackup{Namespaces: []string{"other"}}, toDir)
+	assert.NoError(t, err)
+	entries, err := os.ReadDir(filepath.Join(localBackupDir, "group2"))
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+	_, err = os.Stat(filepath.Join(localBackupDir, "group3"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestRestoreLocalBackup(t *testing.T) {
+	listKinds := map[schema.GroupVersionResource]string{namespaceGvr: "NamespaceList"}
+	for _, resources := range localBackupResources {
+		for _, resource := range resources {
+			listKinds[resource.gvr] = resource.kind + "List"
+		}
+	}
+
+	source := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(apiruntime.NewScheme(), listKinds,
+		newLocalBackupObject("v1", "Namespace", "", "my-app", nil),
+		newLocalBackupObject("v1", "Secret", "my-app", "credentials", nil),
+		newLocalBackupObject("apps/v1", "Deployment", "my-app", "web", map[string]string{"version": "2"}),
+	)
+	toDir := t.TempDir()
+	err := (&BRHandler{DynamicClient: source, Log: ctrl.Log.WithName("BackupRestore")}).
+		ExportLocalBackupToDir(context.Background(), &ibuv1.LocalBackup{Namespaces: []string{"my-app"}}, toDir)
+	assert.NoError(t, err)
+
+	// The deployment is already deployed on the new stateroot
+	target := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(apiruntime.NewScheme(), listKinds,
+		newLocalBackupObject("apps/v1", "Deployment", "my-app", "web", map[string]string{"version": "1"}),
+	)
+	handler := &BRHandler{DynamicClient: target, Log: ctrl.Log.WithName("BackupRestore")}
+	hostPath = toDir
+	assert.NoError(t, handler.RestoreLocalBackup(context.Background()))
+
+	_, err = target.Resource(namespaceGvr).Get(context.Background(), "my-app", metav1.GetOptions{})
+	assert.NoError(t, err)
+	secretGvr := schema.GroupVersionResource{Version: "v1", Resource: "secrets"}
+	_, err = target.Resource(secretGvr).Namespace("my-app").Get(context.Background(), "credentials", metav1.GetOptions{})
+	assert.NoError(t, err)
+	deploymentGvr := schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+	deployment, err := target.Resource(deploymentGvr).Namespace("my-app").Get(context.Background(), "web", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "2", deployment.GetLabels()["version"])
+
+	// Nothing is restored when there is no local backup
+	hostPath = t.TempDir()
+	assert.NoError(t, handler.RestoreLocalBackup(context.Background()))
+}
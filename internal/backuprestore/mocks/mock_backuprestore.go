@@ -73,18 +73,33 @@ func (mr *MockBackuperRestorerMockRecorder) CheckOadpOperatorAvailability(ctx an
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckOadpOperatorAvailability", reflect.TypeOf((*MockBackuperRestorer)(nil).CheckOadpOperatorAvailability), ctx)
 }
 
+// CheckRestoreReadiness mocks base method.
+func (m *MockBackuperRestorer) CheckRestoreReadiness(ctx context.Context, restores []*v10.Restore) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckRestoreReadiness", ctx, restores)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckRestoreReadiness indicates an expected call of CheckRestoreReadiness.
+func (mr *MockBackuperRestorerMockRecorder) CheckRestoreReadiness(ctx, restores any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckRestoreReadiness", reflect.TypeOf((*MockBackuperRestorer)(nil).CheckRestoreReadiness), ctx, restores)
+}
+
 // CleanupBackups mocks base method.
-func (m *MockBackuperRestorer) CleanupBackups(ctx context.Context) error {
+func (m *MockBackuperRestorer) CleanupBackups(ctx context.Context, discardedSuffix string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CleanupBackups", ctx)
+	ret := m.ctrl.Call(m, "CleanupBackups", ctx, discardedSuffix)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // CleanupBackups indicates an expected call of CleanupBackups.
-func (mr *MockBackuperRestorerMockRecorder) CleanupBackups(ctx any) *gomock.Call {
+func (mr *MockBackuperRestorerMockRecorder) CleanupBackups(ctx, discardedSuffix any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CleanupBackups", reflect.TypeOf((*MockBackuperRestorer)(nil).CleanupBackups), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CleanupBackups", reflect.TypeOf((*MockBackuperRestorer)(nil).CleanupBackups), ctx, discardedSuffix)
 }
 
 // CleanupDeleteBackupRequests mocks base method.
@@ -129,6 +144,35 @@ func (mr *MockBackuperRestorerMockRecorder) EnsureOadpConfiguration(ctx any) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnsureOadpConfiguration", reflect.TypeOf((*MockBackuperRestorer)(nil).EnsureOadpConfiguration), ctx)
 }
 
+// EstimateBackups mocks base method.
+func (m *MockBackuperRestorer) EstimateBackups(ctx context.Context, content []v1.ConfigMapRef) (*v1.BackupEstimateStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EstimateBackups", ctx, content)
+	ret0, _ := ret[0].(*v1.BackupEstimateStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EstimateBackups indicates an expected call of EstimateBackups.
+func (mr *MockBackuperRestorerMockRecorder) EstimateBackups(ctx, content any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EstimateBackups", reflect.TypeOf((*MockBackuperRestorer)(nil).EstimateBackups), ctx, content)
+}
+
+// ExportLocalBackupToDir mocks base method.
+func (m *MockBackuperRestorer) ExportLocalBackupToDir(ctx context.Context, localBackup *v1.LocalBackup, toDir string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportLocalBackupToDir", ctx, localBackup, toDir)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExportLocalBackupToDir indicates an expected call of ExportLocalBackupToDir.
+func (mr *MockBackuperRestorerMockRecorder) ExportLocalBackupToDir(ctx, localBackup, toDir any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportLocalBackupToDir", reflect.TypeOf((*MockBackuperRestorer)(nil).ExportLocalBackupToDir), ctx, localBackup, toDir)
+}
+
 // ExportOadpConfigurationToDir mocks base method.
 func (m *MockBackuperRestorer) ExportOadpConfigurationToDir(ctx context.Context, toDir, oadpNamespace string) error {
 	m.ctrl.T.Helper()
@@ -144,17 +188,17 @@ func (mr *MockBackuperRestorerMockRecorder) ExportOadpConfigurationToDir(ctx, to
 }
 
 // ExportRestoresToDir mocks base method.
-func (m *MockBackuperRestorer) ExportRestoresToDir(ctx context.Context, configMaps []v1.ConfigMapRef, toDir string) error {
+func (m *MockBackuperRestorer) ExportRestoresToDir(ctx context.Context, configMaps []v1.ConfigMapRef, backupSuffix, toDir string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ExportRestoresToDir", ctx, configMaps, toDir)
+	ret := m.ctrl.Call(m, "ExportRestoresToDir", ctx, configMaps, backupSuffix, toDir)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // ExportRestoresToDir indicates an expected call of ExportRestoresToDir.
-func (mr *MockBackuperRestorerMockRecorder) ExportRestoresToDir(ctx, configMaps, toDir any) *gomock.Call {
+func (mr *MockBackuperRestorerMockRecorder) ExportRestoresToDir(ctx, configMaps, backupSuffix, toDir any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportRestoresToDir", reflect.TypeOf((*MockBackuperRestorer)(nil).ExportRestoresToDir), ctx, configMaps, toDir)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportRestoresToDir", reflect.TypeOf((*MockBackuperRestorer)(nil).ExportRestoresToDir), ctx, configMaps, backupSuffix, toDir)
 }
 
 // GetDataProtectionApplicationList mocks base method.
@@ -230,6 +274,35 @@ func (mr *MockBackuperRestorerMockRecorder) PatchPVsReclaimPolicy(ctx any) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchPVsReclaimPolicy", reflect.TypeOf((*MockBackuperRestorer)(nil).PatchPVsReclaimPolicy), ctx)
 }
 
+// ProbeBackupStorageLocations mocks base method.
+func (m *MockBackuperRestorer) ProbeBackupStorageLocations(ctx context.Context) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProbeBackupStorageLocations", ctx)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ProbeBackupStorageLocations indicates an expected call of ProbeBackupStorageLocations.
+func (mr *MockBackuperRestorerMockRecorder) ProbeBackupStorageLocations(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProbeBackupStorageLocations", reflect.TypeOf((*MockBackuperRestorer)(nil).ProbeBackupStorageLocations), ctx)
+}
+
+// RestoreLocalBackup mocks base method.
+func (m *MockBackuperRestorer) RestoreLocalBackup(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreLocalBackup", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreLocalBackup indicates an expected call of RestoreLocalBackup.
+func (mr *MockBackuperRestorerMockRecorder) RestoreLocalBackup(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreLocalBackup", reflect.TypeOf((*MockBackuperRestorer)(nil).RestoreLocalBackup), ctx)
+}
+
 // RestorePVsReclaimPolicy mocks base method.
 func (m *MockBackuperRestorer) RestorePVsReclaimPolicy(ctx context.Context) error {
 	m.ctrl.T.Helper()
@@ -244,6 +317,20 @@ func (mr *MockBackuperRestorerMockRecorder) RestorePVsReclaimPolicy(ctx any) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestorePVsReclaimPolicy", reflect.TypeOf((*MockBackuperRestorer)(nil).RestorePVsReclaimPolicy), ctx)
 }
 
+// RetainBackups mocks base method.
+func (m *MockBackuperRestorer) RetainBackups(ctx context.Context, retention *v1.BackupRetention) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetainBackups", ctx, retention)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RetainBackups indicates an expected call of RetainBackups.
+func (mr *MockBackuperRestorerMockRecorder) RetainBackups(ctx, retention any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetainBackups", reflect.TypeOf((*MockBackuperRestorer)(nil).RetainBackups), ctx, retention)
+}
+
 // StartOrTrackBackup mocks base method.
 func (m *MockBackuperRestorer) StartOrTrackBackup(ctx context.Context, backups []*v10.Backup) (*backuprestore.BackupTracker, error) {
 	m.ctrl.T.Helper()
@@ -275,15 +362,30 @@ func (mr *MockBackuperRestorerMockRecorder) StartOrTrackRestore(ctx, restores an
 }
 
 // ValidateOadpConfigmaps mocks base method.
-func (m *MockBackuperRestorer) ValidateOadpConfigmaps(ctx context.Context, content []v1.ConfigMapRef) error {
+func (m *MockBackuperRestorer) ValidateOadpConfigmaps(ctx context.Context, content []v1.ConfigMapRef, cleanupStaleBackups bool) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ValidateOadpConfigmaps", ctx, content)
+	ret := m.ctrl.Call(m, "ValidateOadpConfigmaps", ctx, content, cleanupStaleBackups)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // ValidateOadpConfigmaps indicates an expected call of ValidateOadpConfigmaps.
-func (mr *MockBackuperRestorerMockRecorder) ValidateOadpConfigmaps(ctx, content any) *gomock.Call {
+func (mr *MockBackuperRestorerMockRecorder) ValidateOadpConfigmaps(ctx, content, cleanupStaleBackups any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateOadpConfigmaps", reflect.TypeOf((*MockBackuperRestorer)(nil).ValidateOadpConfigmaps), ctx, content, cleanupStaleBackups)
+}
+
+// VerifyBackups mocks base method.
+func (m *MockBackuperRestorer) VerifyBackups(ctx context.Context, backups []*v10.Backup) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyBackups", ctx, backups)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyBackups indicates an expected call of VerifyBackups.
+func (mr *MockBackuperRestorerMockRecorder) VerifyBackups(ctx, backups any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateOadpConfigmaps", reflect.TypeOf((*MockBackuperRestorer)(nil).ValidateOadpConfigmaps), ctx, content)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyBackups", reflect.TypeOf((*MockBackuperRestorer)(nil).VerifyBackups), ctx, backups)
 }
@@ -0,0 +1,205 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backuprestore
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/samber/lo"
+	velerov1 "github.com/vmware-tanzu/velero/pkg/apis/velero/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// bslProbeLabel marks the probe backups created to check that the backup storage locations are writable, and the
+	// DeleteBackupRequests of the probe backups
+	bslProbeLabel      = "lca.openshift.io/bsl-probe"
+	bslProbeNamePrefix = "lca-bsl-probe-"
+
+	// bslProbeTTL makes Velero expire a probe backup whose deletion failed
+	bslProbeTTL = time.Hour
+)
+
+var bslProbeTimeout = 2 * time.Minute
+
+// ProbeBackupStorageLocations checks that the backup storage locations are usable, since their Available phase only
+// reflects that Velero can list their content. A probe backup selecting no resources is created in each location,
+// which writes its metadata to the object storage, and is then deleted through a DeleteBackupRequest.
+// The probe does not wait: each call moves it one step forward, and it returns whether the probe is done. The error
+// of a done probe tells whether the storage locations are usable. A probe backup that could not be deleted fails the
+// probe, and its probe resources are deleted so that the next probe starts over.
+func (h *BRHandler) ProbeBackupStorageLocations(ctx context.Context) (bool, error) {
+	clusterID, err := getClusterID(ctx, h.Client)
+	if err != nil {
+		return false, err
+	}
+	probeLabels := []client.ListOption{
+		client.InNamespace(OadpNs), client.MatchingLabels{clusterIDLabel: clusterID}, client.HasLabels{bslProbeLabel},
+	}
+
+	// The probe backups of a previous probe are deleted before probing again
+	dbrList := &velerov1.DeleteBackupRequestList{}
+	if err := h.List(ctx, dbrList, probeLabels...); err != nil {
+		return false, fmt.Errorf("failed to list DeleteBackupRequest: %w", err)
+	}
+	if len(dbrList.Items) > 0 {
+		var failures []string
+		for _, dbr := range dbrList.Items {
+			if dbr.Status.Phase == velerov1.DeleteBackupRequestPhaseProcessed && len(dbr.Status.Errors) != 0 {
+				failures = append(failures,
+					fmt.Sprintf("failed to delete probe backup %s: %s", dbr.Spec.BackupName, strings.Join(dbr.Status.Errors, ", ")))
+			}
+		}
+		if len(failures) == 0 {
+			h.Log.Info("Waiting for the previous probe backups to be deleted", "count", len(dbrList.Items))
+			return false, nil
+		}
+
+		// Velero keeps the failed DeleteBackupRequests for a day, which would fail every later probe
+		if err := h.deleteProbeResources(ctx, dbrList.Items, probeLabels); err != nil {
+			return false, err
+		}
+		return true, NewBRStorageBackendUnavailableError(strings.Join(failures, "; "))
+	}
+
+	backupList := &velerov1.BackupList{}
+	if err := h.List(ctx, backupList, probeLabels...); err != nil {
+		return false, fmt.Errorf("failed to list Backup: %w", err)
+	}
+	if len(backupList.Items) == 0 {
+		return false, h.createProbeBackups(ctx, clusterID)
+	}
+
+	failures, done := h.checkProbeBackups(backupList.Items)
+	if !done {
+		return false, nil
+	}
+
+	for _, probe := range backupList.Items {
+		deleteBackupRequest := &velerov1.DeleteBackupRequest{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      probe.Name,
+				Namespace: probe.Namespace,
+				Labels:    map[string]string{clusterIDLabel: clusterID, bslProbeLabel: probe.Labels[bslProbeLabel]},
+			},
+			Spec: velerov1.DeleteBackupRequestSpec{
+				BackupName: probe.Name,
+			},
+		}
+		if err := h.Create(ctx, deleteBackupRequest); err != nil {
+			return false, fmt.Errorf("could not apply DeleteBackupRequest CR for probe backup %s: %w", probe.Name, err)
+		}
+	}
+
+	if len(failures) > 0 {
+		return true, NewBRStorageBackendUnavailableError(
+			fmt.Sprintf("BackupStorageLocations probe backups failed: %s", strings.Join(failures, "; ")))
+	}
+	h.Log.Info("All BackupStorageLocations are writable")
+	return true, nil
+}
+
+// deleteProbeResources deletes the DeleteBackupRequests of the probe backups and the probe backups left by a failed
+// probe. A probe backup written to the storage location is synced to the cluster again by Velero, and deleted by the
+// next probe.
+func (h *BRHandler) deleteProbeResources(ctx context.Context, dbrs []velerov1.DeleteBackupRequest, probeLabels []client.ListOption) error {
+	for i := range dbrs {
+		if err := h.Delete(ctx, &dbrs[i]); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to delete DeleteBackupRequest %s: %w", dbrs[i].Name, err)
+		}
+	}
+
+	backupList := &velerov1.BackupList{}
+	if err := h.List(ctx, backupList, probeLabels...); err != nil {
+		return fmt.Errorf("failed to list Backup: %w", err)
+	}
+	for i := range backupList.Items {
+		if err := h.Delete(ctx, &backupList.Items[i]); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to delete probe backup %s: %w", backupList.Items[i].Name, err)
+		}
+	}
+	h.Log.Info("Deleted the resources of the failed probe", "deleteBackupRequests", len(dbrs), "backups", len(backupList.Items))
+	return nil
+}
+
+// createProbeBackups creates a probe backup in each backup storage location
+func (h *BRHandler) createProbeBackups(ctx context.Context, clusterID string) error {
+	bslList := &velerov1.BackupStorageLocationList{}
+	if err := h.List(ctx, bslList, client.InNamespace(OadpNs)); err != nil {
+		return fmt.Errorf("failed to list BackupStorageLocations: %w", err)
+	}
+
+	for _, bsl := range bslList.Items {
+		probe := &velerov1.Backup{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      bslProbeNamePrefix + bsl.Name,
+				Namespace: OadpNs,
+				Labels: map[string]string{
+					clusterIDLabel: clusterID,
+					bslProbeLabel:  bsl.Name,
+				},
+			},
+			Spec: velerov1.BackupSpec{
+				StorageLocation:    bsl.Name,
+				IncludedNamespaces: []string{OadpNs},
+				IncludedResources:  []string{"configmaps"},
+				// No resource has this label, so the backup only holds its own metadata
+				LabelSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{bslProbeLabel: bsl.Name},
+				},
+				SnapshotVolumes: new(bool),
+				TTL:             metav1.Duration{Duration: bslProbeTTL},
+			},
+		}
+		if err := h.Create(ctx, probe); err != nil {
+			return fmt.Errorf("failed to create probe backup for BackupStorageLocation %s: %w", bsl.Name, err)
+		}
+		h.Log.Info("Probe backup created", "backupStorageLocation", bsl.Name, "backup", probe.Name)
+	}
+	return nil
+}
+
+// checkProbeBackups returns whether the probe backups are all done, completed or not, and the storage locations whose
+// probe failed or did not complete in time
+func (h *BRHandler) checkProbeBackups(probes []velerov1.Backup) ([]string, bool) {
+	var failures []string
+	done := true
+	for _, probe := range probes {
+		switch probe.Status.Phase {
+		case velerov1.BackupPhaseCompleted:
+		case velerov1.BackupPhaseFailedValidation, velerov1.BackupPhasePartiallyFailed, velerov1.BackupPhaseFailed:
+			failure := fmt.Sprintf("%s: %s", probe.Spec.StorageLocation, probe.Status.Phase)
+			reasons := lo.Compact(append([]string{probe.Status.FailureReason}, probe.Status.ValidationErrors...))
+			if len(reasons) > 0 {
+				failure += " (" + strings.Join(reasons, ", ") + ")"
+			}
+			failures = append(failures, failure)
+		default:
+			if time.Since(probe.CreationTimestamp.Time) > bslProbeTimeout {
+				failures = append(failures, fmt.Sprintf("%s: did not complete in %s", probe.Spec.StorageLocation, bslProbeTimeout))
+				continue
+			}
+			h.Log.Info("Waiting for probe backup to complete", "backup", probe.Name, "phase", probe.Status.Phase)
+			done = false
+		}
+	}
+	return failures, done
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backuprestore
+
+import (
+	"context"
+	"testing"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"github.com/stretchr/testify/assert"
+	velerov1 "github.com/vmware-tanzu/velero/pkg/apis/velero/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// setProbePhase plays the part of Velero, setting the phase of the probe backup
+func setProbePhase(t *testing.T, c client.Client, name string, phase velerov1.BackupPhase, reason string) {
+	backup := &velerov1.Backup{}
+	assert.NoError(t, c.Get(context.Background(), types.NamespacedName{Name: name, Namespace: OadpNs}, backup))
+	backup.Status.Phase = phase
+	backup.Status.FailureReason = reason
+	assert.NoError(t, c.Status().Update(context.Background(), backup))
+}
+
+// deleteProbe plays the part of Velero, deleting the probe backup along with its DeleteBackupRequest
+func deleteProbe(t *testing.T, c client.Client, name string) {
+	objMeta := metav1.ObjectMeta{Name: name, Namespace: OadpNs}
+	assert.NoError(t, c.Delete(context.Background(), &velerov1.Backup{ObjectMeta: objMeta}))
+	assert.NoError(t, c.Delete(context.Background(), &velerov1.DeleteBackupRequest{ObjectMeta: objMeta}))
+}
+
+func TestProbeBackupStorageLocations(t *testing.T) {
+	clusterVersion := &configv1.ClusterVersion{
+		ObjectMeta: metav1.ObjectMeta{Name: "version"},
+		Spec:       configv1.ClusterVersionSpec{ClusterID: configv1.ClusterID(testClusterID)},
+	}
+	bsl := fakeBackupStorageBackendWithStatus("default", velerov1.BackupStorageLocationPhaseAvailable)
+	c := fake.NewClientBuilder().WithScheme(testscheme).WithObjects(clusterVersion, bsl).
+		WithStatusSubresource(&velerov1.Backup{}, &velerov1.DeleteBackupRequest{}).Build()
+	handler := &BRHandler{Client: c, Log: ctrl.Log.WithName("BackupRestore")}
+	probeName := bslProbeNamePrefix + bsl.Name
+	ctx := context.Background()
+
+	// The probe backup is created, and the probe is done once it completes
+	done, err := handler.ProbeBackupStorageLocations(ctx)
+	assert.NoError(t, err)
+	assert.False(t, done)
+	probe := &velerov1.Backup{}
+	assert.NoError(t, c.Get(ctx, types.NamespacedName{Name: probeName, Namespace: OadpNs}, probe))
+	assert.Equal(t, bsl.Name, probe.Spec.StorageLocation)
+
+	setProbePhase(t, c, probeName, velerov1.BackupPhaseCompleted, "")
+	done, err = handler.ProbeBackupStorageLocations(ctx)
+	assert.NoError(t, err)
+	assert.True(t, done)
+	dbr := &velerov1.DeleteBackupRequest{}
+	assert.NoError(t, c.Get(ctx, types.NamespacedName{Name: probeName, Namespace: OadpNs}, dbr))
+	assert.Equal(t, bsl.Name, dbr.Labels[bslProbeLabel])
+
+	// Probing again waits for the previous probe backup to be deleted
+	done, err = handler.ProbeBackupStorageLocations(ctx)
+	assert.NoError(t, err)
+	assert.False(t, done)
+	deleteProbe(t, c, probeName)
+
+	done, err = handler.ProbeBackupStorageLocations(ctx)
+	assert.NoError(t, err)
+	assert.False(t, done)
+	setProbePhase(t, c, probeName, velerov1.BackupPhaseFailed, "access denied")
+	done, err = handler.ProbeBackupStorageLocations(ctx)
+	assert.True(t, done)
+	assert.EqualError(t, err, "BackupStorageLocations probe backups failed: default: Failed (access denied)")
+	assert.True(t, IsBRStorageBackendUnavailableError(err))
+
+	// The deletion of the probe backup failed, the failure is reported once and the probe resources are deleted
+	assert.NoError(t, c.Get(ctx, types.NamespacedName{Name: probeName, Namespace: OadpNs}, dbr))
+	dbr.Status = velerov1.DeleteBackupRequestStatus{Phase: velerov1.DeleteBackupRequestPhaseProcessed, Errors: []string{"access denied"}}
+	assert.NoError(t, c.Status().Update(ctx, dbr))
+	done, err = handler.ProbeBackupStorageLocations(ctx)
+	assert.True(t, done)
+	assert.EqualError(t, err, "failed to delete probe backup lca-bsl-probe-default: access denied")
+	assert.True(t, IsBRStorageBackendUnavailableError(err))
+	assert.True(t, k8serrors.IsNotFound(c.Get(ctx, types.NamespacedName{Name: probeName, Namespace: OadpNs}, &velerov1.DeleteBackupRequest{})))
+	assert.True(t, k8serrors.IsNotFound(c.Get(ctx, types.NamespacedName{Name: probeName, Namespace: OadpNs}, &velerov1.Backup{})))
+
+	// The next probe starts over, and succeeds once the storage location is fixed
+	done, err = handler.ProbeBackupStorageLocations(ctx)
+	assert.NoError(t, err)
+	assert.False(t, done)
+	setProbePhase(t, c, probeName, velerov1.BackupPhaseCompleted, "")
+	done, err = handler.ProbeBackupStorageLocations(ctx)
+	assert.NoError(t, err)
+	assert.True(t, done)
+	deleteProbe(t, c, probeName)
+
+	// The probe backup is still in progress, then does not complete in time
+	done, err = handler.ProbeBackupStorageLocations(ctx)
+	assert.NoError(t, err)
+	assert.False(t, done)
+	assert.NoError(t, c.Get(ctx, types.NamespacedName{Name: probeName, Namespace: OadpNs}, probe))
+	probe.CreationTimestamp = metav1.Now()
+	probe.Status.Phase = velerov1.BackupPhaseInProgress
+	assert.NoError(t, c.Delete(ctx, probe))
+	probe.ResourceVersion = ""
+	assert.NoError(t, c.Create(ctx, probe))
+	done, err = handler.ProbeBackupStorageLocations(ctx)
+	assert.NoError(t, err)
+	assert.False(t, done)
+
+	origTimeout := bslProbeTimeout
+	defer func() {
+		bslProbeTimeout = origTimeout
+	}()
+	bslProbeTimeout = 0
+	done, err = handler.ProbeBackupStorageLocations(ctx)
+	assert.True(t, done)
+	assert.EqualError(t, err, "BackupStorageLocations probe backups failed: default: did not complete in 0s")
+}
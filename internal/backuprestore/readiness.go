@@ -0,0 +1,284 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backuprestore
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/samber/lo"
+	velerov1 "github.com/vmware-tanzu/velero/pkg/apis/velero/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// +kubebuilder:rbac:groups=apps,resources=deployments;statefulsets;daemonsets,verbs=get;list;watch
+
+const (
+	// readinessWorkloadsAnn lists the workloads that must be Available before the next restore wave starts, in
+	// "<kind>/<namespace>/<name>" format with kind one of deployment, statefulset or daemonset, e.g.
+	// "statefulset/my-app/db,deployment/my-app/web"
+	readinessWorkloadsAnn = "lca.openshift.io/readiness-workloads"
+	// readinessSelectorsAnn lists label selectors, separated by semicolons, of the deployments, statefulsets and
+	// daemonsets that must be Available before the next restore wave starts, in "<namespace>:<selector>" format, e.g.
+	// "my-app:app=db,tier=backend;other:app=cache"
+	readinessSelectorsAnn = "lca.openshift.io/readiness-selectors"
+	// readinessTimeoutAnn is the maximum time to wait for the workloads to be Available once the restore completes
+	readinessTimeoutAnn = "lca.openshift.io/readiness-timeout"
+	// readinessPassedAnn records on the Restore CR that its readiness gate passed, so it is not checked again
+	readinessPassedAnn = "lca.openshift.io/readiness-passed"
+
+	defaultReadinessTimeout = 10 * time.Minute
+
+	workloadKindDeployment  = "deployment"
+	workloadKindStatefulSet = "statefulset"
+	workloadKindDaemonSet   = "daemonset"
+)
+
+// workloadRef references a single workload of a readiness gate
+type workloadRef struct {
+	kind      string
+	namespace string
+	name      string
+}
+
+// workloadSelector selects the workloads of a namespace for a readiness gate
+type workloadSelector struct {
+	namespace string
+	selector  labels.Selector
+}
+
+// readinessGate is declared with the readiness annotations of a Restore CR
+type readinessGate struct {
+	workloads []workloadRef
+	selectors []workloadSelector
+	timeout   time.Duration
+}
+
+// getReadinessGate parses the readiness annotations of the restore, and returns nil if there are none
+func getReadinessGate(restore *velerov1.Restore) (*readinessGate, error) {
+	annotations := restore.GetAnnotations()
+	workloads, hasWorkloads := annotations[readinessWorkloadsAnn]
+	selectors, hasSelectors := annotations[readinessSelectorsAnn]
+	if !hasWorkloads && !hasSelectors {
+		return nil, nil
+	}
+
+	gate := &readinessGate{timeout: defaultReadinessTimeout}
+	if timeout, ok := annotations[readinessTimeoutAnn]; ok {
+		duration, err := time.ParseDuration(timeout)
+		if err != nil || duration <= 0 {
+			return nil, fmt.Errorf("invalid %s value %q", readinessTimeoutAnn, timeout)
+		}
+		gate.timeout = duration
+	}
+
+	for _, workload := range strings.Split(workloads, ",") {
+		workload = strings.TrimSpace(workload)
+		if workload == "" {
+			continue
+		}
+		parts := strings.Split(workload, "/")
+		if len(parts) != 3 || parts[1] == "" || parts[2] == "" {
+			return nil, fmt.Errorf("invalid %s entry %q, expected <kind>/<namespace>/<name>", readinessWorkloadsAnn, workload)
+		}
+		kind := strings.ToLower(parts[0])
+		if kind != workloadKindDeployment && kind != workloadKindStatefulSet && kind != workloadKindDaemonSet {
+			return nil, fmt.Errorf("unsupported kind %q in %s entry %q", parts[0], readinessWorkloadsAnn, workload)
+		}
+		gate.workloads = append(gate.workloads, workloadRef{kind: kind, namespace: parts[1], name: parts[2]})
+	}
+
+	for _, entry := range strings.Split(selectors, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		namespace, selectorString, found := strings.Cut(entry, ":")
+		if !found || namespace == "" || selectorString == "" {
+			return nil, fmt.Errorf("invalid %s entry %q, expected <namespace>:<selector>", readinessSelectorsAnn, entry)
+		}
+		selector, err := labels.Parse(selectorString)
+		if err != nil {
+			return nil, fmt.Errorf("invalid label selector in %s entry %q: %w", readinessSelectorsAnn, entry, err)
+		}
+		gate.selectors = append(gate.selectors, workloadSelector{namespace: namespace, selector: selector})
+	}
+
+	return gate, nil
+}
+
+// CheckRestoreReadiness checks the readiness gates declared with the lca.openshift.io/readiness-workloads and
+// lca.openshift.io/readiness-selectors annotations of the completed restores. It returns true once every workload of
+// the gates is Available, and a BRFailedError if some are still not Available when the timeout of their gate, counted
+// from the completion of the restore, expires. A gate that passed is recorded with the
+// lca.openshift.io/readiness-passed annotation of its Restore CR, and is not checked again.
+func (h *BRHandler) CheckRestoreReadiness(ctx context.Context, restores []*velerov1.Restore) (bool, error) {
+	ready := true
+	var failures []string
+	for _, restore := range restores {
+		gate, err := getReadinessGate(restore)
+		if err != nil {
+			return false, NewBRFailedValidationError("Restore", err.Error())
+		}
+		if gate == nil {
+			continue
+		}
+
+		existingRestore := &velerov1.Restore{}
+		if err := h.Get(ctx, types.NamespacedName{Name: restore.Name, Namespace: restore.Namespace}, existingRestore); err != nil {
+			return false, fmt.Errorf("failed to get restore: %w", err)
+		}
+		if _, passed := existingRestore.GetAnnotations()[readinessPassedAnn]; passed {
+			continue
+		}
+
+		notAvailable, err := h.getNotAvailableWorkloads(ctx, gate)
+		if err != nil {
+			return false, err
+		}
+		if len(notAvailable) == 0 {
+			h.Log.Info("Restore readiness gate passed", "name", restore.Name)
+			metav1.SetMetaDataAnnotation(&existingRestore.ObjectMeta, readinessPassedAnn, "true")
+			if err := h.Update(ctx, existingRestore); err != nil {
+				return false, fmt.Errorf("failed to record the readiness of restore %s: %w", restore.Name, err)
+			}
+			continue
+		}
+		ready = false
+
+		completion := existingRestore.Status.CompletionTimestamp
+		if completion != nil && time.Since(completion.Time) >= gate.timeout {
+			failures = append(failures, fmt.Sprintf("%s: not Available after %s: %s", restore.Name, gate.timeout, strings.Join(notAvailable, ", ")))
+			continue
+		}
+		h.Log.Info("Waiting for restore readiness gate", "name", restore.Name, "notAvailable", notAvailable)
+	}
+
+	if len(failures) > 0 {
+		errMsg := fmt.Sprintf("Restore readiness gates timed out: %s", strings.Join(failures, "; "))
+		h.Log.Error(nil, errMsg)
+		return false, NewBRFailedError("Restore", errMsg)
+	}
+	return ready, nil
+}
+
+// getNotAvailableWorkloads returns a description of each workload of the gate that is not Available
+func (h *BRHandler) getNotAvailableWorkloads(ctx context.Context, gate *readinessGate) ([]string, error) {
+	var notAvailable []string
+	for _, ref := range gate.workloads {
+		var obj client.Object
+		switch ref.kind {
+		case workloadKindDeployment:
+			obj = &appsv1.Deployment{}
+		case workloadKindStatefulSet:
+			obj = &appsv1.StatefulSet{}
+		default:
+			obj = &appsv1.DaemonSet{}
+		}
+		if err := h.Get(ctx, types.NamespacedName{Name: ref.name, Namespace: ref.namespace}, obj); err != nil {
+			if k8serrors.IsNotFound(err) {
+				notAvailable = append(notAvailable, fmt.Sprintf("%s/%s/%s not found", ref.kind, ref.namespace, ref.name))
+				continue
+			}
+			return nil, fmt.Errorf("failed to get %s %s/%s: %w", ref.kind, ref.namespace, ref.name, err)
+		}
+		if !isWorkloadAvailable(obj) {
+			notAvailable = append(notAvailable, fmt.Sprintf("%s/%s/%s", ref.kind, ref.namespace, ref.name))
+		}
+	}
+
+	for _, s := range gate.selectors {
+		listOpts := []client.ListOption{client.InNamespace(s.namespace), client.MatchingLabelsSelector{Selector: s.selector}}
+		var workloads []client.Object
+		deployments := &appsv1.DeploymentList{}
+		if err := h.List(ctx, deployments, listOpts...); err != nil {
+			return nil, fmt.Errorf("failed to list deployments: %w", err)
+		}
+		for i := range deployments.Items {
+			workloads = append(workloads, &deployments.Items[i])
+		}
+		statefulSets := &appsv1.StatefulSetList{}
+		if err := h.List(ctx, statefulSets, listOpts...); err != nil {
+			return nil, fmt.Errorf("failed to list statefulsets: %w", err)
+		}
+		for i := range statefulSets.Items {
+			workloads = append(workloads, &statefulSets.Items[i])
+		}
+		daemonSets := &appsv1.DaemonSetList{}
+		if err := h.List(ctx, daemonSets, listOpts...); err != nil {
+			return nil, fmt.Errorf("failed to list daemonsets: %w", err)
+		}
+		for i := range daemonSets.Items {
+			workloads = append(workloads, &daemonSets.Items[i])
+		}
+
+		if len(workloads) == 0 {
+			notAvailable = append(notAvailable, fmt.Sprintf("no workload matches %s in namespace %s", s.selector, s.namespace))
+			continue
+		}
+		for _, workload := range workloads {
+			if !isWorkloadAvailable(workload) {
+				notAvailable = append(notAvailable, fmt.Sprintf("%s/%s/%s", workloadKind(workload), workload.GetNamespace(), workload.GetName()))
+			}
+		}
+	}
+	return notAvailable, nil
+}
+
+// workloadKind returns the kind of a workload, which is not set in the objects returned by the client
+func workloadKind(obj client.Object) string {
+	switch obj.(type) {
+	case *appsv1.Deployment:
+		return workloadKindDeployment
+	case *appsv1.StatefulSet:
+		return workloadKindStatefulSet
+	default:
+		return workloadKindDaemonSet
+	}
+}
+
+// isWorkloadAvailable checks that all the replicas of the workload are updated and available
+func isWorkloadAvailable(obj client.Object) bool {
+	switch w := obj.(type) {
+	case *appsv1.Deployment:
+		replicas := lo.FromPtrOr(w.Spec.Replicas, 1)
+		for _, condition := range w.Status.Conditions {
+			if condition.Type == appsv1.DeploymentAvailable && condition.Status != corev1.ConditionTrue {
+				return false
+			}
+		}
+		return w.Status.ObservedGeneration >= w.Generation &&
+			w.Status.UpdatedReplicas == replicas && w.Status.AvailableReplicas == replicas
+	case *appsv1.StatefulSet:
+		replicas := lo.FromPtrOr(w.Spec.Replicas, 1)
+		return w.Status.ObservedGeneration >= w.Generation &&
+			w.Status.ReadyReplicas == replicas && w.Status.AvailableReplicas == replicas
+	case *appsv1.DaemonSet:
+		return w.Status.ObservedGeneration >= w.Generation &&
+			w.Status.UpdatedNumberScheduled == w.Status.DesiredNumberScheduled &&
+			w.Status.NumberAvailable == w.Status.DesiredNumberScheduled
+	}
+	return false
+}
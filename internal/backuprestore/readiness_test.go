@@ -0,0 +1,147 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backuprestore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	velerov1 "github.com/vmware-tanzu/velero/pkg/apis/velero/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestGetReadinessGate(t *testing.T) {
+	restore := fakeRestoreCr("restore", "1", "backup")
+	gate, err := getReadinessGate(restore)
+	assert.NoError(t, err)
+	assert.Nil(t, gate)
+
+	restore.Annotations[readinessWorkloadsAnn] = "StatefulSet/my-app/db, deployment/my-app/web"
+	restore.Annotations[readinessSelectorsAnn] = "my-app:app=cache,tier=backend"
+	restore.Annotations[readinessTimeoutAnn] = "5m"
+	gate, err = getReadinessGate(restore)
+	assert.NoError(t, err)
+	assert.Equal(t, 5*time.Minute, gate.timeout)
+	assert.Equal(t, []workloadRef{
+		{kind: workloadKindStatefulSet, namespace: "my-app", name: "db"},
+		{kind: workloadKindDeployment, namespace: "my-app", name: "web"},
+	}, gate.workloads)
+	assert.Len(t, gate.selectors, 1)
+	assert.Equal(t, "my-app", gate.selectors[0].namespace)
+	assert.Equal(t, "app=cache,tier=backend", gate.selectors[0].selector.String())
+
+	invalid := map[string]string{
+		readinessWorkloadsAnn: "pod/my-app/web",
+		readinessSelectorsAnn: "app=cache",
+		readinessTimeoutAnn:   "soon",
+	}
+	for ann, value := range invalid {
+		restore = fakeRestoreCr("restore", "1", "backup")
+		restore.Annotations[readinessWorkloadsAnn] = "deployment/my-app/web"
+		restore.Annotations[ann] = value
+		_, err = getReadinessGate(restore)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestCheckRestoreReadiness(t *testing.T) {
+	availableDeployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "my-app", Labels: map[string]string{"app": "web"}},
+		Spec:       appsv1.DeploymentSpec{Replicas: lo.ToPtr(int32(2))},
+		Status:     appsv1.DeploymentStatus{UpdatedReplicas: 2, AvailableReplicas: 2},
+	}
+	unavailableStatefulSet := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "my-app", Labels: map[string]string{"app": "db"}},
+		Spec:       appsv1.StatefulSetSpec{Replicas: lo.ToPtr(int32(3))},
+		Status:     appsv1.StatefulSetStatus{ReadyReplicas: 1, AvailableReplicas: 1},
+	}
+
+	newRestore := func(workloads, selectors string, completedAgo time.Duration) *velerov1.Restore {
+		restore := fakeRestoreCr("restore", "1", "backup")
+		if workloads != "" {
+			restore.Annotations[readinessWorkloadsAnn] = workloads
+		}
+		if selectors != "" {
+			restore.Annotations[readinessSelectorsAnn] = selectors
+		}
+		restore.Annotations[readinessTimeoutAnn] = "10m"
+		restore.Status.Phase = velerov1.RestorePhaseCompleted
+		restore.Status.CompletionTimestamp = &metav1.Time{Time: time.Now().Add(-completedAgo)}
+		return restore
+	}
+
+	tests := []struct {
+		name          string
+		restore       *velerov1.Restore
+		expectedReady bool
+		expectedErr   string
+	}{
+		{
+			name:          "no readiness gate",
+			restore:       newRestore("", "", time.Hour),
+			expectedReady: true,
+		},
+		{
+			name:          "workloads available",
+			restore:       newRestore("deployment/my-app/web", "my-app:app=web", time.Minute),
+			expectedReady: true,
+		},
+		{
+			name:    "waiting for workloads",
+			restore: newRestore("deployment/my-app/web,statefulset/my-app/db", "", time.Minute),
+		},
+		{
+			name:    "waiting for selector to match",
+			restore: newRestore("", "other:app=web", time.Minute),
+		},
+		{
+			name:        "timed out",
+			restore:     newRestore("daemonset/my-app/agent", "my-app:app=db", 11*time.Minute),
+			expectedErr: "Restore readiness gates timed out: restore: not Available after 10m0s: daemonset/my-app/agent not found, statefulset/my-app/db",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			objs := []client.Object{tc.restore, availableDeployment.DeepCopy(), unavailableStatefulSet.DeepCopy()}
+			c := fake.NewClientBuilder().WithScheme(testscheme).WithObjects(objs...).Build()
+			handler := &BRHandler{Client: c, Log: ctrl.Log.WithName("BackupRestore")}
+
+			ready, err := handler.CheckRestoreReadiness(context.Background(), []*velerov1.Restore{tc.restore})
+			assert.Equal(t, tc.expectedReady, ready)
+			if tc.expectedErr == "" {
+				assert.NoError(t, err)
+				if ready {
+					// The gate that passed is not checked again, even if its workloads are no longer Available
+					assert.NoError(t, c.Delete(context.Background(), availableDeployment.DeepCopy()))
+					ready, err = handler.CheckRestoreReadiness(context.Background(), []*velerov1.Restore{tc.restore})
+					assert.NoError(t, err)
+					assert.True(t, ready)
+				}
+				return
+			}
+			assert.EqualError(t, err, tc.expectedErr)
+			assert.True(t, IsBRFailedError(err))
+		})
+	}
+}
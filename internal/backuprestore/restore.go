@@ -39,6 +39,9 @@ type RestoreTracker struct {
 	ProgressingRestores []string
 	SucceededRestores   []string
 	FailedRestores      []string
+	// ToleratedRestores holds the errors of the partially failed restores tolerated by their error tolerance
+	// annotations, which are also counted in SucceededRestores
+	ToleratedRestores []ibuv1.ToleratedRestoreErrors
 }
 
 // StartOrTrackRestore start restore or track restore status
@@ -95,8 +98,22 @@ func (h *BRHandler) StartOrTrackRestore(ctx context.Context, restores []*velerov
 			switch existingRestore.Status.Phase {
 			case velerov1.RestorePhaseCompleted:
 				rt.SucceededRestores = append(rt.SucceededRestores, existingRestore.Name)
+			case velerov1.RestorePhasePartiallyFailed:
+				toleratedErrors, checked, err := h.getToleratedRestoreErrors(ctx, existingRestore)
+				if err != nil {
+					return rt, err
+				}
+				switch {
+				case !checked:
+					// The restore results are being downloaded
+					rt.ProgressingRestores = append(rt.ProgressingRestores, existingRestore.Name)
+				case toleratedErrors != nil:
+					rt.SucceededRestores = append(rt.SucceededRestores, existingRestore.Name)
+					rt.ToleratedRestores = append(rt.ToleratedRestores, *toleratedErrors)
+				default:
+					rt.FailedRestores = append(rt.FailedRestores, existingRestore.Name)
+				}
 			case velerov1.RestorePhaseFailedValidation,
-				velerov1.RestorePhasePartiallyFailed,
 				velerov1.RestorePhaseFailed:
 				rt.FailedRestores = append(rt.FailedRestores, existingRestore.Name)
 			case "":
@@ -114,6 +131,7 @@ func (h *BRHandler) StartOrTrackRestore(ctx context.Context, restores []*velerov
 		"progressing restores", rt.ProgressingRestores,
 		"succeeded restores", rt.SucceededRestores,
 		"failed restores", rt.FailedRestores,
+		"tolerated restores", len(rt.ToleratedRestores),
 	)
 	return rt, nil
 }
@@ -169,9 +187,10 @@ func (h *BRHandler) EnsureOadpConfiguration(ctx context.Context) error {
 	return nil
 }
 
-// ExportRestoresToDir extracts all restore CRs from oadp configmaps and write them to a given location
+// ExportRestoresToDir extracts all restore CRs from oadp configmaps and write them to a given location. The restores
+// refer to the backups renamed with the backup suffix, if any, see SetBackupsRetention.
 // returns: error
-func (h *BRHandler) ExportRestoresToDir(ctx context.Context, configMaps []ibuv1.ConfigMapRef, toDir string) error {
+func (h *BRHandler) ExportRestoresToDir(ctx context.Context, configMaps []ibuv1.ConfigMapRef, backupSuffix, toDir string) error {
 	configmaps, err := common.GetConfigMaps(ctx, h.Client, configMaps)
 	if err != nil {
 		return fmt.Errorf("failed to get configMaps: %w", err)
@@ -196,6 +215,9 @@ func (h *BRHandler) ExportRestoresToDir(ctx context.Context, configMaps []ibuv1.
 		}
 
 		for j, restore := range restoreGroup {
+			if backupSuffix != "" && restore.Spec.BackupName != "" {
+				restore.Spec.BackupName = restore.Spec.BackupName + "-" + backupSuffix
+			}
 			restoreFileName := strconv.Itoa(j+1) + "_" + restore.Name + "_" + restore.Namespace + yamlExt
 			filePath := filepath.Join(group, restoreFileName)
 			if err := utils.MarshalToYamlFile(restore, filePath); err != nil {
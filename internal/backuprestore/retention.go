@@ -0,0 +1,143 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backuprestore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	ibuv1 "github.com/openshift-kni/lifecycle-agent/api/imagebasedupgrade/v1"
+	"github.com/samber/lo"
+	velerov1 "github.com/vmware-tanzu/velero/pkg/apis/velero/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/discovery"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// retainedForLabel marks the backups created to be kept once their upgrade is finalized, with the suffix of their
+	// names. It is set when the backups are created, so Velero stores it in the object storage along with the backups,
+	// and it is kept when the backups are synced to the cluster again after the reboot to the new stateroot.
+	retainedForLabel = "lca.openshift.io/retained-for"
+
+	// keepLastBackupTTL is set on the backups kept by the KeepLast policy without a TTL, since Velero expires every
+	// backup, after 30 days by default
+	keepLastBackupTTL = 10 * 365 * 24 * time.Hour
+
+	// retainedBackupsTimeFormat formats the time the backups of an upgrade are created in their suffix
+	retainedBackupsTimeFormat = "20060102150405"
+)
+
+// invalidSuffixChars are the characters of a version that cannot be used in a backup name or label value
+var invalidSuffixChars = regexp.MustCompile(`[^a-z0-9.-]+`)
+
+// RetainedBackupsSuffix returns the suffix added to the names of the backups of the upgrade to the version, created at
+// the given time, when they are kept once the upgrade is finalized. The creation time tells apart the upgrades to the
+// same version, so the backups of the next upgrades do not reuse them. It is empty when the backups are deleted.
+func RetainedBackupsSuffix(retention *ibuv1.BackupRetention, version string, created time.Time) string {
+	if retention == nil || retention.Policy == ibuv1.BackupRetentionPolicies.Delete {
+		return ""
+	}
+	version = strings.Trim(invalidSuffixChars.ReplaceAllString(strings.ToLower(version), "-"), ".-")
+	return version + "-" + created.UTC().Format(retainedBackupsTimeFormat)
+}
+
+// SetBackupsRetention prepares the backups of the upgrade according to the retention policy. The backups to keep are
+// renamed with the suffix returned by RetainedBackupsSuffix, and their Velero TTL is set. The TTL defined in the OADP
+// configmap is left as is with the Delete policy, for which the suffix is empty.
+func SetBackupsRetention(retention *ibuv1.BackupRetention, suffix string, backups []*velerov1.Backup) {
+	if suffix == "" {
+		return
+	}
+
+	ttl := keepLastBackupTTL
+	if retention.TTL != nil {
+		ttl = retention.TTL.Duration
+	}
+	for _, backup := range backups {
+		backup.Name = backup.Name + "-" + suffix
+		if backup.Labels == nil {
+			backup.Labels = map[string]string{}
+		}
+		backup.Labels[retainedForLabel] = suffix
+		backup.Spec.TTL = metav1.Duration{Duration: ttl}
+	}
+}
+
+// RetainBackups applies the retention policy once the upgrade is finalized. With the KeepLast policy, the backups kept
+// for the upgrades before the last count ones are deleted, the most recent upgrade being the one of the latest backup.
+// The backups are kept by CleanupBackups otherwise.
+func (h *BRHandler) RetainBackups(ctx context.Context, retention *ibuv1.BackupRetention) error {
+	if retention.Policy != ibuv1.BackupRetentionPolicies.KeepLast {
+		return nil
+	}
+
+	clusterID, err := getClusterID(ctx, h.Client)
+	if err != nil {
+		return err
+	}
+
+	backupList := &velerov1.BackupList{}
+	if err := h.List(ctx, backupList, client.MatchingLabels{clusterIDLabel: clusterID}, client.HasLabels{retainedForLabel}); err != nil {
+		var groupDiscoveryErr *discovery.ErrGroupDiscoveryFailed
+		if errors.As(err, &groupDiscoveryErr) || meta.IsNoMatchError(err) {
+			h.Log.Info("Backup CR is not installed, nothing to retain")
+			return nil
+		}
+		return fmt.Errorf("failed to list Backup: %w", err)
+	}
+
+	retainedBackups := lo.GroupBy(backupList.Items, func(backup velerov1.Backup) string {
+		return backup.GetLabels()[retainedForLabel]
+	})
+	latestStart := func(suffix string) time.Time {
+		return lo.MaxBy(lo.Map(retainedBackups[suffix], func(backup velerov1.Backup, _ int) time.Time {
+			return backupStartTime(backup)
+		}), func(a, b time.Time) bool { return a.After(b) })
+	}
+	suffixes := lo.Keys(retainedBackups)
+	sort.Slice(suffixes, func(i, j int) bool {
+		return latestStart(suffixes[i]).After(latestStart(suffixes[j]))
+	})
+
+	// Delete the backups of the oldest upgrades, beyond the count latest ones
+	count := lo.FromPtrOr(retention.Count, 1)
+	if len(suffixes) <= count {
+		return nil
+	}
+	var expiredBackups []velerov1.Backup
+	for _, suffix := range suffixes[count:] {
+		expiredBackups = append(expiredBackups, retainedBackups[suffix]...)
+	}
+	h.Log.Info("Deleting the backups of older upgrades", "count", len(expiredBackups))
+	return h.deleteBackups(ctx, clusterID, expiredBackups)
+}
+
+// backupStartTime returns when the backup started. The creation time of the backups is lost when they are synced from
+// the object storage, unlike their start time.
+func backupStartTime(backup velerov1.Backup) time.Time {
+	if backup.Status.StartTimestamp != nil {
+		return backup.Status.StartTimestamp.Time
+	}
+	return backup.CreationTimestamp.Time
+}
@@ -0,0 +1,152 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backuprestore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	ibuv1 "github.com/openshift-kni/lifecycle-agent/api/imagebasedupgrade/v1"
+	configv1 "github.com/openshift/api/config/v1"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	velerov1 "github.com/vmware-tanzu/velero/pkg/apis/velero/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestRetainedBackupsSuffix(t *testing.T) {
+	created := time.Date(2024, 5, 15, 20, 22, 10, 0, time.FixedZone("CEST", 2*60*60))
+	assert.Empty(t, RetainedBackupsSuffix(nil, "4.16.2", created))
+	assert.Empty(t, RetainedBackupsSuffix(&ibuv1.BackupRetention{Policy: ibuv1.BackupRetentionPolicies.Delete}, "4.16.2", created))
+	keepLast := &ibuv1.BackupRetention{Policy: ibuv1.BackupRetentionPolicies.KeepLast, Count: lo.ToPtr(2)}
+	assert.Equal(t, "4.16.2-20240515182210", RetainedBackupsSuffix(keepLast, "4.16.2", created))
+	assert.Equal(t, "4.17.0-ec.1-build.5-20240515182210", RetainedBackupsSuffix(keepLast, "4.17.0-ec.1+Build.5", created))
+
+	// The upgrades to the same version keep their own backups
+	assert.NotEqual(t, RetainedBackupsSuffix(keepLast, "4.16.2", created),
+		RetainedBackupsSuffix(keepLast, "4.16.2", created.Add(time.Second)))
+}
+
+func TestSetBackupsRetention(t *testing.T) {
+	newBackups := func() []*velerov1.Backup {
+		backup := fakeBackupCr("backup", "1", "deployments")
+		backup.Spec.TTL = metav1.Duration{Duration: time.Hour}
+		return []*velerov1.Backup{backup}
+	}
+
+	backups := newBackups()
+	SetBackupsRetention(nil, "", backups)
+	assert.Equal(t, time.Hour, backups[0].Spec.TTL.Duration)
+	assert.Equal(t, "backup", backups[0].Name)
+
+	SetBackupsRetention(&ibuv1.BackupRetention{Policy: ibuv1.BackupRetentionPolicies.Delete}, "", backups)
+	assert.Equal(t, time.Hour, backups[0].Spec.TTL.Duration)
+	assert.Equal(t, "backup", backups[0].Name)
+	assert.NotContains(t, backups[0].Labels, retainedForLabel)
+
+	SetBackupsRetention(&ibuv1.BackupRetention{
+		Policy: ibuv1.BackupRetentionPolicies.KeepWithTTL,
+		TTL:    &metav1.Duration{Duration: 720 * time.Hour},
+	}, "4.16.2-20240515182210", backups)
+	assert.Equal(t, 720*time.Hour, backups[0].Spec.TTL.Duration)
+	assert.Equal(t, "backup-4.16.2-20240515182210", backups[0].Name)
+	assert.Equal(t, "4.16.2-20240515182210", backups[0].Labels[retainedForLabel])
+
+	backups = newBackups()
+	SetBackupsRetention(&ibuv1.BackupRetention{Policy: ibuv1.BackupRetentionPolicies.KeepLast, Count: lo.ToPtr(2)}, "4.16.2-20240515182210", backups)
+	assert.Equal(t, keepLastBackupTTL, backups[0].Spec.TTL.Duration)
+	assert.Equal(t, "backup-4.16.2-20240515182210", backups[0].Name)
+}
+
+func TestRetainBackups(t *testing.T) {
+	clusterVersion := &configv1.ClusterVersion{
+		ObjectMeta: metav1.ObjectMeta{Name: "version"},
+		Spec:       configv1.ClusterVersionSpec{ClusterID: configv1.ClusterID(testClusterID)},
+	}
+	newBackup := func(name, suffix string, started time.Time) *velerov1.Backup {
+		backup := fakeBackupCr(name, "1", "deployments")
+		if suffix != "" {
+			backup.Name = name + "-" + suffix
+			backup.Labels[retainedForLabel] = suffix
+		}
+		backup.Status.StartTimestamp = &metav1.Time{Time: started}
+		return backup
+	}
+
+	// The backups are synced from the object storage, so their start time orders the upgrades rather than their
+	// version or creation time
+	now := time.Now()
+	oldest := newBackup("app", "4.16.2", now.Add(-48*time.Hour))
+	previous := newBackup("app", "4.15.9", now.Add(-24*time.Hour))
+	current := newBackup("app", "4.17.0", now)
+	notRetained := newBackup("other", "", now)
+	fakeClient, err := getFakeClientFromObjects(clusterVersion, oldest, previous, current, notRetained)
+	assert.NoError(t, err)
+	handler := &BRHandler{Client: fakeClient, Log: ctrl.Log.WithName("BackupRestore")}
+
+	// Nothing is deleted with the KeepWithTTL policy
+	assert.NoError(t, handler.RetainBackups(context.Background(), &ibuv1.BackupRetention{
+		Policy: ibuv1.BackupRetentionPolicies.KeepWithTTL,
+		TTL:    &metav1.Duration{Duration: time.Hour},
+	}))
+
+	errorChan := make(chan error)
+	go func() {
+		errorChan <- handler.RetainBackups(context.Background(), &ibuv1.BackupRetention{
+			Policy: ibuv1.BackupRetentionPolicies.KeepLast,
+			Count:  lo.ToPtr(2),
+		})
+	}()
+
+	// Mock the deletion of the oldest backup once its DeleteBackupRequest is created
+	time.Sleep(2 * time.Second)
+	assert.NoError(t, fakeClient.Delete(context.Background(), oldest))
+	dbr := &velerov1.DeleteBackupRequest{ObjectMeta: metav1.ObjectMeta{Name: oldest.Name, Namespace: oldest.Namespace}}
+	assert.NoError(t, fakeClient.Delete(context.Background(), dbr))
+	assert.NoError(t, <-errorChan)
+
+	backup := &velerov1.Backup{}
+	for _, retained := range []*velerov1.Backup{previous, current, notRetained} {
+		assert.NoError(t, fakeClient.Get(context.Background(), types.NamespacedName{Name: retained.Name, Namespace: retained.Namespace}, backup))
+	}
+
+	// The retained backups are not deleted by the cleanup, unless they were created for the discarded upgrade
+	go func() {
+		errorChan <- handler.CleanupBackups(context.Background(), "4.17.0")
+	}()
+	time.Sleep(2 * time.Second)
+	dbrList := &velerov1.DeleteBackupRequestList{}
+	assert.NoError(t, fakeClient.List(context.Background(), dbrList, client.InNamespace(oadpNs)))
+	assert.ElementsMatch(t, []string{current.Name, notRetained.Name}, lo.Map(dbrList.Items, func(dbr velerov1.DeleteBackupRequest, _ int) string {
+		return dbr.Name
+	}))
+	for _, deleted := range []*velerov1.Backup{current, notRetained} {
+		assert.NoError(t, fakeClient.Delete(context.Background(), deleted))
+		dbr := &velerov1.DeleteBackupRequest{ObjectMeta: metav1.ObjectMeta{Name: deleted.Name, Namespace: deleted.Namespace}}
+		assert.NoError(t, fakeClient.Delete(context.Background(), dbr))
+	}
+	assert.NoError(t, <-errorChan)
+
+	backupList := &velerov1.BackupList{}
+	assert.NoError(t, fakeClient.List(context.Background(), backupList))
+	assert.Len(t, backupList.Items, 1)
+	assert.Equal(t, previous.Name, backupList.Items[0].Name)
+}
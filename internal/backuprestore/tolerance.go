@@ -0,0 +1,227 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backuprestore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	ibuv1 "github.com/openshift-kni/lifecycle-agent/api/imagebasedupgrade/v1"
+	"github.com/samber/lo"
+	velerov1 "github.com/vmware-tanzu/velero/pkg/apis/velero/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// toleratedRestoreErrorsAnn lists regular expressions, one per line, matching the item errors tolerated when the
+	// restore is PartiallyFailed, e.g. "events\.events\.k8s\.io"
+	toleratedRestoreErrorsAnn = "lca.openshift.io/tolerated-restore-errors"
+	// maxToleratedRestoreErrorsAnn is the maximum number of item errors tolerated when the restore is PartiallyFailed
+	maxToleratedRestoreErrorsAnn = "lca.openshift.io/max-tolerated-restore-errors"
+	// restoreErrorsToleratedAnn records the count and the first errors of a partially failed restore once they are
+	// tolerated, so the restore results are not downloaded again
+	restoreErrorsToleratedAnn = "lca.openshift.io/restore-errors-tolerated"
+
+	restoreResultsDownloadRequestSuffix = "-lca-restore-results"
+
+	// maxReportedRestoreErrors and maxRestoreErrorLength bound the tolerated errors reported in the restore annotation
+	// and in the IBU status, as a restore can fail on every item
+	maxReportedRestoreErrors = 10
+	maxRestoreErrorLength    = 256
+)
+
+// restoreErrorTolerance is declared with the error tolerance annotations of a Restore CR. A partially failed restore
+// is tolerated when each of its errors matches one of the patterns, and there are no more errors than the maximum.
+type restoreErrorTolerance struct {
+	patterns  []*regexp.Regexp
+	maxErrors int
+}
+
+// restoreResult is the errors or warnings of a restore, as stored by Velero in the backup storage location
+type restoreResult struct {
+	Velero     []string            `json:"velero,omitempty"`
+	Cluster    []string            `json:"cluster,omitempty"`
+	Namespaces map[string][]string `json:"namespaces,omitempty"`
+}
+
+// getRestoreErrorTolerance parses the error tolerance annotations of the restore, and returns nil if there are none
+func getRestoreErrorTolerance(restore *velerov1.Restore) (*restoreErrorTolerance, error) {
+	annotations := restore.GetAnnotations()
+	patterns, hasPatterns := annotations[toleratedRestoreErrorsAnn]
+	maxErrors, hasMaxErrors := annotations[maxToleratedRestoreErrorsAnn]
+	if !hasPatterns && !hasMaxErrors {
+		return nil, nil
+	}
+
+	tolerance := &restoreErrorTolerance{maxErrors: -1}
+	if hasMaxErrors {
+		value, err := strconv.Atoi(strings.TrimSpace(maxErrors))
+		if err != nil || value < 0 {
+			return nil, fmt.Errorf("invalid %s value %q", maxToleratedRestoreErrorsAnn, maxErrors)
+		}
+		tolerance.maxErrors = value
+	}
+
+	for _, pattern := range strings.Split(patterns, "\n") {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" {
+			continue
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s pattern %q: %w", toleratedRestoreErrorsAnn, pattern, err)
+		}
+		tolerance.patterns = append(tolerance.patterns, re)
+	}
+	return tolerance, nil
+}
+
+// tolerates checks whether the errors of a partially failed restore are tolerated, and returns the reason if not
+func (t *restoreErrorTolerance) tolerates(errs []string) (bool, string) {
+	if t.maxErrors >= 0 && len(errs) > t.maxErrors {
+		return false, fmt.Sprintf("%d errors exceed the maximum of %d tolerated errors", len(errs), t.maxErrors)
+	}
+	if len(t.patterns) == 0 {
+		return true, ""
+	}
+	for _, restoreErr := range errs {
+		matched := false
+		for _, pattern := range t.patterns {
+			if pattern.MatchString(restoreErr) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false, fmt.Sprintf("error %q does not match any tolerated pattern", restoreErr)
+		}
+	}
+	return true, ""
+}
+
+// getRestoreErrors downloads the results of a restore from the backup storage location of its backup, using a
+// Velero DownloadRequest, and returns its item errors. It returns whether the results were downloaded, see
+// downloadFromBackupStorage.
+var getRestoreErrors = func(ctx context.Context, c client.Client, restore *velerov1.Restore) ([]string, bool, error) {
+	backup, err := getBackup(ctx, c, restore.Spec.BackupName, restore.Namespace)
+	if err != nil {
+		return nil, false, err
+	}
+	if backup == nil {
+		return nil, false, fmt.Errorf("backup %s of restore %s not found", restore.Spec.BackupName, restore.Name)
+	}
+
+	target := velerov1.DownloadTarget{Kind: velerov1.DownloadTargetKindRestoreResults, Name: restore.Name}
+	body, downloaded, err := downloadFromBackupStorage(ctx, c, backup, target, restore.Name+restoreResultsDownloadRequestSuffix)
+	if err != nil || !downloaded {
+		return nil, false, err
+	}
+	defer body.Close()
+
+	results := map[string]restoreResult{}
+	if err := decodeGzippedJSON(body, &results); err != nil {
+		return nil, false, fmt.Errorf("failed to decode results of restore %s: %w", restore.Name, err)
+	}
+	return flattenRestoreResult(results["errors"]), true, nil
+}
+
+// flattenRestoreResult returns the messages of the restore result, prefixing those of namespaced items with their
+// namespace
+func flattenRestoreResult(result restoreResult) []string {
+	messages := append(append([]string{}, result.Velero...), result.Cluster...)
+	namespaces := make([]string, 0, len(result.Namespaces))
+	for namespace := range result.Namespaces {
+		namespaces = append(namespaces, namespace)
+	}
+	sort.Strings(namespaces)
+	for _, namespace := range namespaces {
+		for _, message := range result.Namespaces[namespace] {
+			messages = append(messages, namespace+": "+message)
+		}
+	}
+	return messages
+}
+
+// newToleratedRestoreErrors returns the count of the tolerated errors of the restore, with the first errors truncated
+func newToleratedRestoreErrors(restore string, errs []string) ibuv1.ToleratedRestoreErrors {
+	tolerated := ibuv1.ToleratedRestoreErrors{Restore: restore, Count: len(errs), Errors: []string{}}
+	for _, restoreErr := range lo.Slice(errs, 0, maxReportedRestoreErrors) {
+		if len(restoreErr) > maxRestoreErrorLength {
+			restoreErr = restoreErr[:maxRestoreErrorLength-3] + "..."
+		}
+		tolerated.Errors = append(tolerated.Errors, restoreErr)
+	}
+	return tolerated
+}
+
+// getToleratedRestoreErrors checks whether the errors of a partially failed restore are tolerated by its error
+// tolerance annotations. The restore results are downloaded without waiting: it returns whether the errors were
+// checked, along with the tolerated errors, or nil if they are not tolerated. Once tolerated, the errors are recorded
+// in an annotation of the restore.
+func (h *BRHandler) getToleratedRestoreErrors(ctx context.Context, restore *velerov1.Restore) (*ibuv1.ToleratedRestoreErrors, bool, error) {
+	if recorded, found := restore.GetAnnotations()[restoreErrorsToleratedAnn]; found {
+		tolerated := &ibuv1.ToleratedRestoreErrors{}
+		if err := json.Unmarshal([]byte(recorded), tolerated); err == nil {
+			tolerated.Restore = restore.Name
+			return tolerated, true, nil
+		}
+	}
+
+	// The annotations are validated during Prep, see ValidateOadpConfigmaps
+	tolerance, err := getRestoreErrorTolerance(restore)
+	if err != nil {
+		h.Log.Error(err, "Invalid error tolerance annotations, the restore errors are not tolerated", "restore", restore.Name)
+		return nil, true, nil
+	}
+	if tolerance == nil {
+		return nil, true, nil
+	}
+
+	errs, downloaded, err := getRestoreErrors(ctx, h.Client, restore)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get errors of restore %s: %w", restore.Name, err)
+	}
+	if !downloaded {
+		h.Log.Info("Waiting for the results of restore to be downloaded", "restore", restore.Name)
+		return nil, false, nil
+	}
+	tolerated := newToleratedRestoreErrors(restore.Name, errs)
+	if ok, reason := tolerance.tolerates(errs); !ok {
+		h.Log.Info("Restore errors are not tolerated", "restore", restore.Name, "reason", reason,
+			"count", tolerated.Count, "errors", tolerated.Errors)
+		return nil, true, nil
+	}
+
+	recorded, err := json.Marshal(tolerated)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to marshal errors of restore %s: %w", restore.Name, err)
+	}
+	if restore.Annotations == nil {
+		restore.Annotations = map[string]string{}
+	}
+	restore.Annotations[restoreErrorsToleratedAnn] = string(recorded)
+	if err := h.Update(ctx, restore); err != nil {
+		return nil, false, fmt.Errorf("failed to record tolerated errors of restore %s: %w", restore.Name, err)
+	}
+	h.Log.Info("Restore errors tolerated", "restore", restore.Name, "count", tolerated.Count, "errors", tolerated.Errors)
+	return &tolerated, true, nil
+}
@@ -0,0 +1,237 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backuprestore
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	ibuv1 "github.com/openshift-kni/lifecycle-agent/api/imagebasedupgrade/v1"
+	configv1 "github.com/openshift/api/config/v1"
+	"github.com/stretchr/testify/assert"
+	velerov1 "github.com/vmware-tanzu/velero/pkg/apis/velero/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+func TestGetRestoreErrorTolerance(t *testing.T) {
+	newRestore := func(annotations map[string]string) *velerov1.Restore {
+		return &velerov1.Restore{ObjectMeta: metav1.ObjectMeta{Name: "restore", Annotations: annotations}}
+	}
+
+	tolerance, err := getRestoreErrorTolerance(newRestore(nil))
+	assert.NoError(t, err)
+	assert.Nil(t, tolerance)
+
+	tolerance, err = getRestoreErrorTolerance(newRestore(map[string]string{
+		toleratedRestoreErrorsAnn: "events\\.events\\.k8s\\.io\n\n  field is immutable  ",
+	}))
+	assert.NoError(t, err)
+	assert.Len(t, tolerance.patterns, 2)
+	assert.Equal(t, -1, tolerance.maxErrors)
+
+	tolerance, err = getRestoreErrorTolerance(newRestore(map[string]string{maxToleratedRestoreErrorsAnn: "3"}))
+	assert.NoError(t, err)
+	assert.Empty(t, tolerance.patterns)
+	assert.Equal(t, 3, tolerance.maxErrors)
+
+	_, err = getRestoreErrorTolerance(newRestore(map[string]string{maxToleratedRestoreErrorsAnn: "-1"}))
+	assert.ErrorContains(t, err, "invalid lca.openshift.io/max-tolerated-restore-errors value")
+
+	_, err = getRestoreErrorTolerance(newRestore(map[string]string{toleratedRestoreErrorsAnn: "events("}))
+	assert.ErrorContains(t, err, "invalid lca.openshift.io/tolerated-restore-errors pattern")
+}
+
+func TestRestoreErrorToleranceTolerates(t *testing.T) {
+	errs := flattenRestoreResult(restoreResult{
+		Cluster: []string{"error restoring clusterroles.rbac.authorization.k8s.io/admin: field is immutable"},
+		Namespaces: map[string][]string{
+			"my-app": {"error restoring events.events.k8s.io/my-app/event-1"},
+		},
+	})
+	assert.Equal(t, []string{
+		"error restoring clusterroles.rbac.authorization.k8s.io/admin: field is immutable",
+		"my-app: error restoring events.events.k8s.io/my-app/event-1",
+	}, errs)
+
+	tolerance := &restoreErrorTolerance{maxErrors: 2}
+	tolerated, _ := tolerance.tolerates(errs)
+	assert.True(t, tolerated)
+
+	tolerance.maxErrors = 1
+	tolerated, reason := tolerance.tolerates(errs)
+	assert.False(t, tolerated)
+	assert.Equal(t, "2 errors exceed the maximum of 1 tolerated errors", reason)
+
+	tolerance, err := getRestoreErrorTolerance(&velerov1.Restore{ObjectMeta: metav1.ObjectMeta{
+		Annotations: map[string]string{toleratedRestoreErrorsAnn: "events\\.events\\.k8s\\.io"},
+	}})
+	assert.NoError(t, err)
+	tolerated, reason = tolerance.tolerates(errs)
+	assert.False(t, tolerated)
+	assert.Contains(t, reason, "does not match any tolerated pattern")
+
+	tolerance.patterns = append(tolerance.patterns, regexp.MustCompile("field is immutable$"))
+	tolerated, _ = tolerance.tolerates(errs)
+	assert.True(t, tolerated)
+}
+
+func TestValidateOadpConfigmapsErrorTolerance(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		expectedErr string
+	}{
+		{
+			name:        "valid annotations",
+			annotations: map[string]string{toleratedRestoreErrorsAnn: "events\\.events\\.k8s\\.io", maxToleratedRestoreErrorsAnn: "5"},
+		},
+		{
+			name:        "invalid pattern",
+			annotations: map[string]string{toleratedRestoreErrorsAnn: "events("},
+			expectedErr: "invalid error tolerance annotation in restore restore1: invalid lca.openshift.io/tolerated-restore-errors pattern",
+		},
+		{
+			name:        "invalid maximum",
+			annotations: map[string]string{maxToleratedRestoreErrorsAnn: "many"},
+			expectedErr: "invalid error tolerance annotation in restore restore1: invalid lca.openshift.io/max-tolerated-restore-errors value \"many\"",
+		},
+		{
+			name:        "negative maximum",
+			annotations: map[string]string{maxToleratedRestoreErrorsAnn: "-1"},
+			expectedErr: "invalid error tolerance annotation in restore restore1: invalid lca.openshift.io/max-tolerated-restore-errors value \"-1\"",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			backup := fakeBackupCr("backup1", "1", "deployments")
+			backupBytes, err := yaml.Marshal(backup)
+			assert.NoError(t, err)
+			restore := fakeRestoreCr("restore1", "1", backup.Name)
+			for key, value := range tc.annotations {
+				restore.Annotations[key] = value
+			}
+			restoreBytes, err := yaml.Marshal(restore)
+			assert.NoError(t, err)
+			cm := fakeConfigmap("oadp-cm", "1", 0, 0, false)
+			cm.Data[backup.Name] = string(backupBytes)
+			cm.Data[restore.Name] = string(restoreBytes)
+
+			fakeClient, err := getFakeClientFromObjects(cm)
+			assert.NoError(t, err)
+			handler := &BRHandler{Client: fakeClient, Log: ctrl.Log.WithName("BackupRestore")}
+
+			err = handler.ValidateOadpConfigmaps(context.Background(),
+				[]ibuv1.ConfigMapRef{{Name: cm.Name, Namespace: cm.Namespace}}, false)
+			if tc.expectedErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tc.expectedErr)
+			assert.True(t, IsBRFailedValidationError(err))
+		})
+	}
+}
+
+func TestTrackPartiallyFailedRestore(t *testing.T) {
+	oldGetRestoreErrors := getRestoreErrors
+	defer func() {
+		getRestoreErrors = oldGetRestoreErrors
+	}()
+	downloads := 0
+	downloaded := false
+	getRestoreErrors = func(ctx context.Context, c client.Client, restore *velerov1.Restore) ([]string, bool, error) {
+		if !downloaded {
+			return nil, false, nil
+		}
+		downloads++
+		return []string{"my-app: error restoring events.events.k8s.io/my-app/event-1"}, true, nil
+	}
+
+	clusterVersion := &configv1.ClusterVersion{
+		ObjectMeta: metav1.ObjectMeta{Name: "version"},
+		Spec:       configv1.ClusterVersionSpec{ClusterID: configv1.ClusterID(testClusterID)},
+	}
+	tolerated := fakeRestoreCrWithStatus("restore1", "1", "backup1", velerov1.RestorePhasePartiallyFailed)
+	tolerated.Annotations = map[string]string{toleratedRestoreErrorsAnn: "events\\.events\\.k8s\\.io"}
+	notTolerated := fakeRestoreCrWithStatus("restore2", "1", "backup2", velerov1.RestorePhasePartiallyFailed)
+	notTolerated.Annotations = map[string]string{maxToleratedRestoreErrorsAnn: "0"}
+	noTolerance := fakeRestoreCrWithStatus("restore3", "1", "backup3", velerov1.RestorePhasePartiallyFailed)
+
+	fakeClient, err := getFakeClientFromObjects(clusterVersion, tolerated, notTolerated, noTolerance)
+	assert.NoError(t, err)
+	handler := &BRHandler{Client: fakeClient, Log: ctrl.Log.WithName("BackupRestore")}
+
+	restores := []*velerov1.Restore{
+		fakeRestoreCr("restore1", "1", "backup1"),
+		fakeRestoreCr("restore2", "1", "backup2"),
+		fakeRestoreCr("restore3", "1", "backup3"),
+	}
+	expectedTolerated := []ibuv1.ToleratedRestoreErrors{
+		{Restore: "restore1", Count: 1, Errors: []string{"my-app: error restoring events.events.k8s.io/my-app/event-1"}},
+	}
+
+	// The restores are in progress until their results are downloaded
+	restoreTracker, err := handler.StartOrTrackRestore(context.Background(), restores)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"restore1", "restore2"}, restoreTracker.ProgressingRestores)
+	assert.Equal(t, []string{"restore3"}, restoreTracker.FailedRestores)
+	assert.Empty(t, restoreTracker.SucceededRestores)
+
+	downloaded = true
+	restoreTracker, err = handler.StartOrTrackRestore(context.Background(), restores)
+	assert.NoError(t, err)
+	assert.Empty(t, restoreTracker.ProgressingRestores)
+	assert.Equal(t, []string{"restore1"}, restoreTracker.SucceededRestores)
+	assert.Equal(t, []string{"restore2", "restore3"}, restoreTracker.FailedRestores)
+	assert.Equal(t, expectedTolerated, restoreTracker.ToleratedRestores)
+	assert.Equal(t, 2, downloads)
+
+	// The tolerated errors are recorded in the restore, and not downloaded again
+	restore := &velerov1.Restore{}
+	assert.NoError(t, fakeClient.Get(context.Background(), types.NamespacedName{Name: "restore1", Namespace: oadpNs}, restore))
+	assert.NotEmpty(t, restore.Annotations[restoreErrorsToleratedAnn])
+
+	restoreTracker, err = handler.StartOrTrackRestore(context.Background(), restores[:1])
+	assert.NoError(t, err)
+	assert.Equal(t, expectedTolerated, restoreTracker.ToleratedRestores)
+	assert.Equal(t, 2, downloads)
+}
+
+func TestNewToleratedRestoreErrors(t *testing.T) {
+	var errs []string
+	for i := 0; i < 15; i++ {
+		errs = append(errs, fmt.Sprintf("my-app: error restoring events.events.k8s.io/my-app/event-%d", i))
+	}
+	errs[0] = "my-app: " + strings.Repeat("x", 300)
+
+	tolerated := newToleratedRestoreErrors("restore1", errs)
+	assert.Equal(t, "restore1", tolerated.Restore)
+	assert.Equal(t, 15, tolerated.Count)
+	assert.Len(t, tolerated.Errors, maxReportedRestoreErrors)
+	assert.Len(t, tolerated.Errors[0], maxRestoreErrorLength)
+	assert.True(t, strings.HasSuffix(tolerated.Errors[0], "..."))
+	assert.Equal(t, errs[1:maxReportedRestoreErrors], tolerated.Errors[1:])
+
+	assert.Equal(t, ibuv1.ToleratedRestoreErrors{Restore: "restore1", Errors: []string{}}, newToleratedRestoreErrors("restore1", nil))
+}
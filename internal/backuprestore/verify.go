@@ -0,0 +1,434 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backuprestore
+
+import (
+	"compress/gzip"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	velerov1 "github.com/vmware-tanzu/velero/pkg/apis/velero/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// +kubebuilder:rbac:groups=velero.io,resources=downloadrequests,verbs=get;list;delete;create;watch
+
+const (
+	// verifyRequiredResourcesAnn lists the GroupResources that must have at least one item in the backup, e.g.
+	// "deployments.apps,persistentvolumeclaims"
+	verifyRequiredResourcesAnn = "lca.openshift.io/verify-required-resources"
+	// verifyMinItemsAnn lists the minimum item counts of GroupResources in the backup, optionally restricted to a
+	// namespace, e.g. "deployments.apps/my-app=2,persistentvolumeclaims=3"
+	verifyMinItemsAnn = "lca.openshift.io/verify-min-items"
+	// verifyResultAnn records the result of the verification on the Backup CR, so that the backup is verified once:
+	// "Passed", or the unmet expectations otherwise
+	verifyResultAnn    = "lca.openshift.io/verify-result"
+	verifyResultPassed = "Passed"
+
+	downloadRequestSuffix = "-lca-resource-list"
+)
+
+// downloadRequestTimeout is the time given to Velero to process a DownloadRequest
+var downloadRequestTimeout = 2 * time.Minute
+
+// BackupContents holds the item counts of a backup per GroupResource and namespace.
+// Cluster scoped items are counted with an empty namespace.
+type BackupContents map[string]map[string]int
+
+// count returns the number of items of the GroupResource, in the namespace or in every namespace if empty
+func (c BackupContents) count(resource, namespace string) int {
+	if namespace != "" {
+		return c[resource][namespace]
+	}
+	total := 0
+	for _, n := range c[resource] {
+		total += n
+	}
+	return total
+}
+
+// minItemsExpectation is the minimum number of items of a GroupResource, optionally in a namespace
+type minItemsExpectation struct {
+	resource  string
+	namespace string
+	count     int
+}
+
+// backupExpectations are the backup contents declared as expected with the verify annotations of a Backup CR
+type backupExpectations struct {
+	requiredResources []string
+	minItems          []minItemsExpectation
+}
+
+// getBackupExpectations parses the verify annotations of the backup, and returns nil if there are none
+func getBackupExpectations(backup *velerov1.Backup) (*backupExpectations, error) {
+	annotations := backup.GetAnnotations()
+	required, hasRequired := annotations[verifyRequiredResourcesAnn]
+	minItems, hasMinItems := annotations[verifyMinItemsAnn]
+	if !hasRequired && !hasMinItems {
+		return nil, nil
+	}
+
+	expectations := &backupExpectations{}
+	for _, resource := range strings.Split(required, ",") {
+		resource = strings.TrimSpace(resource)
+		if resource == "" {
+			continue
+		}
+		expectations.requiredResources = append(expectations.requiredResources, resource)
+	}
+
+	for _, entry := range strings.Split(minItems, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		target, countString, found := strings.Cut(entry, "=")
+		if !found {
+			return nil, fmt.Errorf("invalid %s entry %q, expected <resource>[/<namespace>]=<count>", verifyMinItemsAnn, entry)
+		}
+		count, err := strconv.Atoi(countString)
+		if err != nil || count < 0 {
+			return nil, fmt.Errorf("invalid count in %s entry %q", verifyMinItemsAnn, entry)
+		}
+		resource, namespace, _ := strings.Cut(target, "/")
+		if resource == "" {
+			return nil, fmt.Errorf("missing resource in %s entry %q", verifyMinItemsAnn, entry)
+		}
+		expectations.minItems = append(expectations.minItems, minItemsExpectation{resource: resource, namespace: namespace, count: count})
+	}
+
+	return expectations, nil
+}
+
+// check returns the expectations that the backup contents do not meet
+func (e *backupExpectations) check(contents BackupContents) []string {
+	var failures []string
+	for _, resource := range e.requiredResources {
+		if contents.count(resource, "") == 0 {
+			failures = append(failures, fmt.Sprintf("no %s found", resource))
+		}
+	}
+	for _, expected := range e.minItems {
+		if found := contents.count(expected.resource, expected.namespace); found < expected.count {
+			target := expected.resource
+			if expected.namespace != "" {
+				target = fmt.Sprintf("%s in namespace %s", expected.resource, expected.namespace)
+			}
+			failures = append(failures, fmt.Sprintf("expected at least %d %s, found %d", expected.count, target, found))
+		}
+	}
+	return failures
+}
+
+// VerifyBackups checks that the contents of the completed backups match the expectations declared with the
+// lca.openshift.io/verify-required-resources and lca.openshift.io/verify-min-items annotations of their Backup CR.
+// Backups without these annotations are not verified. Each backup is verified once, the result being recorded with
+// the lca.openshift.io/verify-result annotation of the Backup CR. The contents of the backups are downloaded without
+// waiting, and it returns whether every backup is verified. A BRFailedError is returned if any expectation is not met.
+func (h *BRHandler) VerifyBackups(ctx context.Context, backups []*velerov1.Backup) (bool, error) {
+	var failures []string
+	verified := true
+	for _, backup := range backups {
+		expectations, err := getBackupExpectations(backup)
+		if err != nil {
+			return false, NewBRFailedValidationError("Backup", err.Error())
+		}
+		if expectations == nil {
+			continue
+		}
+
+		existingBackup, err := getBackup(ctx, h.Client, backup.Name, backup.Namespace)
+		if err != nil {
+			return false, err
+		}
+		if existingBackup == nil {
+			return false, fmt.Errorf("backup %s not found", backup.Name)
+		}
+
+		if result, found := existingBackup.GetAnnotations()[verifyResultAnn]; found {
+			if result != verifyResultPassed {
+				failures = append(failures, fmt.Sprintf("%s: %s", backup.Name, result))
+			}
+			continue
+		}
+
+		contents, downloaded, err := h.GetBackupContents(ctx, existingBackup)
+		if err != nil {
+			return false, fmt.Errorf("failed to get the contents of backup %s: %w", backup.Name, err)
+		}
+		if !downloaded {
+			h.Log.Info("Waiting for the contents of backup to be downloaded", "name", backup.Name)
+			verified = false
+			continue
+		}
+		h.Log.Info("Backup contents", "name", backup.Name, "contents", contents)
+
+		var backupFailures []string
+		if progress := existingBackup.Status.Progress; progress != nil && progress.ItemsBackedUp < progress.TotalItems {
+			backupFailures = append(backupFailures, fmt.Sprintf("only %d of %d items backed up", progress.ItemsBackedUp, progress.TotalItems))
+		}
+
+		backupFailures = append(backupFailures, expectations.check(contents)...)
+		result := verifyResultPassed
+		if len(backupFailures) > 0 {
+			result = strings.Join(backupFailures, ", ")
+			failures = append(failures, fmt.Sprintf("%s: %s", backup.Name, result))
+		}
+
+		metav1.SetMetaDataAnnotation(&existingBackup.ObjectMeta, verifyResultAnn, result)
+		if err := h.Update(ctx, existingBackup); err != nil {
+			return false, fmt.Errorf("failed to record the verification result of backup %s: %w", backup.Name, err)
+		}
+	}
+
+	if len(failures) > 0 {
+		errMsg := fmt.Sprintf("Backup verification failed: %s", strings.Join(failures, "; "))
+		h.Log.Error(nil, errMsg)
+		return false, NewBRFailedError("Backup", errMsg)
+	}
+	return verified, nil
+}
+
+// GetBackupContents counts the items of a backup per GroupResource and namespace, from the resource list that
+// Velero stores with the backup. It returns whether the resource list was downloaded, see downloadFromBackupStorage.
+func (h *BRHandler) GetBackupContents(ctx context.Context, backup *velerov1.Backup) (BackupContents, bool, error) {
+	resourceList, downloaded, err := getBackupResourceList(ctx, h.Client, backup)
+	if err != nil || !downloaded {
+		return nil, false, err
+	}
+
+	contents := BackupContents{}
+	for gvk, items := range resourceList {
+		resource, err := getGroupResource(h.Client, gvk)
+		if err != nil {
+			return nil, false, err
+		}
+		if contents[resource] == nil {
+			contents[resource] = map[string]int{}
+		}
+		for _, item := range items {
+			namespace, _, found := strings.Cut(item, "/")
+			if !found {
+				namespace = ""
+			}
+			contents[resource][namespace]++
+		}
+	}
+	return contents, true, nil
+}
+
+// getGroupResource converts a "<group>/<version>/<kind>" key of a Velero resource list to a GroupResource string,
+// e.g. "apps/v1/Deployment" to "deployments.apps"
+func getGroupResource(c client.Client, gvk string) (string, error) {
+	var gv, kind string
+	if i := strings.LastIndex(gvk, "/"); i >= 0 {
+		gv, kind = gvk[:i], gvk[i+1:]
+	}
+	groupVersion, err := schema.ParseGroupVersion(gv)
+	if err != nil || kind == "" {
+		return "", fmt.Errorf("invalid resource list key %s", gvk)
+	}
+	mapping, err := c.RESTMapper().RESTMapping(schema.GroupKind{Group: groupVersion.Group, Kind: kind}, groupVersion.Version)
+	if err != nil {
+		return "", fmt.Errorf("failed to get the resource of %s: %w", gvk, err)
+	}
+	return mapping.Resource.GroupResource().String(), nil
+}
+
+// getBackupResourceList downloads the resource list of a backup from its backup storage location, using a Velero
+// DownloadRequest. The list maps each "<group>/<version>/<kind>" to its "<namespace>/<name>" items. It returns
+// whether the list was downloaded, see downloadFromBackupStorage.
+var getBackupResourceList = func(ctx context.Context, c client.Client, backup *velerov1.Backup) (map[string][]string, bool, error) {
+	target := velerov1.DownloadTarget{Kind: velerov1.DownloadTargetKindBackupResourceList, Name: backup.Name}
+	body, downloaded, err := downloadFromBackupStorage(ctx, c, backup, target, backup.Name+downloadRequestSuffix)
+	if err != nil || !downloaded {
+		return nil, false, err
+	}
+	defer body.Close()
+
+	resourceList, err := decodeResourceList(body)
+	if err != nil {
+		return nil, false, err
+	}
+	return resourceList, true, nil
+}
+
+// downloadFromBackupStorage downloads a file of the backup, or of a restore of the backup, from the backup storage
+// location, using a Velero DownloadRequest with the given name. It does not wait for Velero to process the request:
+// the first call creates the request, and a later call downloads the file once the request is processed. It returns
+// whether the file was downloaded, in which case the caller must close the returned body.
+func downloadFromBackupStorage(ctx context.Context, c client.Client, backup *velerov1.Backup,
+	target velerov1.DownloadTarget, name string) (io.ReadCloser, bool, error) {
+	downloadRequest := &velerov1.DownloadRequest{}
+	err := c.Get(ctx, types.NamespacedName{Name: name, Namespace: backup.Namespace}, downloadRequest)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return nil, false, fmt.Errorf("failed to get DownloadRequest %s: %w", name, err)
+	}
+
+	// A previous request may be left over for another target, or have expired
+	if err == nil && (downloadRequest.Spec.Target != target || isDownloadRequestExpired(downloadRequest)) {
+		if err := c.Delete(ctx, downloadRequest); err != nil && !k8serrors.IsNotFound(err) {
+			return nil, false, fmt.Errorf("failed to delete DownloadRequest %s: %w", name, err)
+		}
+		err = k8serrors.NewNotFound(velerov1.Resource("downloadrequests"), name)
+	}
+	if err != nil {
+		downloadRequest = &velerov1.DownloadRequest{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: backup.Namespace,
+			},
+			Spec: velerov1.DownloadRequestSpec{
+				Target: target,
+			},
+		}
+		if err := c.Create(ctx, downloadRequest); err != nil {
+			return nil, false, fmt.Errorf("failed to create DownloadRequest %s: %w", name, err)
+		}
+		return nil, false, nil
+	}
+
+	if downloadRequest.Status.Phase != velerov1.DownloadRequestPhaseProcessed || downloadRequest.Status.DownloadURL == "" {
+		if time.Since(downloadRequest.CreationTimestamp.Time) > downloadRequestTimeout {
+			// Start over with a new request on the next attempt
+			_ = c.Delete(ctx, downloadRequest)
+			return nil, false, fmt.Errorf("DownloadRequest %s was not processed in %s", name, downloadRequestTimeout)
+		}
+		return nil, false, nil
+	}
+
+	// The download URL is only valid for a short time, the next download starts with a new request
+	defer func() {
+		_ = c.Delete(context.Background(), downloadRequest)
+	}()
+
+	httpClient, err := getBackupStorageHTTPClient(ctx, c, backup)
+	if err != nil {
+		return nil, false, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, downloadRequest.Status.DownloadURL, nil)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to create %s request: %w", target.Kind, err)
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to download %s: %w", target.Kind, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, false, fmt.Errorf("failed to download %s: %s", target.Kind, resp.Status)
+	}
+	return resp.Body, true, nil
+}
+
+// isDownloadRequestExpired returns whether the download URL of a processed DownloadRequest has expired
+func isDownloadRequestExpired(downloadRequest *velerov1.DownloadRequest) bool {
+	expiration := downloadRequest.Status.Expiration
+	return expiration != nil && expiration.Time.Before(time.Now())
+}
+
+// decodeResourceList decodes the gzipped JSON resource list of a backup
+func decodeResourceList(r io.Reader) (map[string][]string, error) {
+	resourceList := map[string][]string{}
+	if err := decodeGzippedJSON(r, &resourceList); err != nil {
+		return nil, fmt.Errorf("failed to decode resource list: %w", err)
+	}
+	return resourceList, nil
+}
+
+// decodeGzippedJSON decodes the gzipped JSON files that Velero stores in the backup storage location
+func decodeGzippedJSON(r io.Reader, v any) error {
+	gzipReader, err := gzip.NewReader(r)
+	if err != nil {
+		return fmt.Errorf("failed to read gzipped file: %w", err)
+	}
+	defer gzipReader.Close()
+
+	if err := json.NewDecoder(gzipReader).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode JSON: %w", err)
+	}
+	return nil
+}
+
+// getBackupStorageHTTPClient returns an HTTP client trusting the CA bundle of the backup storage location, if any
+func getBackupStorageHTTPClient(ctx context.Context, c client.Client, backup *velerov1.Backup) (*http.Client, error) {
+	bsl := &velerov1.BackupStorageLocation{}
+	if err := c.Get(ctx, types.NamespacedName{Name: backup.Spec.StorageLocation, Namespace: backup.Namespace}, bsl); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return http.DefaultClient, nil
+		}
+		return nil, fmt.Errorf("failed to get BackupStorageLocation %s: %w", backup.Spec.StorageLocation, err)
+	}
+	if bsl.Spec.ObjectStorage == nil {
+		return http.DefaultClient, nil
+	}
+
+	caCert := bsl.Spec.ObjectStorage.CACert
+	if ref := bsl.Spec.ObjectStorage.CACertRef; ref != nil {
+		secret := &corev1.Secret{}
+		if err := c.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: bsl.Namespace}, secret); err != nil {
+			return nil, fmt.Errorf("failed to get the CA certificate secret %s: %w", ref.Name, err)
+		}
+		caCert = secret.Data[ref.Key]
+	}
+	if len(caCert) == 0 {
+		return http.DefaultClient, nil
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	pool.AppendCertsFromPEM(caCert)
+	return &http.Client{
+		Transport: &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12},
+		},
+	}, nil
+}
+
+// String formats the contents as a sorted list of "<resource>[/<namespace>]=<count>" entries, for logging
+func (c BackupContents) String() string {
+	var entries []string
+	for resource, namespaces := range c {
+		for namespace, count := range namespaces {
+			target := resource
+			if namespace != "" {
+				target = resource + "/" + namespace
+			}
+			entries = append(entries, fmt.Sprintf("%s=%d", target, count))
+		}
+	}
+	sort.Strings(entries)
+	return strings.Join(entries, ",")
+}
@@ -0,0 +1,258 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backuprestore
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	velerov1 "github.com/vmware-tanzu/velero/pkg/apis/velero/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestGetBackupExpectations(t *testing.T) {
+	backup := fakeBackupCr("backup", "1", "")
+	expectations, err := getBackupExpectations(backup)
+	assert.NoError(t, err)
+	assert.Nil(t, expectations)
+
+	backup.Annotations[verifyRequiredResourcesAnn] = "deployments.apps, persistentvolumeclaims"
+	backup.Annotations[verifyMinItemsAnn] = "deployments.apps/my-app=2,configmaps=3"
+	expectations, err = getBackupExpectations(backup)
+	assert.NoError(t, err)
+	assert.Equal(t, &backupExpectations{
+		requiredResources: []string{"deployments.apps", "persistentvolumeclaims"},
+		minItems: []minItemsExpectation{
+			{resource: "deployments.apps", namespace: "my-app", count: 2},
+			{resource: "configmaps", count: 3},
+		},
+	}, expectations)
+
+	for _, invalid := range []string{"deployments.apps/my-app", "configmaps=many", "configmaps=-1", "/my-app=1"} {
+		backup.Annotations[verifyMinItemsAnn] = invalid
+		_, err = getBackupExpectations(backup)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestDecodeResourceList(t *testing.T) {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	_, err := w.Write([]byte(`{"apps/v1/Deployment":["my-app/web"],"v1/Namespace":["my-app"]}`))
+	assert.NoError(t, err)
+	assert.NoError(t, w.Close())
+
+	resourceList, err := decodeResourceList(&buf)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]string{"apps/v1/Deployment": {"my-app/web"}, "v1/Namespace": {"my-app"}}, resourceList)
+}
+
+func TestVerifyBackups(t *testing.T) {
+	oldGetBackupResourceList := getBackupResourceList
+	defer func() {
+		getBackupResourceList = oldGetBackupResourceList
+	}()
+	resourceList := func(ctx context.Context, c client.Client, backup *velerov1.Backup) (map[string][]string, bool, error) {
+		return map[string][]string{
+			"apps/v1/Deployment":       {"my-app/web", "my-app/db", "other/web"},
+			"v1/PersistentVolumeClaim": {"my-app/data"},
+			"v1/Namespace":             {"my-app", "other"},
+		}, true, nil
+	}
+	getBackupResourceList = resourceList
+
+	newBackup := func(required, minItems string, progress *velerov1.BackupProgress) *velerov1.Backup {
+		backup := fakeBackupCr("backup", "1", "")
+		if required != "" {
+			backup.Annotations[verifyRequiredResourcesAnn] = required
+		}
+		if minItems != "" {
+			backup.Annotations[verifyMinItemsAnn] = minItems
+		}
+		backup.Status.Phase = velerov1.BackupPhaseCompleted
+		backup.Status.Progress = progress
+		return backup
+	}
+
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}, meta.RESTScopeNamespace)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "PersistentVolumeClaim"}, meta.RESTScopeNamespace)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "Namespace"}, meta.RESTScopeRoot)
+
+	tests := []struct {
+		name        string
+		backup      *velerov1.Backup
+		expectedErr string
+	}{
+		{
+			name:   "no expectations",
+			backup: newBackup("", "", &velerov1.BackupProgress{TotalItems: 10, ItemsBackedUp: 2}),
+		},
+		{
+			name:   "expectations met",
+			backup: newBackup("persistentvolumeclaims", "deployments.apps/my-app=2,deployments.apps=3,namespaces=2", nil),
+		},
+		{
+			name:        "required resource missing",
+			backup:      newBackup("persistentvolumeclaims,secrets", "", nil),
+			expectedErr: "Backup verification failed: backup: no secrets found",
+		},
+		{
+			name:        "not enough items",
+			backup:      newBackup("", "deployments.apps/other=2", &velerov1.BackupProgress{TotalItems: 7, ItemsBackedUp: 6}),
+			expectedErr: "Backup verification failed: backup: only 6 of 7 items backed up, expected at least 2 deployments.apps in namespace other, found 1",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c := fake.NewClientBuilder().WithScheme(testscheme).WithRESTMapper(mapper).WithObjects(tc.backup).Build()
+			handler := &BRHandler{Client: c, Log: ctrl.Log.WithName("BackupRestore")}
+
+			// The result is recorded on the Backup CR, so the second call does not verify the backup again
+			for i := 0; i < 2; i++ {
+				verified, err := handler.VerifyBackups(context.Background(), []*velerov1.Backup{tc.backup})
+				if tc.expectedErr == "" {
+					assert.NoError(t, err)
+					assert.True(t, verified)
+				} else {
+					assert.EqualError(t, err, tc.expectedErr)
+					assert.True(t, IsBRFailedError(err))
+				}
+				getBackupResourceList = func(ctx context.Context, c client.Client, backup *velerov1.Backup) (map[string][]string, bool, error) {
+					return nil, false, fmt.Errorf("backup %s verified again", backup.Name)
+				}
+			}
+			getBackupResourceList = resourceList
+		})
+	}
+
+	t.Run("resource list not downloaded yet", func(t *testing.T) {
+		backup := newBackup("persistentvolumeclaims", "", nil)
+		c := fake.NewClientBuilder().WithScheme(testscheme).WithRESTMapper(mapper).WithObjects(backup).Build()
+		handler := &BRHandler{Client: c, Log: ctrl.Log.WithName("BackupRestore")}
+		getBackupResourceList = func(ctx context.Context, c client.Client, backup *velerov1.Backup) (map[string][]string, bool, error) {
+			return nil, false, nil
+		}
+		defer func() {
+			getBackupResourceList = resourceList
+		}()
+
+		verified, err := handler.VerifyBackups(context.Background(), []*velerov1.Backup{backup})
+		assert.NoError(t, err)
+		assert.False(t, verified)
+
+		// Nothing is recorded until the backup is verified
+		existing := &velerov1.Backup{}
+		assert.NoError(t, c.Get(context.Background(), client.ObjectKeyFromObject(backup), existing))
+		assert.NotContains(t, existing.Annotations, verifyResultAnn)
+	})
+}
+
+func TestDownloadFromBackupStorage(t *testing.T) {
+	testscheme.AddKnownTypes(velerov1.SchemeGroupVersion, &velerov1.DownloadRequest{}, &velerov1.BackupStorageLocation{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("resource list"))
+	}))
+	defer server.Close()
+
+	backup := fakeBackupCr("backup", "1", "")
+	backup.Spec.StorageLocation = "default"
+	target := velerov1.DownloadTarget{Kind: velerov1.DownloadTargetKindBackupResourceList, Name: backup.Name}
+	key := client.ObjectKey{Name: "backup-download", Namespace: backup.Namespace}
+
+	// The fake client does not set the creation timestamp used for the timeout
+	c := fake.NewClientBuilder().WithScheme(testscheme).WithInterceptorFuncs(interceptor.Funcs{
+		Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+			obj.SetCreationTimestamp(metav1.Now())
+			return c.Create(ctx, obj, opts...)
+		},
+	}).Build()
+	ctx := context.Background()
+
+	// The first call creates the request, and does not wait for it to be processed
+	body, downloaded, err := downloadFromBackupStorage(ctx, c, backup, target, key.Name)
+	assert.NoError(t, err)
+	assert.False(t, downloaded)
+	assert.Nil(t, body)
+	downloadRequest := &velerov1.DownloadRequest{}
+	assert.NoError(t, c.Get(ctx, key, downloadRequest))
+	assert.Equal(t, target, downloadRequest.Spec.Target)
+
+	_, downloaded, err = downloadFromBackupStorage(ctx, c, backup, target, key.Name)
+	assert.NoError(t, err)
+	assert.False(t, downloaded)
+
+	// Once processed, the file is downloaded and the request deleted
+	downloadRequest.Status = velerov1.DownloadRequestStatus{
+		Phase:       velerov1.DownloadRequestPhaseProcessed,
+		DownloadURL: server.URL,
+		Expiration:  &metav1.Time{Time: time.Now().Add(time.Minute)},
+	}
+	assert.NoError(t, c.Update(ctx, downloadRequest))
+	body, downloaded, err = downloadFromBackupStorage(ctx, c, backup, target, key.Name)
+	assert.NoError(t, err)
+	assert.True(t, downloaded)
+	content, err := io.ReadAll(body)
+	assert.NoError(t, err)
+	assert.NoError(t, body.Close())
+	assert.Equal(t, "resource list", string(content))
+	assert.True(t, k8serrors.IsNotFound(c.Get(ctx, key, &velerov1.DownloadRequest{})))
+
+	// An expired request is replaced by a new one
+	expired := &velerov1.DownloadRequest{
+		ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+		Spec:       velerov1.DownloadRequestSpec{Target: target},
+		Status: velerov1.DownloadRequestStatus{
+			Phase:       velerov1.DownloadRequestPhaseProcessed,
+			DownloadURL: server.URL,
+			Expiration:  &metav1.Time{Time: time.Now().Add(-time.Minute)},
+		},
+	}
+	assert.NoError(t, c.Create(ctx, expired))
+	_, downloaded, err = downloadFromBackupStorage(ctx, c, backup, target, key.Name)
+	assert.NoError(t, err)
+	assert.False(t, downloaded)
+	downloadRequest = &velerov1.DownloadRequest{}
+	assert.NoError(t, c.Get(ctx, key, downloadRequest))
+	assert.Empty(t, downloadRequest.Status.Phase)
+
+	// A request not processed in time is deleted, so that the next attempt starts over
+	oldTimeout := downloadRequestTimeout
+	defer func() {
+		downloadRequestTimeout = oldTimeout
+	}()
+	downloadRequestTimeout = 0
+	_, downloaded, err = downloadFromBackupStorage(ctx, c, backup, target, key.Name)
+	assert.ErrorContains(t, err, "DownloadRequest backup-download was not processed in 0s")
+	assert.False(t, downloaded)
+	assert.True(t, k8serrors.IsNotFound(c.Get(ctx, key, &velerov1.DownloadRequest{})))
+}
@@ -67,6 +67,7 @@ const (
 	ClusterConfigDir                  = "cluster-configuration"
 	ContainersListFileName            = "containers.list"
 	SeedClusterInfoFileName           = "manifest.json"
+	SeedImageDigestFileName           = "seed-image-digest"
 	SeedReconfigurationFileName       = "manifest.json"
 	ManifestsDir                      = "manifests"
 	ExtraManifestsDir                 = "extra-manifests"
@@ -198,10 +199,12 @@ const (
 // Annotation names and values related to extra manifest
 const (
 	ApplyWaveAnn        = "lca.openshift.io/apply-wave"
+	ApplyWaveTimeoutAnn = "lca.openshift.io/apply-wave-timeout"
 	defaultApplyWave    = math.MaxInt32 // 2147483647, an enough large number
 	ApplyTypeAnnotation = "lca.openshift.io/apply-type"
 	ApplyTypeReplace    = "replace" // default if annotation doesn't exist
 	ApplyTypeMerge      = "merge"
+	ApplyTypeDelete     = "delete"
 )
 
 var (
@@ -129,6 +129,12 @@ func GetStaterootCertsDir(ibu *ibuv1.ImageBasedUpgrade) string {
 	return PathOutsideChroot(filepath.Join(GetStaterootOptOpenshift(GetStaterootPath(GetDesiredStaterootName(ibu))), KubeconfigCryptoDir))
 }
 
+// GetStaterootSeedImageDigestFile returns the path of the file recording the digest of the seed image the new
+// stateroot was set up from
+func GetStaterootSeedImageDigestFile(ibu *ibuv1.ImageBasedUpgrade) string {
+	return PathOutsideChroot(filepath.Join(GetStaterootPath(GetDesiredStaterootName(ibu)), SeedDataDir, SeedImageDigestFileName))
+}
+
 func GetStaterootName(identifier string) string {
 	return fmt.Sprintf("rhcos_%s", strings.ReplaceAll(identifier, "-", "_"))
 }
@@ -0,0 +1,271 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package extramanifest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	ibuv1 "github.com/openshift-kni/lifecycle-agent/api/imagebasedupgrade/v1"
+	"github.com/openshift-kni/lifecycle-agent/internal/common"
+	"github.com/samber/lo"
+	"gomodules.xyz/jsonpatch/v2"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+)
+
+// The operations of the manifest diffs
+const (
+	DiffCreated   = "Created"
+	DiffUpdated   = "Updated"
+	DiffDeleted   = "Deleted"
+	DiffUnchanged = "Unchanged"
+	DiffUnknown   = "Unknown"
+)
+
+// ManifestDiff is the change a manifest makes to the cluster, computed with a dry-run
+type ManifestDiff struct {
+	// Source is the configmap or the policy of the manifest
+	Source     string `json:"source"`
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name"`
+	Operation  string `json:"operation"`
+	// Patch is the JSON patch from the object on the cluster to the updated object. The values of the secret data are
+	// redacted.
+	Patch []jsonpatch.Operation `json:"patch,omitempty"`
+	// Message is the reason why the dry-run could not be done, when the operation is Unknown
+	Message string `json:"message,omitempty"`
+}
+
+var (
+	secretGroupKind = schema.GroupKind{Kind: "Secret"}
+	// secretDataFields are the paths of the secret fields whose values are redacted from the patches
+	secretDataFields = []string{"/data", "/stringData"}
+)
+
+// diffIgnoredMetadata are the metadata fields set by the API server, which do not come from the manifests
+var diffIgnoredMetadata = []string{"resourceVersion", "managedFields", "generation", "uid", "creationTimestamp"}
+
+func newManifestDiff(source string, manifest *unstructured.Unstructured) ManifestDiff {
+	return ManifestDiff{
+		Source:     source,
+		APIVersion: manifest.GetAPIVersion(),
+		Kind:       manifest.GetKind(),
+		Namespace:  manifest.GetNamespace(),
+		Name:       manifest.GetName(),
+	}
+}
+
+// dryRunDiff applies the manifest with dryrun and returns the change it makes to the object on the cluster. The manifest
+// is modified as when it is applied.
+func dryRunDiff(ctx context.Context, dc dynamic.Interface, restMapper meta.RESTMapper, source string, manifest *unstructured.Unstructured) (ManifestDiff, error) {
+	diff := newManifestDiff(source, manifest)
+	resource, err := getResourceInterface(dc, restMapper, manifest)
+	if err != nil {
+		return diff, err
+	}
+
+	if manifest.GetAnnotations()[common.ApplyTypeAnnotation] == common.ApplyTypeDelete {
+		if _, err := resource.Get(ctx, manifest.GetName(), metav1.GetOptions{}); err != nil {
+			if k8serrors.IsNotFound(err) {
+				diff.Operation = DiffUnchanged
+				return diff, nil
+			}
+			return diff, fmt.Errorf("failed to get manifest %s called %s: %w", manifest.GetKind(), manifest.GetName(), err)
+		}
+		if err := deleteExtraManifest(ctx, resource, manifest, true); err != nil {
+			return diff, err
+		}
+		diff.Operation = DiffDeleted
+		return diff, nil
+	}
+
+	existing, applied, err := applyExtraManifest(ctx, resource, manifest, true)
+	if err != nil {
+		return diff, err
+	}
+	if existing == nil {
+		diff.Operation = DiffCreated
+		return diff, nil
+	}
+
+	diff.Patch, err = createPatch(existing, applied)
+	if err != nil {
+		return diff, err
+	}
+	if manifest.GroupVersionKind().GroupKind() == secretGroupKind {
+		diff.Patch = redactSecretPatch(diff.Patch)
+	}
+	diff.Operation = DiffUpdated
+	if len(diff.Patch) == 0 {
+		diff.Operation = DiffUnchanged
+	}
+	return diff, nil
+}
+
+// createPatch returns the JSON patch from the existing object to the applied one, ignoring the metadata set by the API
+// server. There is no patch when the object was not applied.
+func createPatch(existing, applied *unstructured.Unstructured) ([]jsonpatch.Operation, error) {
+	if applied == nil {
+		return nil, nil
+	}
+
+	var docs [][]byte
+	for _, obj := range []*unstructured.Unstructured{existing, applied} {
+		obj = obj.DeepCopy()
+		for _, field := range diffIgnoredMetadata {
+			unstructured.RemoveNestedField(obj.Object, "metadata", field)
+		}
+		doc, err := json.Marshal(obj.Object)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal %s called %s: %w", obj.GetKind(), obj.GetName(), err)
+		}
+		docs = append(docs, doc)
+	}
+
+	patch, err := jsonpatch.CreatePatch(docs[0], docs[1])
+	if err != nil {
+		return nil, fmt.Errorf("failed to create patch of %s called %s: %w", existing.GetKind(), existing.GetName(), err)
+	}
+	return patch, nil
+}
+
+// redactSecretPatch removes the values of the secret data from the patch, so that only the names of the changed keys
+// are reported
+func redactSecretPatch(patch []jsonpatch.Operation) []jsonpatch.Operation {
+	for i, op := range patch {
+		for _, field := range secretDataFields {
+			switch {
+			case strings.HasPrefix(op.Path, field+"/"):
+				patch[i].Value = nil
+			case op.Path == field:
+				// The whole data is added or replaced, its value is replaced with the names of its keys
+				if data, ok := op.Value.(map[string]interface{}); ok {
+					keys := lo.Keys(data)
+					sort.Strings(keys)
+					patch[i].Value = keys
+				} else {
+					patch[i].Value = nil
+				}
+			}
+		}
+	}
+	return patch
+}
+
+// isDryRunWarning returns whether the dryrun error is due to the manifest or the current cluster, rather than a
+// runtime error, in which case the manifest diff is unknown
+func isDryRunWarning(err error) bool {
+	var groupDiscoveryErr *discovery.ErrGroupDiscoveryFailed
+	return errors.As(err, &groupDiscoveryErr) || meta.IsNoMatchError(err) ||
+		k8serrors.IsInvalid(err) || k8serrors.IsBadRequest(err) || k8serrors.IsForbidden(err) || k8serrors.IsNotFound(err)
+}
+
+// DiffPolicyManifests computes with dryrun the changes the manifests extracted from the policies make to the cluster.
+// The groups of manifests are those returned by ValidateAndExtractManifestFromPolicies, along with their policies.
+// Namespaces created by earlier manifests are created for the dryrun and deleted afterward, and the manifests whose
+// dryrun cannot be done on the current cluster, e.g. as their CRD is not deployed yet, are reported as Unknown.
+func (h *EMHandler) DiffPolicyManifests(ctx context.Context, sortedObjects [][]*unstructured.Unstructured, selectedPolicies []ibuv1.SelectedPolicy) ([]ManifestDiff, error) {
+	if len(sortedObjects) != len(selectedPolicies) {
+		return nil, fmt.Errorf("got %d groups of manifests for %d policies", len(sortedObjects), len(selectedPolicies))
+	}
+
+	var diffs []ManifestDiff
+
+	// A namespace set saves all created namespaces for dryrun
+	fakedNamespacesForDryrun := make(map[string]bool)
+	// Cleanup any faked namespaces at the end of the diff
+	defer func() { _ = deleteFakedNamespacesForDryrun(ctx, h.Client, h.Log, fakedNamespacesForDryrun) }()
+
+	nsManifestsSet := make(map[string]bool) // A namespace set collects the namespaces created by the manifests
+	for i, objects := range sortedObjects {
+		source := fmt.Sprintf("Policy %s/%s", selectedPolicies[i].Namespace, selectedPolicies[i].Name)
+		for _, object := range objects {
+			manifest := object.DeepCopy()
+			isDeletion := manifest.GetAnnotations()[common.ApplyTypeAnnotation] == common.ApplyTypeDelete
+			if manifest.GetKind() == "Namespace" && !isDeletion {
+				nsManifestsSet[manifest.GetName()] = true
+			}
+
+			if mNamespace := manifest.GetNamespace(); mNamespace != "" && mNamespace != "default" {
+				ns := &corev1.Namespace{}
+				if err := h.Client.Get(ctx, types.NamespacedName{Name: mNamespace}, ns); err != nil {
+					if !k8serrors.IsNotFound(err) {
+						return nil, fmt.Errorf("failed to query namespace %s: %w", mNamespace, err)
+					}
+					if !nsManifestsSet[mNamespace] {
+						diff := newManifestDiff(source, manifest)
+						diff.Operation = DiffUnknown
+						diff.Message = fmt.Sprintf("namespace %s not found on cluster or not created by earlier manifests", mNamespace)
+						diffs = append(diffs, diff)
+						continue
+					}
+					faked, err := fakeNamespaceForDryrun(ctx, h.Client, h.Log, mNamespace)
+					if err != nil {
+						return nil, fmt.Errorf("failed to create namespace %s for dryrun: %w", mNamespace, err)
+					} else if faked {
+						fakedNamespacesForDryrun[mNamespace] = true
+					}
+				}
+			}
+
+			diff, err := dryRunDiff(ctx, h.DynamicClient, h.Client.RESTMapper(), source, manifest)
+			if err != nil {
+				if !isDryRunWarning(err) {
+					return nil, fmt.Errorf("failed to diff manifest with dryrun: %w", err)
+				}
+				diff.Operation = DiffUnknown
+				diff.Message = err.Error()
+			}
+			diffs = append(diffs, diff)
+		}
+	}
+	return diffs, nil
+}
+
+// SummarizeManifestDiffs counts the manifest diffs of each operation
+func SummarizeManifestDiffs(diffs []ManifestDiff) ibuv1.ExtraManifestsDiff {
+	summary := ibuv1.ExtraManifestsDiff{}
+	for _, diff := range diffs {
+		switch diff.Operation {
+		case DiffCreated:
+			summary.Created++
+		case DiffUpdated:
+			summary.Updated++
+		case DiffDeleted:
+			summary.Deleted++
+		case DiffUnchanged:
+			summary.Unchanged++
+		default:
+			summary.Unknown++
+		}
+	}
+	return summary
+}
@@ -0,0 +1,205 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package extramanifest
+
+import (
+	"context"
+	"testing"
+
+	ibuv1 "github.com/openshift-kni/lifecycle-agent/api/imagebasedupgrade/v1"
+	"github.com/openshift-kni/lifecycle-agent/internal/common"
+	"github.com/stretchr/testify/assert"
+	"gomodules.xyz/jsonpatch/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestDryRunDiff(t *testing.T) {
+	cmGvk := schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(cmGvk, meta.RESTScopeNamespace)
+
+	newConfigMap := func(name string, data map[string]interface{}, annotations map[string]string) *unstructured.Unstructured {
+		cm := newUnstructured(cmGvk, "default", name, annotations, nil)
+		delete(cm.Object, "status")
+		cm.Object["data"] = data
+		return cm
+	}
+	existing := newConfigMap("existing", map[string]interface{}{"a": "1", "b": "2"}, nil)
+	existing.SetResourceVersion("10")
+	existing.SetUID("1234")
+	unchanged := newConfigMap("unchanged", map[string]interface{}{"a": "1"}, map[string]string{"owner": "ztp"})
+	obsolete := newConfigMap("obsolete", nil, nil)
+	dc := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), existing, unchanged, obsolete)
+
+	testcases := []struct {
+		name          string
+		manifest      *unstructured.Unstructured
+		expectedOp    string
+		expectedPatch []jsonpatch.Operation
+	}{
+		{
+			name:       "object is created",
+			manifest:   newConfigMap("new", map[string]interface{}{"a": "1"}, nil),
+			expectedOp: DiffCreated,
+		},
+		{
+			name:       "object is replaced",
+			manifest:   newConfigMap("existing", map[string]interface{}{"a": "3", "c": "4"}, nil),
+			expectedOp: DiffUpdated,
+			expectedPatch: []jsonpatch.Operation{
+				{Operation: "remove", Path: "/data/b"},
+				{Operation: "add", Path: "/data/c", Value: "4"},
+				{Operation: "replace", Path: "/data/a", Value: "3"},
+			},
+		},
+		{
+			name: "object is merged with the same values",
+			manifest: newConfigMap("unchanged", map[string]interface{}{"a": "1"},
+				map[string]string{common.ApplyTypeAnnotation: common.ApplyTypeMerge}),
+			expectedOp: DiffUnchanged,
+		},
+		{
+			name: "object is deleted",
+			manifest: newConfigMap("obsolete", nil,
+				map[string]string{common.ApplyTypeAnnotation: common.ApplyTypeDelete}),
+			expectedOp: DiffDeleted,
+		},
+		{
+			name: "object to delete is not found",
+			manifest: newConfigMap("missing", nil,
+				map[string]string{common.ApplyTypeAnnotation: common.ApplyTypeDelete}),
+			expectedOp: DiffUnchanged,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			diff, err := dryRunDiff(context.Background(), dc, mapper, "ConfigMap default/cm", tc.manifest)
+			assert.NoError(t, err)
+			assert.Equal(t, "ConfigMap default/cm", diff.Source)
+			assert.Equal(t, "v1", diff.APIVersion)
+			assert.Equal(t, "ConfigMap", diff.Kind)
+			assert.Equal(t, "default", diff.Namespace)
+			assert.Equal(t, tc.manifest.GetName(), diff.Name)
+			assert.Equal(t, tc.expectedOp, diff.Operation)
+			assert.ElementsMatch(t, tc.expectedPatch, diff.Patch)
+		})
+	}
+}
+
+func TestDryRunDiffRedactsSecretData(t *testing.T) {
+	secretGvk := schema.GroupVersionKind{Version: "v1", Kind: "Secret"}
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(secretGvk, meta.RESTScopeNamespace)
+
+	newSecret := func(name string, data map[string]interface{}) *unstructured.Unstructured {
+		secret := newUnstructured(secretGvk, "default", name, nil, nil)
+		delete(secret.Object, "status")
+		if data != nil {
+			secret.Object["data"] = data
+		}
+		return secret
+	}
+	dc := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(),
+		newSecret("existing", map[string]interface{}{"user": "YWRtaW4=", "password": "czNjcjN0"}),
+		newSecret("empty", nil),
+	)
+
+	// Only the names of the changed keys are reported
+	diff, err := dryRunDiff(context.Background(), dc, mapper, "Secret default/secret",
+		newSecret("existing", map[string]interface{}{"password": "bjN3", "token": "dDBrM24="}))
+	assert.NoError(t, err)
+	assert.Equal(t, DiffUpdated, diff.Operation)
+	assert.ElementsMatch(t, []jsonpatch.Operation{
+		{Operation: "remove", Path: "/data/user"},
+		{Operation: "add", Path: "/data/token"},
+		{Operation: "replace", Path: "/data/password"},
+	}, diff.Patch)
+
+	diff, err = dryRunDiff(context.Background(), dc, mapper, "Secret default/secret",
+		newSecret("empty", map[string]interface{}{"token": "dDBrM24=", "password": "czNjcjN0"}))
+	assert.NoError(t, err)
+	assert.Equal(t, []jsonpatch.Operation{{Operation: "add", Path: "/data", Value: []string{"password", "token"}}}, diff.Patch)
+}
+
+func TestDiffPolicyManifests(t *testing.T) {
+	cmGvk := schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}
+	nsGvk := schema.GroupVersionKind{Version: "v1", Kind: "Namespace"}
+	widgetGvk := schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Widget"}
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(cmGvk, meta.RESTScopeNamespace)
+	mapper.Add(nsGvk, meta.RESTScopeRoot)
+	fakeClient := fake.NewClientBuilder().WithScheme(testscheme).WithRESTMapper(mapper).WithObjects(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "existing"}},
+	).Build()
+
+	handler := &EMHandler{
+		Client:        fakeClient,
+		DynamicClient: dynamicfake.NewSimpleDynamicClient(runtime.NewScheme()),
+		Log:           ctrl.Log.WithName("ExtraManifest"),
+	}
+
+	manifests := [][]*unstructured.Unstructured{
+		{
+			newUnstructured(nsGvk, "", "new", nil, nil),
+			newUnstructured(cmGvk, "new", "cm1", nil, nil),
+			newUnstructured(cmGvk, "existing", "cm2", nil, nil),
+		},
+		{
+			newUnstructured(cmGvk, "missing", "cm3", nil, nil),
+			newUnstructured(widgetGvk, "existing", "widget", nil, nil),
+		},
+	}
+	policies := []ibuv1.SelectedPolicy{
+		{Name: "ztp-common.p1", Namespace: "spoke", Manifests: 3},
+		{Name: "ztp-common.p2", Namespace: "spoke", Manifests: 2},
+	}
+
+	diffs, err := handler.DiffPolicyManifests(context.Background(), manifests, policies)
+	assert.NoError(t, err)
+	assert.Len(t, diffs, 5)
+	for i, expected := range []struct{ source, name, operation string }{
+		{"Policy spoke/ztp-common.p1", "new", DiffCreated},
+		{"Policy spoke/ztp-common.p1", "cm1", DiffCreated},
+		{"Policy spoke/ztp-common.p1", "cm2", DiffCreated},
+		{"Policy spoke/ztp-common.p2", "cm3", DiffUnknown},
+		{"Policy spoke/ztp-common.p2", "widget", DiffUnknown},
+	} {
+		assert.Equal(t, expected.source, diffs[i].Source)
+		assert.Equal(t, expected.name, diffs[i].Name)
+		assert.Equal(t, expected.operation, diffs[i].Operation)
+	}
+	assert.Equal(t, "namespace missing not found on cluster or not created by earlier manifests", diffs[3].Message)
+	assert.Contains(t, diffs[4].Message, `no matches for kind "Widget"`)
+
+	// The namespace created for the dryrun is deleted afterward
+	assert.Error(t, fakeClient.Get(context.Background(), client.ObjectKey{Name: "new"}, &corev1.Namespace{}))
+
+	assert.Equal(t, ibuv1.ExtraManifestsDiff{Created: 3, Unknown: 2}, SummarizeManifestDiffs(diffs))
+
+	_, err = handler.DiffPolicyManifests(context.Background(), manifests, policies[:1])
+	assert.EqualError(t, err, "got 2 groups of manifests for 1 policies")
+}
@@ -26,6 +26,7 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/coreos/go-semver/semver"
 	"github.com/go-logr/logr"
@@ -62,18 +63,21 @@ var unSupportedResources = map[string][]string{
 }
 
 type EManifestHandler interface {
-	ApplyExtraManifests(ctx context.Context, fromDir string) error
+	ApplyExtraManifests(ctx context.Context, fromDir string) (bool, error)
+	DiffPolicyManifests(ctx context.Context, sortedObjects [][]*unstructured.Unstructured, selectedPolicies []ibuv1.SelectedPolicy) ([]ManifestDiff, error)
 	ExportExtraManifestToDir(ctx context.Context, extraManifestCMs []ibuv1.ConfigMapRef, toDir string) error
 	ExtractAndExportManifestFromPoliciesToDir(ctx context.Context, policyLabels, objectLabels, validationAnns map[string]string, toDir string) error
-	ValidateAndExtractManifestFromPolicies(ctx context.Context, policyLabels, objectLabels, validationAnns map[string]string) ([][]*unstructured.Unstructured, error)
-	ValidateExtraManifestConfigmaps(ctx context.Context, extraManifestCMs []ibuv1.ConfigMapRef) (string, error)
+	ValidateAndExtractManifestFromPolicies(ctx context.Context, policyLabels, objectLabels, validationAnns map[string]string) ([][]*unstructured.Unstructured, []ibuv1.SelectedPolicy, error)
+	ValidateExtraManifestConfigmaps(ctx context.Context, extraManifestCMs []ibuv1.ConfigMapRef, fakeNamespaces bool) (string, []ManifestDiff, error)
 }
 
 // EMHandler handles the extra manifests
 type EMHandler struct {
-	Client        client.Client
-	DynamicClient dynamic.Interface
-	Log           logr.Logger
+	Client client.Client
+	// NoncachedClient resolves the policy templates, which may read any object, without starting an informer for it
+	NoncachedClient client.Reader
+	DynamicClient   dynamic.Interface
+	Log             logr.Logger
 }
 
 // EMStatusError type
@@ -108,33 +112,49 @@ func IsEMFailedError(err error) bool {
 	return false
 }
 
-func ApplyExtraManifest(ctx context.Context, dc dynamic.Interface, restMapper meta.RESTMapper, manifest *unstructured.Unstructured, isDryRun bool) error {
+// getResourceInterface returns the dynamic resource interface of the manifest. Namespaced manifests without namespace
+// are in the default namespace.
+func getResourceInterface(dc dynamic.Interface, restMapper meta.RESTMapper, manifest *unstructured.Unstructured) (dynamic.ResourceInterface, error) {
 	// Mapping resource GVK to CVR
 	mapping, err := restMapper.RESTMapping(manifest.GroupVersionKind().GroupKind(), manifest.GroupVersionKind().Version)
 	if err != nil {
-		return fmt.Errorf("failed to get RESTMapping: %w", err)
+		return nil, fmt.Errorf("failed to get RESTMapping: %w", err)
 	}
 
-	var resource dynamic.ResourceInterface
 	if mapping.Scope.Name() == meta.RESTScopeNameRoot {
 		// cluster-scoped resource
-		resource = dc.Resource(mapping.Resource)
-	} else {
-		// namespaced-scoped resource
-		manifestNs := manifest.GetNamespace()
-		if manifestNs == "" {
-			manifestNs = "default"
-		}
-		resource = dc.Resource(mapping.Resource).Namespace(manifestNs)
+		return dc.Resource(mapping.Resource), nil
+	}
+	// namespaced-scoped resource
+	manifestNs := manifest.GetNamespace()
+	if manifestNs == "" {
+		manifestNs = "default"
+	}
+	return dc.Resource(mapping.Resource).Namespace(manifestNs), nil
+}
+
+func ApplyExtraManifest(ctx context.Context, dc dynamic.Interface, restMapper meta.RESTMapper, manifest *unstructured.Unstructured, isDryRun bool) error {
+	resource, err := getResourceInterface(dc, restMapper, manifest)
+	if err != nil {
+		return err
+	}
+
+	if manifest.GetAnnotations()[common.ApplyTypeAnnotation] == common.ApplyTypeDelete {
+		return deleteExtraManifest(ctx, resource, manifest, isDryRun)
 	}
 
+	_, _, err = applyExtraManifest(ctx, resource, manifest, isDryRun)
+	return err
+}
+
+// applyExtraManifest creates or updates the object of the manifest, and returns the object before, nil if it did not
+// exist, and after being applied
+func applyExtraManifest(ctx context.Context, resource dynamic.ResourceInterface, manifest *unstructured.Unstructured, isDryRun bool) (*unstructured.Unstructured, *unstructured.Unstructured, error) {
 	// Check if the resource exists
-	existingManifest := &unstructured.Unstructured{}
-	existingManifest.SetGroupVersionKind(manifest.GroupVersionKind())
-	existingManifest, err = resource.Get(ctx, manifest.GetName(), metav1.GetOptions{})
+	existingManifest, err := resource.Get(ctx, manifest.GetName(), metav1.GetOptions{})
 	if err != nil {
 		if !k8serrors.IsNotFound(err) {
-			return fmt.Errorf("failed to get manifest %s called %s: %w", manifest.GetKind(), manifest.GetName(), err)
+			return nil, nil, fmt.Errorf("failed to get manifest %s called %s: %w", manifest.GetKind(), manifest.GetName(), err)
 		}
 
 		opts := metav1.CreateOptions{}
@@ -145,49 +165,65 @@ func ApplyExtraManifest(ctx context.Context, dc dynamic.Interface, restMapper me
 		}
 
 		manifest.SetResourceVersion("")
-		if _, err := resource.Create(ctx, manifest, opts); err != nil {
-			return fmt.Errorf("failed to create manifest %s called %s: %w", manifest.GetKind(), manifest.GetName(), err)
+		created, err := resource.Create(ctx, manifest, opts)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create manifest %s called %s: %w", manifest.GetKind(), manifest.GetName(), err)
 		}
-	} else {
-		applyType := common.ApplyTypeReplace
-		if metadata, exists := manifest.Object["metadata"].(map[string]interface{}); exists {
-			if annotations, exists := metadata["annotations"].(map[string]interface{}); exists {
-				if at, exists := annotations[common.ApplyTypeAnnotation]; exists {
-					applyType = at.(string)
-					// Remove the annotation as it serves no purpose at runtime
-					delete(annotations, common.ApplyTypeAnnotation)
-				}
+		return nil, created, nil
+	}
+
+	applyType := common.ApplyTypeReplace
+	if metadata, exists := manifest.Object["metadata"].(map[string]interface{}); exists {
+		if annotations, exists := metadata["annotations"].(map[string]interface{}); exists {
+			if at, exists := annotations[common.ApplyTypeAnnotation]; exists {
+				applyType = at.(string)
+				// Remove the annotation as it serves no purpose at runtime
+				delete(annotations, common.ApplyTypeAnnotation)
 			}
 		}
+	}
 
-		opts := metav1.UpdateOptions{}
-		if isDryRun {
-			opts = metav1.UpdateOptions{
-				DryRun: []string{metav1.DryRunAll},
-			}
+	opts := metav1.UpdateOptions{}
+	if isDryRun {
+		opts = metav1.UpdateOptions{
+			DryRun: []string{metav1.DryRunAll},
 		}
+	}
 
-		switch applyType {
-		case common.ApplyTypeReplace:
-			manifest.SetResourceVersion(existingManifest.GetResourceVersion())
-			if _, err := resource.Update(ctx, manifest, opts); err != nil {
-				return fmt.Errorf("failed to replace manifest %s called %s: %w", manifest.GetKind(), manifest.GetName(), err)
-			}
+	var updated *unstructured.Unstructured
+	switch applyType {
+	case common.ApplyTypeReplace:
+		manifest.SetResourceVersion(existingManifest.GetResourceVersion())
+		if updated, err = resource.Update(ctx, manifest, opts); err != nil {
+			return nil, nil, fmt.Errorf("failed to replace manifest %s called %s: %w", manifest.GetKind(), manifest.GetName(), err)
+		}
 
-		case common.ApplyTypeMerge:
-			mergedObj, err := mergeSpecs(manifest.Object, existingManifest.Object, string(policyv1.MustHave), false)
-			if err != nil {
-				return fmt.Errorf("failed to merge manifest %s called %s: %w", manifest.GetKind(), manifest.GetName(), err)
-			}
+	case common.ApplyTypeMerge:
+		mergedObj, err := mergeSpecs(manifest.Object, existingManifest.DeepCopy().Object, string(policyv1.MustHave), false)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to merge manifest %s called %s: %w", manifest.GetKind(), manifest.GetName(), err)
+		}
 
-			merged := &unstructured.Unstructured{Object: mergedObj.(map[string]interface{})}
-			merged.SetResourceVersion(existingManifest.GetResourceVersion())
-			if _, err := resource.Update(ctx, merged, opts); err != nil {
-				return fmt.Errorf("failed to update manifest %s called %s: %w", manifest.GetKind(), manifest.GetName(), err)
-			}
+		merged := &unstructured.Unstructured{Object: mergedObj.(map[string]interface{})}
+		merged.SetResourceVersion(existingManifest.GetResourceVersion())
+		if updated, err = resource.Update(ctx, merged, opts); err != nil {
+			return nil, nil, fmt.Errorf("failed to update manifest %s called %s: %w", manifest.GetKind(), manifest.GetName(), err)
+		}
+	}
+	return existingManifest, updated, nil
+}
 
+// deleteExtraManifest deletes the object of the manifest, if it exists
+func deleteExtraManifest(ctx context.Context, resource dynamic.ResourceInterface, manifest *unstructured.Unstructured, isDryRun bool) error {
+	opts := metav1.DeleteOptions{}
+	if isDryRun {
+		opts = metav1.DeleteOptions{
+			DryRun: []string{metav1.DryRunAll},
 		}
+	}
 
+	if err := resource.Delete(ctx, manifest.GetName(), opts); err != nil && !k8serrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete manifest %s called %s: %w", manifest.GetKind(), manifest.GetName(), err)
 	}
 	return nil
 }
@@ -227,30 +263,42 @@ func deleteFakedNamespacesForDryrun(ctx context.Context, c client.Client, log lo
 // Errors such as Invalid or webhook BadRequest types from Dry-run apply are considered warnings. This also includes
 // cases where CRDs are missing from the current stateroot and dependent namespace does not exist on the current
 // stateroot but is also not found in the configmaps. Other validation failures are treated as errors, such as random
-// chars, missing resource Kind, resource ApiVersion, resource name or the presence of disallowed resource types
-func (h *EMHandler) ValidateExtraManifestConfigmaps(ctx context.Context, content []ibuv1.ConfigMapRef) (string, error) {
+// chars, missing resource Kind, resource ApiVersion, resource name or the presence of disallowed resource types.
+// It also returns the changes the manifests make to the cluster, computed from the Dry-run results.
+// If fakeNamespaces is set, the namespaces created by earlier manifests are created for the Dry-run and deleted
+// afterward, otherwise the manifests in those namespaces are reported as Unknown without Dry-run.
+func (h *EMHandler) ValidateExtraManifestConfigmaps(ctx context.Context, content []ibuv1.ConfigMapRef, fakeNamespaces bool) (string, []ManifestDiff, error) {
 	var errs []string         // validation errors
 	var warnings []string     // validation warnings
 	var validationErr error   // returned validation err
 	var validationWarn string // returned warning string
+	var diffs []ManifestDiff  // returned manifest diffs
 
 	configmaps, err := common.GetConfigMaps(ctx, h.Client, content)
 	if err != nil {
 		if k8serrors.IsNotFound(err) {
 			errMsg := fmt.Sprintf("the extraManifests configMap is not found, error: %v. Please create the configMap.", err)
-			return validationWarn, NewEMFailedValidationError(errMsg)
+			return validationWarn, nil, NewEMFailedValidationError(errMsg)
 		}
-		return validationWarn, fmt.Errorf("failed to get configMaps to export extraManifest to dir: %w", err)
+		return validationWarn, nil, fmt.Errorf("failed to get configMaps to export extraManifest to dir: %w", err)
 	}
 
-	manifests, extractErrs := h.extractManifestFromConfigmaps(configmaps)
-	if len(extractErrs) != 0 {
-		errs = append(errs, extractErrs...)
+	var manifests []*unstructured.Unstructured
+	sources := make(map[*unstructured.Unstructured]string) // the configmap of each manifest
+	for _, cm := range configmaps {
+		cmManifests, extractErrs := h.extractManifestFromConfigmaps([]corev1.ConfigMap{cm})
+		if len(extractErrs) != 0 {
+			errs = append(errs, extractErrs...)
+		}
+		for _, manifest := range cmManifests {
+			sources[manifest] = fmt.Sprintf("ConfigMap %s/%s", cm.Namespace, cm.Name)
+		}
+		manifests = append(manifests, cmManifests...)
 	}
 
 	sortedManifests, err := common.SortAndGroupByApplyWave[*unstructured.Unstructured](manifests)
 	if err != nil {
-		return validationWarn, fmt.Errorf("failed to sort and group manifests: %w", err)
+		return validationWarn, nil, fmt.Errorf("failed to sort and group manifests: %w", err)
 	}
 
 	// A namespace set saves all created namespaces for dryrun
@@ -283,6 +331,12 @@ func (h *EMHandler) ValidateExtraManifestConfigmaps(ctx context.Context, content
 			// Add to namespace set when manifest is Namespace
 			if mKind == "Namespace" {
 				nsManifestsSet[mName] = true
+				diff, err := dryRunDiff(ctx, h.DynamicClient, h.Client.RESTMapper(), sources[manifest], manifest.DeepCopy())
+				if err != nil {
+					diff.Operation = DiffUnknown
+					diff.Message = err.Error()
+				}
+				diffs = append(diffs, diff)
 				continue
 			}
 
@@ -291,19 +345,32 @@ func (h *EMHandler) ValidateExtraManifestConfigmaps(ctx context.Context, content
 				ns := &corev1.Namespace{}
 				if err := h.Client.Get(ctx, types.NamespacedName{Name: mNamespace}, ns); err != nil {
 					if !k8serrors.IsNotFound(err) {
-						return validationWarn, fmt.Errorf("failed to query namespace %s: %w", ns, err)
+						return validationWarn, nil, fmt.Errorf("failed to query namespace %s: %w", ns, err)
 					}
 
 					// Manifest's namespace not found on cluster and not in configmap
 					if _, exists := nsManifestsSet[mNamespace]; !exists {
-						warnings = append(warnings, fmt.Sprintf("%s[%s] - namespace %s not found on cluster or not added in configmap in right order", mKind, mName, mNamespace))
+						warning := fmt.Sprintf("%s[%s] - namespace %s not found on cluster or not added in configmap in right order", mKind, mName, mNamespace)
+						warnings = append(warnings, warning)
+						diff := newManifestDiff(sources[manifest], manifest)
+						diff.Operation = DiffUnknown
+						diff.Message = warning
+						diffs = append(diffs, diff)
 						continue
 					}
 
-					// Manifest's namespace not found on cluster but in configmap, fake the namespace
+					// Manifest's namespace not found on cluster but in configmap, the dryrun cannot be done without
+					// faking the namespace
+					if !fakeNamespaces {
+						diff := newManifestDiff(sources[manifest], manifest)
+						diff.Operation = DiffUnknown
+						diff.Message = fmt.Sprintf("namespace %s is created by the extra manifests, dryrun skipped", mNamespace)
+						diffs = append(diffs, diff)
+						continue
+					}
 					faked, err := fakeNamespaceForDryrun(ctx, h.Client, h.Log, mNamespace)
 					if err != nil {
-						return validationWarn, fmt.Errorf("failed to create namespace %s for dryrun: %w", ns, err)
+						return validationWarn, nil, fmt.Errorf("failed to create namespace %s for dryrun: %w", ns, err)
 					} else if faked {
 						fakedNamespacesForDryrun[mNamespace] = true
 					}
@@ -311,24 +378,30 @@ func (h *EMHandler) ValidateExtraManifestConfigmaps(ctx context.Context, content
 			}
 
 			// Apply manifest with dryRun
-			if err := ApplyExtraManifest(ctx, h.DynamicClient, h.Client.RESTMapper(), manifest, true); err != nil {
+			diff, err := dryRunDiff(ctx, h.DynamicClient, h.Client.RESTMapper(), sources[manifest], manifest)
+			if err != nil {
 				var groupDiscoveryErr *discovery.ErrGroupDiscoveryFailed
+				var warning string
 				switch {
 				case errors.As(err, &groupDiscoveryErr), meta.IsNoMatchError(err): // Capture unknown CRD
-					warnings = append(warnings, fmt.Sprintf("%s[%s] - CRD not deployed on cluster", mKind, mName))
+					warning = fmt.Sprintf("%s[%s] - CRD not deployed on cluster", mKind, mName)
 				case k8serrors.IsInvalid(err), k8serrors.IsBadRequest(err): // Capture both invalid syntax and webhook validation errors
-					warnings = append(warnings, fmt.Sprintf("%s[%s] - dryrun failed in namespace %s: %s", mKind, mName, mNamespace, err.Error()))
+					warning = fmt.Sprintf("%s[%s] - dryrun failed in namespace %s: %s", mKind, mName, mNamespace, err.Error())
 				default: // Capture unknown runtime error
 					// nolint: staticcheck
-					return validationWarn, fmt.Errorf("failed to validate manifest with dryrun: %w", err)
+					return validationWarn, nil, fmt.Errorf("failed to validate manifest with dryrun: %w", err)
 				}
+				warnings = append(warnings, warning)
+				diff.Operation = DiffUnknown
+				diff.Message = warning
 			}
+			diffs = append(diffs, diff)
 		}
 	}
 
 	if len(warnings) == 0 && len(errs) == 0 {
 		h.Log.Info("ExtraManifests configmaps are validated", "configmaps", content)
-		return "", nil
+		return "", diffs, nil
 	}
 
 	if len(warnings) > 0 {
@@ -341,7 +414,7 @@ func (h *EMHandler) ValidateExtraManifestConfigmaps(ctx context.Context, content
 		h.Log.Error(validationErr, "[ERROR] ExtraManifests configmaps validation")
 	}
 
-	return validationWarn, validationErr
+	return validationWarn, diffs, validationErr
 }
 
 func (h *EMHandler) extractManifestFromConfigmaps(configmaps []corev1.ConfigMap) ([]*unstructured.Unstructured, []string) {
@@ -429,17 +502,7 @@ func (h *EMHandler) ExportExtraManifestToDir(ctx context.Context, extraManifestC
 
 // ExtractAndExportManifestFromPoliciesToDir extracts CR specs from policies and writes to a given directory. It matches policies and/or CRs by labels.
 func (h *EMHandler) ExtractAndExportManifestFromPoliciesToDir(ctx context.Context, policyLabels, objectLabels, validationAnns map[string]string, toDir string) error {
-	crd := &apiextensionsv1.CustomResourceDefinition{}
-	if err := h.Client.Get(ctx, types.NamespacedName{Name: "policies.policy.open-cluster-management.io"}, crd); err != nil {
-		if k8serrors.IsNotFound(err) {
-			h.Log.Info("Skipping extraction from policies as the policy CRD is not found. This is expected if the cluster is not managed by ACM")
-			return nil
-		} else {
-			return fmt.Errorf("error while check policy CRD: %w", err)
-		}
-	}
-
-	sortedObjects, err := h.ValidateAndExtractManifestFromPolicies(ctx, policyLabels, objectLabels, validationAnns)
+	sortedObjects, _, err := h.ValidateAndExtractManifestFromPolicies(ctx, policyLabels, objectLabels, validationAnns)
 	if err != nil {
 		return fmt.Errorf("failed to validate and extract manifests from policies: %w", err)
 	}
@@ -464,24 +527,61 @@ func (h *EMHandler) ExtractAndExportManifestFromPoliciesToDir(ctx context.Contex
 	return nil
 }
 
-func (h *EMHandler) ValidateAndExtractManifestFromPolicies(ctx context.Context, policyLabels, objectLabels, validationAnns map[string]string) ([][]*unstructured.Unstructured, error) {
+// ValidateAndExtractManifestFromPolicies extracts CR specs from policies, grouped by policy in the ztp wave order. The
+// managed cluster templates of the CRs are resolved against the cluster, and the CRs of mustnothave templates are
+// deleted with dryrun to validate them. It also returns the policies the CRs are extracted from.
+func (h *EMHandler) ValidateAndExtractManifestFromPolicies(ctx context.Context, policyLabels, objectLabels, validationAnns map[string]string) ([][]*unstructured.Unstructured, []ibuv1.SelectedPolicy, error) {
 	var manifestCount int
 	var sortedObjects = [][]*unstructured.Unstructured{}
+	var selectedPolicies []ibuv1.SelectedPolicy
+
+	crd := &apiextensionsv1.CustomResourceDefinition{}
+	if err := h.Client.Get(ctx, types.NamespacedName{Name: "policies.policy.open-cluster-management.io"}, crd); err != nil {
+		if k8serrors.IsNotFound(err) {
+			h.Log.Info("Skipping extraction from policies as the policy CRD is not found. This is expected if the cluster is not managed by ACM")
+			return sortedObjects, selectedPolicies, nil
+		} else {
+			return sortedObjects, selectedPolicies, fmt.Errorf("error while check policy CRD: %w", err)
+		}
+	}
 
 	sortedPolicies, err := h.GetPolicies(ctx, policyLabels)
 	if err != nil {
-		return sortedObjects, fmt.Errorf("failed to get policies: %w", err)
+		return sortedObjects, selectedPolicies, fmt.Errorf("failed to get policies: %w", err)
 	}
 
 	for _, policy := range sortedPolicies {
-		objects, err := getConfigurationObjects(&h.Log, policy, objectLabels)
+		objects, selectedBy, err := getConfigurationObjects(&h.Log, policy, objectLabels)
 		if err != nil {
-			return sortedObjects, fmt.Errorf("failed to extract manifests from policies: %w", err)
+			return sortedObjects, selectedPolicies, fmt.Errorf("failed to extract manifests from policies: %w", err)
+		}
+
+		for _, object := range objects {
+			if err := resolveTemplates(ctx, h.NoncachedClient, object); err != nil {
+				errMsg := fmt.Sprintf("failed to resolve templates of %s[%s] in namespace %s from policy %s: %s",
+					object.GetKind(), object.GetName(), object.GetNamespace(), policy.Name, err.Error())
+				h.Log.Error(nil, errMsg)
+				return sortedObjects, selectedPolicies, NewEMFailedError(errMsg)
+			}
+			// The objects of mustnothave templates are deleted by name
+			if isDeletion(object) && object.GetName() == "" {
+				errMsg := fmt.Sprintf("mustnothave template of %s in namespace %s from policy %s has no name, which is not supported",
+					object.GetKind(), object.GetNamespace(), policy.Name)
+				h.Log.Error(nil, errMsg)
+				return sortedObjects, selectedPolicies, NewEMFailedError(errMsg)
+			}
 		}
 
 		if len(objects) > 0 {
-			manifestCount += len(objects)
+			// The deletions are not counted, as they are not labeled manifests to apply
+			manifestCount += lo.CountBy(objects, func(object *unstructured.Unstructured) bool { return !isDeletion(object) })
 			sortedObjects = append(sortedObjects, objects)
+			selectedPolicies = append(selectedPolicies, ibuv1.SelectedPolicy{
+				Name:              policy.Name,
+				Namespace:         policy.Namespace,
+				Manifests:         len(objects),
+				TargetOcpVersions: selectedBy,
+			})
 		}
 	}
 
@@ -490,54 +590,137 @@ func (h *EMHandler) ValidateAndExtractManifestFromPolicies(ctx context.Context,
 		if expectedManifestCount != strconv.Itoa(manifestCount) {
 			errMsg := fmt.Sprintf("The labeled manifests count found in policies %s does not match the expected manifests count %s", strconv.Itoa(manifestCount), expectedManifestCount)
 			h.Log.Error(nil, errMsg)
-			return sortedObjects, NewEMFailedError(errMsg)
+			return sortedObjects, selectedPolicies, NewEMFailedError(errMsg)
 		}
 	}
-	return sortedObjects, nil
+
+	if err := h.validateDeletions(ctx, sortedObjects); err != nil {
+		return sortedObjects, selectedPolicies, err
+	}
+	return sortedObjects, selectedPolicies, nil
+}
+
+// isDeletion checks whether the object is annotated to be deleted, as extracted from a mustnothave template
+func isDeletion(object *unstructured.Unstructured) bool {
+	return object.GetAnnotations()[common.ApplyTypeAnnotation] == common.ApplyTypeDelete
 }
 
-// ApplyExtraManifests applies the extra manifests from the preserved extra manifests directory
-func (h *EMHandler) ApplyExtraManifests(ctx context.Context, fromDir string) error {
+// validateDeletions deletes with dryrun the CRs annotated to be deleted. CRs whose CRD is not deployed on the cluster
+// are skipped, as there is nothing to delete.
+func (h *EMHandler) validateDeletions(ctx context.Context, sortedObjects [][]*unstructured.Unstructured) error {
+	for _, objects := range sortedObjects {
+		for _, object := range objects {
+			if !isDeletion(object) {
+				continue
+			}
+
+			if err := ApplyExtraManifest(ctx, h.DynamicClient, h.Client.RESTMapper(), object.DeepCopy(), true); err != nil {
+				var groupDiscoveryErr *discovery.ErrGroupDiscoveryFailed
+				switch {
+				case errors.As(err, &groupDiscoveryErr), meta.IsNoMatchError(err):
+					h.Log.Info(fmt.Sprintf("%s[%s] - CRD not deployed on cluster, nothing to delete", object.GetKind(), object.GetName()))
+				case k8serrors.IsInvalid(err), k8serrors.IsBadRequest(err), k8serrors.IsForbidden(err):
+					return NewEMFailedError(fmt.Sprintf("%s[%s] - dryrun deletion failed in namespace %s: %s",
+						object.GetKind(), object.GetName(), object.GetNamespace(), err.Error()))
+				default:
+					return fmt.Errorf("failed to validate deletion with dryrun: %w", err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// ApplyExtraManifests applies the extra manifests from the preserved extra manifests directory, one wave at a time.
+// A wave must be ready before the next one is applied, and its readiness is tracked across reconciles: the applied
+// wave is recorded next to the directory, and false is returned while it is not ready, until its timeout expires.
+// The directory is removed once all the waves are ready.
+func (h *EMHandler) ApplyExtraManifests(ctx context.Context, fromDir string) (bool, error) {
 	manifests, err := utils.LoadGroupedManifestsFromPath(fromDir, &h.Log)
 	if err != nil {
-		return fmt.Errorf("failed to read extra manifests from path: %w", err)
+		return false, fmt.Errorf("failed to read extra manifests from path: %w", err)
 	}
 	if len(manifests) == 0 {
 		h.Log.Info(fmt.Sprintf("No extra manifests to apply in path %s", fromDir))
-		return nil
+		return true, nil
 	}
 
-	for _, group := range manifests {
-		for _, manifest := range group {
-			h.Log.Info("Applying manifest", "kind", manifest.GetKind(), "name", manifest.GetName())
-			err = ApplyExtraManifest(ctx, h.DynamicClient, h.Client.RESTMapper(), manifest, false)
-			if err != nil {
-				h.Log.Error(err, "Failed to apply manifest")
-				errMsg := fmt.Sprintf("failed to apply manifest: %v", err.Error())
+	state, err := readWaveState(fromDir)
+	if err != nil {
+		return false, err
+	}
 
-				// Capture both invalid syntax and webhook validation errors
-				if k8serrors.IsInvalid(err) || k8serrors.IsBadRequest(err) {
-					return NewEMFailedError(errMsg)
-				}
+	for wave := state.Wave; wave < len(manifests); wave++ {
+		group := manifests[wave]
+		timeout, err := getApplyWaveTimeout(group)
+		if err != nil {
+			return false, NewEMFailedError(err.Error())
+		}
 
-				// Capture unknown CRD issue
-				var groupDiscoveryErr *discovery.ErrGroupDiscoveryFailed
-				if errors.As(err, &groupDiscoveryErr) || meta.IsNoMatchError(err) {
-					return NewEMFailedError(errMsg)
-				}
-				// nolint: staticcheck
-				return fmt.Errorf("failed to apply manifest: %w", err)
+		if wave != state.Wave || state.AppliedAt.IsZero() {
+			if err := h.applyWave(ctx, group); err != nil {
+				return false, err
+			}
+			state = waveState{Wave: wave, AppliedAt: time.Now()}
+			if err := utils.MarshalToFile(state, waveStatePath(fromDir)); err != nil {
+				return false, fmt.Errorf("failed to record the wave state of %s: %w", fromDir, err)
 			}
+		}
+		if timeout == 0 {
+			continue
+		}
 
-			h.Log.Info("Applied manifest", "name", manifest.GetName(), "namespace", manifest.GetNamespace())
+		notReady, err := getNotReadyManifests(ctx, h.DynamicClient, h.Client.RESTMapper(), group)
+		if err != nil {
+			return false, fmt.Errorf("failed to check whether manifests are ready: %w", err)
+		}
+		if len(notReady) > 0 {
+			if time.Since(state.AppliedAt) >= timeout {
+				err := newNotReadyError(timeout, notReady)
+				h.Log.Error(err, "Manifests of the wave are not ready in time", "wave", wave)
+				return false, err
+			}
+			h.Log.Info("Waiting for the manifests of the wave to be ready", "wave", wave, "notReady", notReady)
+			return false, nil
 		}
 	}
 
 	// Remove the extra manifests directory
 	if err := os.RemoveAll(fromDir); err != nil {
-		return fmt.Errorf("failed to remove manifests from %s: %w", fromDir, err)
+		return false, fmt.Errorf("failed to remove manifests from %s: %w", fromDir, err)
+	}
+	if err := os.Remove(waveStatePath(fromDir)); err != nil && !os.IsNotExist(err) {
+		return false, fmt.Errorf("failed to remove the wave state of %s: %w", fromDir, err)
 	}
 	h.Log.Info("Extra manifests path removed", "path", fromDir)
+	return true, nil
+}
+
+// applyWave applies the manifests of a wave
+func (h *EMHandler) applyWave(ctx context.Context, manifests []*unstructured.Unstructured) error {
+	for _, manifest := range manifests {
+		h.Log.Info("Applying manifest", "kind", manifest.GetKind(), "name", manifest.GetName())
+		err := ApplyExtraManifest(ctx, h.DynamicClient, h.Client.RESTMapper(), manifest, false)
+		if err != nil {
+			h.Log.Error(err, "Failed to apply manifest")
+			errMsg := fmt.Sprintf("failed to apply manifest: %v", err.Error())
+
+			// Capture both invalid syntax and webhook validation errors
+			if k8serrors.IsInvalid(err) || k8serrors.IsBadRequest(err) {
+				return NewEMFailedError(errMsg)
+			}
+
+			// Capture unknown CRD issue
+			var groupDiscoveryErr *discovery.ErrGroupDiscoveryFailed
+			if errors.As(err, &groupDiscoveryErr) || meta.IsNoMatchError(err) {
+				return NewEMFailedError(errMsg)
+			}
+			// nolint: staticcheck
+			return fmt.Errorf("failed to apply manifest: %w", err)
+		}
+
+		h.Log.Info("Applied manifest", "name", manifest.GetName(), "namespace", manifest.GetNamespace())
+	}
 	return nil
 }
 
@@ -27,10 +27,12 @@ import (
 	ibuv1 "github.com/openshift-kni/lifecycle-agent/api/imagebasedupgrade/v1"
 	"github.com/openshift-kni/lifecycle-agent/internal/common"
 	"github.com/openshift-kni/lifecycle-agent/utils"
+	"github.com/samber/lo"
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -39,6 +41,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/yaml"
 	dynamicfake "k8s.io/client-go/dynamic/fake"
 	"k8s.io/client-go/kubernetes/scheme"
+	k8stesting "k8s.io/client-go/testing"
 	policiesv1 "open-cluster-management.io/governance-policy-propagator/api/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -303,10 +306,12 @@ func TestValidateExtraManifestConfigmaps(t *testing.T) {
 		}}
 
 	testcases := []struct {
-		name         string
-		configmaps   []ibuv1.ConfigMapRef
-		expectedErr  error
-		expectedWarn string
+		name           string
+		configmaps     []ibuv1.ConfigMapRef
+		fakeNamespaces bool
+		expectedErr    error
+		expectedWarn   string
+		expectedDiffs  []string
 	}{
 		{
 			name: "configmap is not found",
@@ -345,14 +350,40 @@ func TestValidateExtraManifestConfigmaps(t *testing.T) {
 			expectedWarn: "SriovNetworkNodePolicy[sriov-nnp-mh] - CRD not deployed on cluster; " +
 				"SriovNetworkNodePolicy[sriov-nnp-fh] - CRD not deployed on cluster; " +
 				"SriovNetwork[sriov-nw-fh] - namespace openshift-sriov-network-operator-test not found on cluster or not added in configmap in right order",
+
+			expectedDiffs: []string{
+				"SriovNetwork[sriov-nw-mh] Created",
+				"SriovNetworkNodePolicy[sriov-nnp-mh] Unknown",
+				"SriovNetworkNodePolicy[sriov-nnp-fh] Unknown",
+				"SriovNetwork[sriov-nw-fh] Unknown",
+			},
 		},
 		{
 			name: "validation pass with ns added in configmap",
 			configmaps: []ibuv1.ConfigMapRef{
 				{Name: "extra-manifest-cm-happy", Namespace: "default"},
 			},
+			fakeNamespaces: true,
+			expectedErr:    nil,
+			expectedWarn:   "",
+			expectedDiffs: []string{
+				"Namespace[openshift-sriov-network-operator-test] Unknown", // not in the RESTMapper
+				"SriovNetwork[sriov-nw-mh] Created",
+				"SriovNetwork[sriov-nw-fh] Created",
+			},
+		},
+		{
+			name: "validation pass with ns added in configmap, without faking it",
+			configmaps: []ibuv1.ConfigMapRef{
+				{Name: "extra-manifest-cm-happy", Namespace: "default"},
+			},
 			expectedErr:  nil,
 			expectedWarn: "",
+			expectedDiffs: []string{
+				"Namespace[openshift-sriov-network-operator-test] Unknown", // not in the RESTMapper
+				"SriovNetwork[sriov-nw-mh] Created",
+				"SriovNetwork[sriov-nw-fh] Unknown", // its namespace is not created for the dryrun
+			},
 		},
 	}
 
@@ -373,7 +404,7 @@ func TestValidateExtraManifestConfigmaps(t *testing.T) {
 				Log:           ctrl.Log.WithName("ExtraManifest"),
 			}
 
-			warn, err := handler.ValidateExtraManifestConfigmaps(context.Background(), tc.configmaps)
+			warn, diffs, err := handler.ValidateExtraManifestConfigmaps(context.Background(), tc.configmaps, tc.fakeNamespaces)
 			if err != nil && tc.expectedErr == nil {
 				t.Errorf("Unexpected error: %v", err)
 			} else if err == nil && tc.expectedErr != nil {
@@ -382,6 +413,15 @@ func TestValidateExtraManifestConfigmaps(t *testing.T) {
 				assert.ErrorContains(t, err, tc.expectedErr.Error())
 			}
 			assert.Equal(t, tc.expectedWarn, warn)
+			if tc.expectedDiffs != nil {
+				assert.ElementsMatch(t, tc.expectedDiffs, lo.Map(diffs, func(d ManifestDiff, _ int) string {
+					return fmt.Sprintf("%s[%s] %s", d.Kind, d.Name, d.Operation)
+				}))
+			}
+
+			// The namespaces faked for the dryrun are deleted afterward
+			assert.True(t, k8serrors.IsNotFound(fakeClient.Get(context.Background(),
+				client.ObjectKey{Name: "openshift-sriov-network-operator-test"}, &corev1.Namespace{})))
 		})
 	}
 }
@@ -594,6 +634,78 @@ spec:
   remediationAction: inform
 `
 
+const policyWithMustNotHaveObjects = `---
+kind: Policy
+apiVersion: policy.open-cluster-management.io/v1
+metadata:
+  name: ztp-common.p5
+  namespace: spoke
+  annotations:
+    ran.openshift.io/ztp-deploy-wave: "1"
+spec:
+  disabled: false
+  policy-templates:
+    - objectDefinition:
+        apiVersion: policy.open-cluster-management.io/v1
+        kind: ConfigurationPolicy
+        metadata:
+          name: common-cnfdf22-remove-config-policy-config
+        spec:
+          object-templates:
+            - complianceType: mustnothave
+              objectDefinition:
+                apiVersion: operators.coreos.com/v1alpha1
+                kind: CatalogSource
+                metadata:
+                  name: certified-operators
+                  namespace: openshift-marketplace
+                  labels:
+                    lca.openshift.io/target-ocp-version: "4.15"
+            - complianceType: musthave
+              objectDefinition:
+                apiVersion: operators.coreos.com/v1alpha1
+                kind: CatalogSource
+                metadata:
+                  name: redhat-operators
+                  namespace: openshift-marketplace
+                  labels:
+                    lca.openshift.io/target-ocp-version: "4.15"
+          remediationAction: inform
+          severity: low
+  remediationAction: inform
+`
+
+const policyWithUnnamedMustNotHaveObject = `---
+kind: Policy
+apiVersion: policy.open-cluster-management.io/v1
+metadata:
+  name: ztp-common.p6
+  namespace: spoke
+  annotations:
+    ran.openshift.io/ztp-deploy-wave: "1"
+spec:
+  disabled: false
+  policy-templates:
+    - objectDefinition:
+        apiVersion: policy.open-cluster-management.io/v1
+        kind: ConfigurationPolicy
+        metadata:
+          name: common-cnfdf22-remove-config-policy-config
+        spec:
+          object-templates:
+            - complianceType: mustnothave
+              objectDefinition:
+                apiVersion: operators.coreos.com/v1alpha1
+                kind: CatalogSource
+                metadata:
+                  namespace: openshift-marketplace
+                  labels:
+                    lca.openshift.io/target-ocp-version: "4.15"
+          remediationAction: inform
+          severity: low
+  remediationAction: inform
+`
+
 func TestExportPolicyManifests(t *testing.T) {
 
 	testcases := []struct {
@@ -696,6 +808,59 @@ func TestExportPolicyManifests(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:           "mustnothave objects are exported to be deleted",
+			policies:       []*unstructured.Unstructured{mustConvertYamlStrToUnstructured(policyWithMustNotHaveObjects)},
+			objectLabels:   map[string]string{TargetOcpVersionLabel: "4.15.2,4.15"},
+			validationAnns: map[string]string{TargetOcpVersionManifestCountAnnotation: "1"},
+			expectedFilePaths: []string{
+				filepath.Join(PolicyManifestPath, "group1", "1_CatalogSource_certified-operators_openshift-marketplace.yaml"),
+				filepath.Join(PolicyManifestPath, "group1", "2_CatalogSource_redhat-operators_openshift-marketplace.yaml"),
+			},
+			expectedObjects: []unstructured.Unstructured{
+				{
+					Object: map[string]interface{}{
+						"apiVersion": "operators.coreos.com/v1alpha1",
+						"kind":       "CatalogSource",
+						"metadata": map[string]interface{}{
+							"annotations": map[string]interface{}{
+								common.ApplyTypeAnnotation: common.ApplyTypeDelete,
+							},
+							"labels": map[string]interface{}{
+								"lca.openshift.io/target-ocp-version": "4.15",
+							},
+							"name":      "certified-operators",
+							"namespace": "openshift-marketplace",
+						},
+						"status": map[string]interface{}{},
+					},
+				},
+				{
+					Object: map[string]interface{}{
+						"apiVersion": "operators.coreos.com/v1alpha1",
+						"kind":       "CatalogSource",
+						"metadata": map[string]interface{}{
+							"annotations": map[string]interface{}{
+								common.ApplyTypeAnnotation: common.ApplyTypeMerge,
+							},
+							"labels": map[string]interface{}{
+								"lca.openshift.io/target-ocp-version": "4.15",
+							},
+							"name":      "redhat-operators",
+							"namespace": "openshift-marketplace",
+						},
+						"status": map[string]interface{}{},
+					},
+				},
+			},
+		},
+		{
+			name:              "mustnothave template without name fails the validation",
+			policies:          []*unstructured.Unstructured{mustConvertYamlStrToUnstructured(policyWithUnnamedMustNotHaveObject)},
+			objectLabels:      map[string]string{TargetOcpVersionLabel: "4.15"},
+			expectedFilePaths: []string{},
+			expectedError:     "mustnothave template of CatalogSource in namespace openshift-marketplace from policy ztp-common.p6 has no name",
+		},
 		{
 			name:              "manifests count does not match with expected",
 			policies:          []*unstructured.Unstructured{mustConvertYamlStrToUnstructured(policyWithAnnotationAndObjectWithLabel)},
@@ -736,8 +901,9 @@ func TestExportPolicyManifests(t *testing.T) {
 			}
 
 			handler := &EMHandler{
-				Client: fakeClient,
-				Log:    ctrl.Log.WithName("ExtraManifest"),
+				Client:          fakeClient,
+				NoncachedClient: fakeClient,
+				Log:             ctrl.Log.WithName("ExtraManifest"),
 			}
 
 			// Export the manifests to the temporary directory
@@ -824,3 +990,39 @@ func TestGetMatchingVersion(t *testing.T) {
 		}
 	})
 }
+
+func TestApplyExtraManifestDeletion(t *testing.T) {
+	catalogSourceGVR := schema.GroupVersionResource{Group: "operators.coreos.com", Version: "v1alpha1", Resource: "catalogsources"}
+	catalogSourceGVK := schema.GroupVersionKind{Group: "operators.coreos.com", Version: "v1alpha1", Kind: "CatalogSource"}
+	mapper := meta.NewDefaultRESTMapper([]schema.GroupVersion{catalogSourceGVK.GroupVersion()})
+	mapper.Add(catalogSourceGVK, meta.RESTScopeNamespace)
+
+	existing := &unstructured.Unstructured{}
+	existing.SetGroupVersionKind(catalogSourceGVK)
+	existing.SetName("certified-operators")
+	existing.SetNamespace("openshift-marketplace")
+	dynamicFakeClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{catalogSourceGVR: "CatalogSourceList"}, existing)
+
+	manifest := existing.DeepCopy()
+	manifest.SetAnnotations(map[string]string{common.ApplyTypeAnnotation: common.ApplyTypeDelete})
+	assert.NoError(t, ApplyExtraManifest(context.Background(), dynamicFakeClient, mapper, manifest.DeepCopy(), false))
+	_, err := dynamicFakeClient.Resource(catalogSourceGVR).Namespace("openshift-marketplace").Get(context.Background(), "certified-operators", metav1.GetOptions{})
+	assert.True(t, k8serrors.IsNotFound(err))
+
+	// Deleting an object that does not exist is a no-op
+	assert.NoError(t, ApplyExtraManifest(context.Background(), dynamicFakeClient, mapper, manifest.DeepCopy(), false))
+
+	// A deletion rejected by the dryrun fails the validation
+	dynamicFakeClient.PrependReactor("delete", "catalogsources", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, k8serrors.NewForbidden(catalogSourceGVR.GroupResource(), "certified-operators", fmt.Errorf("denied by webhook"))
+	})
+	handler := &EMHandler{
+		Client:        fake.NewClientBuilder().WithScheme(testscheme).WithRESTMapper(mapper).Build(),
+		DynamicClient: dynamicFakeClient,
+		Log:           ctrl.Log.WithName("ExtraManifest"),
+	}
+	err = handler.validateDeletions(context.Background(), [][]*unstructured.Unstructured{{manifest}})
+	assert.True(t, IsEMFailedError(err))
+	assert.ErrorContains(t, err, "denied by webhook")
+}
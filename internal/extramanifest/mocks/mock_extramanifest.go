@@ -13,6 +13,7 @@ import (
 	reflect "reflect"
 
 	v1 "github.com/openshift-kni/lifecycle-agent/api/imagebasedupgrade/v1"
+	extramanifest "github.com/openshift-kni/lifecycle-agent/internal/extramanifest"
 	gomock "go.uber.org/mock/gomock"
 	unstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
@@ -43,11 +44,12 @@ func (m *MockEManifestHandler) EXPECT() *MockEManifestHandlerMockRecorder {
 }
 
 // ApplyExtraManifests mocks base method.
-func (m *MockEManifestHandler) ApplyExtraManifests(ctx context.Context, fromDir string) error {
+func (m *MockEManifestHandler) ApplyExtraManifests(ctx context.Context, fromDir string) (bool, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "ApplyExtraManifests", ctx, fromDir)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // ApplyExtraManifests indicates an expected call of ApplyExtraManifests.
@@ -56,6 +58,21 @@ func (mr *MockEManifestHandlerMockRecorder) ApplyExtraManifests(ctx, fromDir any
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplyExtraManifests", reflect.TypeOf((*MockEManifestHandler)(nil).ApplyExtraManifests), ctx, fromDir)
 }
 
+// DiffPolicyManifests mocks base method.
+func (m *MockEManifestHandler) DiffPolicyManifests(ctx context.Context, sortedObjects [][]*unstructured.Unstructured, selectedPolicies []v1.SelectedPolicy) ([]extramanifest.ManifestDiff, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DiffPolicyManifests", ctx, sortedObjects, selectedPolicies)
+	ret0, _ := ret[0].([]extramanifest.ManifestDiff)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DiffPolicyManifests indicates an expected call of DiffPolicyManifests.
+func (mr *MockEManifestHandlerMockRecorder) DiffPolicyManifests(ctx, sortedObjects, selectedPolicies any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DiffPolicyManifests", reflect.TypeOf((*MockEManifestHandler)(nil).DiffPolicyManifests), ctx, sortedObjects, selectedPolicies)
+}
+
 // ExportExtraManifestToDir mocks base method.
 func (m *MockEManifestHandler) ExportExtraManifestToDir(ctx context.Context, extraManifestCMs []v1.ConfigMapRef, toDir string) error {
 	m.ctrl.T.Helper()
@@ -85,12 +102,13 @@ func (mr *MockEManifestHandlerMockRecorder) ExtractAndExportManifestFromPolicies
 }
 
 // ValidateAndExtractManifestFromPolicies mocks base method.
-func (m *MockEManifestHandler) ValidateAndExtractManifestFromPolicies(ctx context.Context, policyLabels, objectLabels, validationAnns map[string]string) ([][]*unstructured.Unstructured, error) {
+func (m *MockEManifestHandler) ValidateAndExtractManifestFromPolicies(ctx context.Context, policyLabels, objectLabels, validationAnns map[string]string) ([][]*unstructured.Unstructured, []v1.SelectedPolicy, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "ValidateAndExtractManifestFromPolicies", ctx, policyLabels, objectLabels, validationAnns)
 	ret0, _ := ret[0].([][]*unstructured.Unstructured)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret1, _ := ret[1].([]v1.SelectedPolicy)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
 }
 
 // ValidateAndExtractManifestFromPolicies indicates an expected call of ValidateAndExtractManifestFromPolicies.
@@ -100,16 +118,17 @@ func (mr *MockEManifestHandlerMockRecorder) ValidateAndExtractManifestFromPolici
 }
 
 // ValidateExtraManifestConfigmaps mocks base method.
-func (m *MockEManifestHandler) ValidateExtraManifestConfigmaps(ctx context.Context, extraManifestCMs []v1.ConfigMapRef) (string, error) {
+func (m *MockEManifestHandler) ValidateExtraManifestConfigmaps(ctx context.Context, extraManifestCMs []v1.ConfigMapRef, fakeNamespaces bool) (string, []extramanifest.ManifestDiff, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ValidateExtraManifestConfigmaps", ctx, extraManifestCMs)
+	ret := m.ctrl.Call(m, "ValidateExtraManifestConfigmaps", ctx, extraManifestCMs, fakeNamespaces)
 	ret0, _ := ret[0].(string)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret1, _ := ret[1].([]extramanifest.ManifestDiff)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
 }
 
 // ValidateExtraManifestConfigmaps indicates an expected call of ValidateExtraManifestConfigmaps.
-func (mr *MockEManifestHandlerMockRecorder) ValidateExtraManifestConfigmaps(ctx, extraManifestCMs any) *gomock.Call {
+func (mr *MockEManifestHandlerMockRecorder) ValidateExtraManifestConfigmaps(ctx, extraManifestCMs, fakeNamespaces any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateExtraManifestConfigmaps", reflect.TypeOf((*MockEManifestHandler)(nil).ValidateExtraManifestConfigmaps), ctx, extraManifestCMs)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateExtraManifestConfigmaps", reflect.TypeOf((*MockEManifestHandler)(nil).ValidateExtraManifestConfigmaps), ctx, extraManifestCMs, fakeNamespaces)
 }
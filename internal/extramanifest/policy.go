@@ -9,6 +9,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/Masterminds/semver/v3"
 	"github.com/go-logr/logr"
 	"github.com/openshift-kni/lifecycle-agent/internal/common"
 	"github.com/samber/lo"
@@ -27,6 +28,10 @@ const ztpDeployWaveAnnotation = "ran.openshift.io/ztp-deploy-wave"
 const TargetOcpVersionManifestCountAnnotation = "lca.openshift.io/target-ocp-version-manifest-count"
 const TargetOcpVersionLabel = "lca.openshift.io/target-ocp-version"
 
+// TargetOcpVersionRangeAnnotation selects the manifests of a policy, or a manifest, for the target OCP versions in a
+// semantic version range, e.g. ">=4.16, <4.18", in addition to the exact versions of the target-ocp-version label
+const TargetOcpVersionRangeAnnotation = "lca.openshift.io/target-ocp-version-range"
+
 // GetPolicies gets the policies matching the labels from the namespace and sort them by the ztp wave annotation value
 func (h *EMHandler) GetPolicies(ctx context.Context, labels map[string]string) ([]*policiesv1.Policy, error) {
 	listOpts := []client.ListOption{
@@ -70,9 +75,11 @@ func (h *EMHandler) GetPolicies(ctx context.Context, labels map[string]string) (
 	return sortPolicyMap(policyWaveMap), nil
 }
 
-// Gets encapsulated objects from policy
-func getConfigurationObjects(log *logr.Logger, policy *policiesv1.Policy, objectLabels map[string]string) ([]*unstructured.Unstructured, error) {
+// Gets encapsulated objects from policy. Objects of mustnothave templates are annotated to be deleted. It also
+// returns the target-ocp-version label values and version ranges that selected the objects.
+func getConfigurationObjects(log *logr.Logger, policy *policiesv1.Policy, objectLabels map[string]string) ([]*unstructured.Unstructured, []string, error) {
 	var uobjects []*unstructured.Unstructured
+	var selectedBy []string
 
 	var objects []runtime.RawExtension
 
@@ -87,67 +94,112 @@ func getConfigurationObjects(log *logr.Logger, policy *policiesv1.Policy, object
 		var pol policyv1.ConfigurationPolicy
 		err := json.Unmarshal(ob.DeepCopy().Raw, &pol)
 		if err != nil {
-			return uobjects, fmt.Errorf("failed to unmarshal ConfigurationPolicy: %w", err)
+			return uobjects, selectedBy, fmt.Errorf("failed to unmarshal ConfigurationPolicy: %w", err)
 		}
 		for _, ot := range pol.Spec.ObjectTemplates {
-			if strings.EqualFold(string(ot.ComplianceType), string(policyv1.MustNotHave)) {
-				continue
-			}
-
 			var object unstructured.Unstructured
 			err = object.UnmarshalJSON(ot.ObjectDefinition.DeepCopy().Raw)
 			if err != nil {
-				return uobjects, fmt.Errorf("failed to unmarshal ObjectTemplate: %w", err)
+				return uobjects, selectedBy, fmt.Errorf("failed to unmarshal ObjectTemplate: %w", err)
 			}
 
+			var matchedVersion string
 			if len(objectLabels) > 0 {
-				if metadata, exists := object.Object["metadata"].(map[string]interface{}); exists {
-					if labels, exists := metadata["labels"].(map[string]interface{}); exists {
-						labelsFound := true
-						for oLabel, oValue := range objectLabels {
-							value, exists := labels[oLabel]
-							if !exists {
-								labelsFound = false
-								break
-							}
-
-							if oLabel == TargetOcpVersionLabel {
-								expectedValues := strings.Split(oValue, ",")
-								if !lo.Contains(expectedValues, value.(string)) {
-									log.Info(fmt.Sprintf("Found %s label in manifest %s %s, but its value %s is not in %v, skipping", oLabel, object.GetKind(), object.GetName(), value, expectedValues))
-									labelsFound = false
-									break
-								}
-							} else if oValue != value.(string) {
-								labelsFound = false
-								break
-							}
-						}
-						if !labelsFound {
-							continue
-						}
-						annotations, exists := metadata["annotations"].(map[string]interface{})
-						if !exists {
-							annotations = make(map[string]interface{})
-						}
-						applyType := getApplyType(string(ot.ComplianceType))
-						if applyType == "" {
-							return uobjects, fmt.Errorf("unsupported compliance type: %s", ot.ComplianceType)
-						}
-						annotations[common.ApplyTypeAnnotation] = applyType
-					} else {
-						continue
-					}
-				} else {
+				matched, version, err := matchObjectLabels(log, &object, policy.GetAnnotations()[TargetOcpVersionRangeAnnotation], objectLabels)
+				if err != nil {
+					return uobjects, selectedBy, fmt.Errorf("failed to match labels of %s %s in policy %s: %w", object.GetKind(), object.GetName(), policy.Name, err)
+				}
+				if !matched {
 					continue
 				}
+				matchedVersion = version
+			}
+
+			applyType := getApplyType(string(ot.ComplianceType))
+			if applyType == "" {
+				return uobjects, selectedBy, fmt.Errorf("unsupported compliance type: %s", ot.ComplianceType)
+			}
+			// The apply type of the objects matched by the policy labels is the default, unless they must be deleted
+			if len(objectLabels) > 0 || applyType == common.ApplyTypeDelete {
+				annotations := object.GetAnnotations()
+				if annotations == nil {
+					annotations = make(map[string]string)
+				}
+				annotations[common.ApplyTypeAnnotation] = applyType
+				object.SetAnnotations(annotations)
 			}
 
 			object.Object["status"] = map[string]interface{}{} // remove status, we can't apply it
 			uobjects = append(uobjects, &object)
+			if matchedVersion != "" && !lo.Contains(selectedBy, matchedVersion) {
+				selectedBy = append(selectedBy, matchedVersion)
+			}
+		}
+	}
+	return uobjects, selectedBy, nil
+}
+
+// matchObjectLabels returns whether the object has all the labels, and the target-ocp-version label value or version
+// range that matched. The target-ocp-version label matches any of the comma separated values, or the version range of
+// the object matches one of them. The version range of the policy applies to the objects without their own label or range.
+func matchObjectLabels(log *logr.Logger, object *unstructured.Unstructured, policyRange string, objectLabels map[string]string) (bool, string, error) {
+	labels := object.GetLabels()
+	var matchedVersion string
+	for oLabel, oValue := range objectLabels {
+		value, exists := labels[oLabel]
+
+		if oLabel == TargetOcpVersionLabel {
+			expectedValues := strings.Split(oValue, ",")
+			if exists && lo.Contains(expectedValues, value) {
+				matchedVersion = value
+				continue
+			}
+
+			versionRange := object.GetAnnotations()[TargetOcpVersionRangeAnnotation]
+			if versionRange == "" && !exists {
+				versionRange = policyRange
+			}
+			if versionRange != "" {
+				inRange, err := isInVersionRange(versionRange, expectedValues)
+				if err != nil {
+					return false, "", err
+				}
+				if inRange {
+					matchedVersion = versionRange
+					continue
+				}
+				log.Info(fmt.Sprintf("Found %s range %s for manifest %s %s, but none of %v is in it, skipping", TargetOcpVersionRangeAnnotation, versionRange, object.GetKind(), object.GetName(), expectedValues))
+				return false, "", nil
+			}
+
+			if exists {
+				log.Info(fmt.Sprintf("Found %s label in manifest %s %s, but its value %s is not in %v, skipping", oLabel, object.GetKind(), object.GetName(), value, expectedValues))
+			}
+			return false, "", nil
+		} else if !exists || oValue != value {
+			return false, "", nil
+		}
+	}
+	return true, matchedVersion, nil
+}
+
+// isInVersionRange returns whether one of the versions satisfies the semantic version range. Only the versions in the
+// Major.Minor.Patch form are checked, so pre-releases of a version are in the same ranges as the version.
+func isInVersionRange(versionRange string, versions []string) (bool, error) {
+	constraints, err := semver.NewConstraint(versionRange)
+	if err != nil {
+		return false, fmt.Errorf("invalid %s %q: %w", TargetOcpVersionRangeAnnotation, versionRange, err)
+	}
+	for _, v := range versions {
+		version, err := semver.StrictNewVersion(v)
+		if err != nil || version.Prerelease() != "" || version.Metadata() != "" {
+			continue
+		}
+		if constraints.Check(version) {
+			return true, nil
 		}
 	}
-	return uobjects, nil
+	return false, nil
 }
 
 func getApplyType(complianceType string) string {
@@ -156,6 +208,8 @@ func getApplyType(complianceType string) string {
 		applyType = common.ApplyTypeMerge
 	} else if strings.EqualFold(complianceType, string(policyv1.MustOnlyHave)) {
 		applyType = common.ApplyTypeReplace
+	} else if strings.EqualFold(complianceType, string(policyv1.MustNotHave)) {
+		applyType = common.ApplyTypeDelete
 	}
 	return applyType
 }
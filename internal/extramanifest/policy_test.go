@@ -1,10 +1,15 @@
 package extramanifest
 
 import (
+	"strings"
 	"testing"
 
+	"github.com/go-logr/logr"
+	"github.com/samber/lo"
 	"github.com/stretchr/testify/assert"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	policiesv1 "open-cluster-management.io/governance-policy-propagator/api/v1"
 )
 
@@ -63,3 +68,98 @@ func TestGetParentPolicyNameAndNamespace(t *testing.T) {
 	assert.Equal(t, res[0], "default")
 	assert.Equal(t, res[1], "upgrade.cluster")
 }
+
+const policyWithTargetOcpVersionRanges = `---
+kind: Policy
+apiVersion: policy.open-cluster-management.io/v1
+metadata:
+  name: ztp-common.p6
+  namespace: spoke
+  annotations:
+    ran.openshift.io/ztp-deploy-wave: "1"
+    lca.openshift.io/target-ocp-version-range: ">=4.16, <4.18"
+spec:
+  disabled: false
+  policy-templates:
+    - objectDefinition:
+        apiVersion: policy.open-cluster-management.io/v1
+        kind: ConfigurationPolicy
+        metadata:
+          name: common-ranges-config-policy-config
+        spec:
+          object-templates:
+            - complianceType: musthave
+              objectDefinition:
+                apiVersion: v1
+                kind: ConfigMap
+                metadata:
+                  name: policy-range
+                  namespace: default
+            - complianceType: musthave
+              objectDefinition:
+                apiVersion: v1
+                kind: ConfigMap
+                metadata:
+                  name: object-range
+                  namespace: default
+                  annotations:
+                    lca.openshift.io/target-ocp-version-range: "~4.15"
+            - complianceType: musthave
+              objectDefinition:
+                apiVersion: v1
+                kind: ConfigMap
+                metadata:
+                  name: exact-version
+                  namespace: default
+                  labels:
+                    lca.openshift.io/target-ocp-version: "4.15.3"
+  remediationAction: inform
+`
+
+func TestGetConfigurationObjectsWithTargetOcpVersionRanges(t *testing.T) {
+	policy := &policiesv1.Policy{}
+	assert.NoError(t, runtime.DefaultUnstructuredConverter.FromUnstructured(
+		mustConvertYamlStrToUnstructured(policyWithTargetOcpVersionRanges).Object, policy))
+	log := logr.Discard()
+
+	testcases := []struct {
+		targetVersion      string
+		expectedObjects    []string
+		expectedSelectedBy []string
+	}{
+		{
+			targetVersion:      "4.17.2",
+			expectedObjects:    []string{"policy-range"},
+			expectedSelectedBy: []string{">=4.16, <4.18"},
+		},
+		{
+			targetVersion:      "4.16.0-rc.1",
+			expectedObjects:    []string{"policy-range"},
+			expectedSelectedBy: []string{">=4.16, <4.18"},
+		},
+		{
+			targetVersion:      "4.15.3",
+			expectedObjects:    []string{"object-range", "exact-version"},
+			expectedSelectedBy: []string{"~4.15", "4.15.3"},
+		},
+		{
+			targetVersion:   "4.18.0",
+			expectedObjects: []string{},
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.targetVersion, func(t *testing.T) {
+			versions, err := GetMatchingTargetOcpVersionLabelVersions(tc.targetVersion)
+			assert.NoError(t, err)
+			objects, selectedBy, err := getConfigurationObjects(&log, policy,
+				map[string]string{TargetOcpVersionLabel: strings.Join(versions, ",")})
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedObjects, lo.Map(objects, func(o *unstructured.Unstructured, _ int) string { return o.GetName() }))
+			assert.Equal(t, tc.expectedSelectedBy, selectedBy)
+		})
+	}
+
+	policy.SetAnnotations(map[string]string{TargetOcpVersionRangeAnnotation: "4.16 or later"})
+	_, _, err := getConfigurationObjects(&log, policy, map[string]string{TargetOcpVersionLabel: "4.17.2,4.17"})
+	assert.ErrorContains(t, err, `failed to match labels of ConfigMap policy-range in policy ztp-common.p6: invalid lca.openshift.io/target-ocp-version-range "4.16 or later"`)
+}
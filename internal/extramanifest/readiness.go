@@ -0,0 +1,219 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package extramanifest
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/openshift-kni/lifecycle-agent/internal/common"
+	"github.com/openshift-kni/lifecycle-agent/utils"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+)
+
+var (
+	crdGroupKind          = schema.GroupKind{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"}
+	subscriptionGroupKind = schema.GroupKind{Group: "operators.coreos.com", Kind: "Subscription"}
+	csvGroupKind          = schema.GroupKind{Group: "operators.coreos.com", Kind: "ClusterServiceVersion"}
+	deploymentGroupKind   = schema.GroupKind{Group: "apps", Kind: "Deployment"}
+
+	csvGvk = schema.GroupVersionKind{Group: "operators.coreos.com", Version: "v1alpha1", Kind: "ClusterServiceVersion"}
+)
+
+// readinessInterval is the interval between the readiness checks of a wave
+var readinessInterval = 5 * time.Second
+
+// defaultApplyWaveTimeout is how long a wave waits to be ready when none of its manifests has the
+// lca.openshift.io/apply-wave-timeout annotation
+const defaultApplyWaveTimeout = 5 * time.Minute
+
+// waveState records the wave applied from a manifests directory and when, to track its readiness across reconciles
+type waveState struct {
+	Wave      int       `json:"wave"`
+	AppliedAt time.Time `json:"appliedAt"`
+}
+
+// waveStatePath returns the path of the file recording the wave state of a manifests directory
+func waveStatePath(fromDir string) string {
+	return filepath.Clean(fromDir) + "-wave.json"
+}
+
+// readWaveState reads the wave state of a manifests directory, the zero state if none is recorded yet
+func readWaveState(fromDir string) (waveState, error) {
+	state := waveState{}
+	if err := utils.ReadYamlOrJSONFile(waveStatePath(fromDir), &state); err != nil && !os.IsNotExist(err) {
+		return state, fmt.Errorf("failed to read the wave state of %s: %w", fromDir, err)
+	}
+	return state, nil
+}
+
+// WaitForManifestsReady waits until the applied manifests of a wave are ready, before the next wave is applied, as
+// described for getNotReadyManifests. The wave timeout is the longest lca.openshift.io/apply-wave-timeout annotation
+// of its manifests, 5 minutes without annotation, and a zero timeout does not wait.
+func WaitForManifestsReady(ctx context.Context, dc dynamic.Interface, restMapper meta.RESTMapper, manifests []*unstructured.Unstructured) error {
+	timeout, err := getApplyWaveTimeout(manifests)
+	if err != nil {
+		return NewEMFailedError(err.Error())
+	}
+	if timeout == 0 {
+		return nil
+	}
+
+	var notReady []string
+	err = wait.PollUntilContextTimeout(ctx, readinessInterval, timeout, true, func(ctx context.Context) (bool, error) {
+		var err error
+		notReady, err = getNotReadyManifests(ctx, dc, restMapper, manifests)
+		return len(notReady) == 0, err
+	})
+	if err != nil {
+		if wait.Interrupted(err) && len(notReady) > 0 {
+			return newNotReadyError(timeout, notReady)
+		}
+		return fmt.Errorf("failed to wait for manifests to be ready: %w", err)
+	}
+	return nil
+}
+
+// getNotReadyManifests returns the applied manifests that are not ready yet: CRDs must be Established, Subscriptions
+// must have installed a CSV, CSVs must have Succeeded and Deployments must be Available. Other manifests are ready once
+// applied, as are Subscriptions with Manual install plan approval, whose install plan waits for the user.
+func getNotReadyManifests(ctx context.Context, dc dynamic.Interface, restMapper meta.RESTMapper, manifests []*unstructured.Unstructured) ([]string, error) {
+	var notReady []string
+	for _, manifest := range manifests {
+		ready, err := isManifestReady(ctx, dc, restMapper, manifest)
+		if err != nil {
+			return nil, err
+		}
+		if !ready {
+			notReady = append(notReady, fmt.Sprintf("%s[%s]", manifest.GetKind(), manifest.GetName()))
+		}
+	}
+	return notReady, nil
+}
+
+func newNotReadyError(timeout time.Duration, notReady []string) error {
+	return NewEMFailedError(fmt.Sprintf("manifests are not ready after %s: %s", timeout, strings.Join(notReady, ", ")))
+}
+
+// getApplyWaveTimeout returns the longest apply-wave-timeout annotation of the manifests, or the default timeout if
+// there are none
+func getApplyWaveTimeout(manifests []*unstructured.Unstructured) (time.Duration, error) {
+	var timeout time.Duration
+	annotated := false
+	for _, manifest := range manifests {
+		value, exists := manifest.GetAnnotations()[common.ApplyWaveTimeoutAnn]
+		if !exists {
+			continue
+		}
+		t, err := time.ParseDuration(value)
+		if err != nil || t < 0 {
+			return 0, fmt.Errorf("invalid %s annotation %q in %s[%s]", common.ApplyWaveTimeoutAnn, value, manifest.GetKind(), manifest.GetName())
+		}
+		timeout = max(timeout, t)
+		annotated = true
+	}
+	if !annotated {
+		return defaultApplyWaveTimeout, nil
+	}
+	return timeout, nil
+}
+
+// isManifestReady returns whether the object of an applied manifest is ready. Deleted manifests are always ready.
+func isManifestReady(ctx context.Context, dc dynamic.Interface, restMapper meta.RESTMapper, manifest *unstructured.Unstructured) (bool, error) {
+	if manifest.GetAnnotations()[common.ApplyTypeAnnotation] == common.ApplyTypeDelete {
+		return true, nil
+	}
+
+	switch manifest.GroupVersionKind().GroupKind() {
+	case crdGroupKind:
+		obj, err := getAppliedObject(ctx, dc, restMapper, manifest)
+		if obj == nil || err != nil {
+			return false, err
+		}
+		return hasTrueCondition(obj, "Established"), nil
+	case subscriptionGroupKind:
+		obj, err := getAppliedObject(ctx, dc, restMapper, manifest)
+		if obj == nil || err != nil {
+			return false, err
+		}
+		if approval, _, _ := unstructured.NestedString(obj.Object, "spec", "installPlanApproval"); approval == "Manual" {
+			return true, nil
+		}
+		installedCSV, _, _ := unstructured.NestedString(obj.Object, "status", "installedCSV")
+		if installedCSV == "" {
+			return false, nil
+		}
+		csv := &unstructured.Unstructured{}
+		csv.SetGroupVersionKind(csvGvk)
+		csv.SetName(installedCSV)
+		csv.SetNamespace(obj.GetNamespace())
+		return isManifestReady(ctx, dc, restMapper, csv)
+	case csvGroupKind:
+		obj, err := getAppliedObject(ctx, dc, restMapper, manifest)
+		if obj == nil || err != nil {
+			return false, err
+		}
+		phase, _, _ := unstructured.NestedString(obj.Object, "status", "phase")
+		return phase == "Succeeded", nil
+	case deploymentGroupKind:
+		obj, err := getAppliedObject(ctx, dc, restMapper, manifest)
+		if obj == nil || err != nil {
+			return false, err
+		}
+		observedGeneration, _, _ := unstructured.NestedInt64(obj.Object, "status", "observedGeneration")
+		return observedGeneration >= obj.GetGeneration() && hasTrueCondition(obj, "Available"), nil
+	default:
+		return true, nil
+	}
+}
+
+// getAppliedObject gets the object of an applied manifest, or nil if it is not found yet
+func getAppliedObject(ctx context.Context, dc dynamic.Interface, restMapper meta.RESTMapper, manifest *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	resource, err := getResourceInterface(dc, restMapper, manifest)
+	if err != nil {
+		return nil, err
+	}
+	obj, err := resource.Get(ctx, manifest.GetName(), metav1.GetOptions{})
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get %s called %s: %w", manifest.GetKind(), manifest.GetName(), err)
+	}
+	return obj, nil
+}
+
+func hasTrueCondition(obj *unstructured.Unstructured, conditionType string) bool {
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if ok && condition["type"] == conditionType && condition["status"] == string(metav1.ConditionTrue) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,225 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package extramanifest
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/openshift-kni/lifecycle-agent/internal/common"
+	"github.com/openshift-kni/lifecycle-agent/utils"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newUnstructured(gvk schema.GroupVersionKind, namespace, name string, annotations map[string]string, status map[string]interface{}) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{"status": status}}
+	obj.SetGroupVersionKind(gvk)
+	obj.SetNamespace(namespace)
+	obj.SetName(name)
+	obj.SetAnnotations(annotations)
+	return obj
+}
+
+func TestGetApplyWaveTimeout(t *testing.T) {
+	gvk := schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}
+	timeout, err := getApplyWaveTimeout([]*unstructured.Unstructured{newUnstructured(gvk, "default", "cm1", nil, nil)})
+	assert.NoError(t, err)
+	assert.Equal(t, defaultApplyWaveTimeout, timeout)
+
+	timeout, err = getApplyWaveTimeout([]*unstructured.Unstructured{
+		newUnstructured(gvk, "default", "cm1", map[string]string{common.ApplyWaveTimeoutAnn: "2m"}, nil),
+		newUnstructured(gvk, "default", "cm2", map[string]string{common.ApplyWaveTimeoutAnn: "10m"}, nil),
+		newUnstructured(gvk, "default", "cm3", nil, nil),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 10*time.Minute, timeout)
+
+	timeout, err = getApplyWaveTimeout([]*unstructured.Unstructured{
+		newUnstructured(gvk, "default", "cm1", map[string]string{common.ApplyWaveTimeoutAnn: "0s"}, nil),
+	})
+	assert.NoError(t, err)
+	assert.Zero(t, timeout)
+
+	_, err = getApplyWaveTimeout([]*unstructured.Unstructured{
+		newUnstructured(gvk, "default", "cm1", map[string]string{common.ApplyWaveTimeoutAnn: "soon"}, nil),
+	})
+	assert.ErrorContains(t, err, "invalid lca.openshift.io/apply-wave-timeout annotation \"soon\" in ConfigMap[cm1]")
+}
+
+func TestWaitForManifestsReady(t *testing.T) {
+	origReadinessInterval := readinessInterval
+	defer func() {
+		readinessInterval = origReadinessInterval
+	}()
+	readinessInterval = 10 * time.Millisecond
+
+	crdGvk := crdGroupKind.WithVersion("v1")
+	subscriptionGvk := subscriptionGroupKind.WithVersion("v1alpha1")
+	deploymentGvk := deploymentGroupKind.WithVersion("v1")
+	cmGvk := schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(crdGvk, meta.RESTScopeRoot)
+	mapper.Add(subscriptionGvk, meta.RESTScopeNamespace)
+	mapper.Add(csvGvk, meta.RESTScopeNamespace)
+	mapper.Add(deploymentGvk, meta.RESTScopeNamespace)
+	mapper.Add(cmGvk, meta.RESTScopeNamespace)
+
+	available := map[string]interface{}{
+		"observedGeneration": int64(1),
+		"conditions":         []interface{}{map[string]interface{}{"type": "Available", "status": "True"}},
+	}
+	crd := newUnstructured(crdGvk, "", "widgets.example.com", nil, map[string]interface{}{
+		"conditions": []interface{}{map[string]interface{}{"type": "Established", "status": "True"}},
+	})
+	subscription := newUnstructured(subscriptionGvk, "widgets", "widget-operator", nil, map[string]interface{}{
+		"installedCSV": "widget-operator.v1.0.0",
+	})
+	csv := newUnstructured(csvGvk, "widgets", "widget-operator.v1.0.0", nil, map[string]interface{}{"phase": "Succeeded"})
+	deployment := newUnstructured(deploymentGvk, "widgets", "web", nil, available)
+	deployment.SetGeneration(1)
+	unavailable := newUnstructured(deploymentGvk, "widgets", "db", nil, map[string]interface{}{
+		"observedGeneration": int64(1),
+		"conditions":         []interface{}{map[string]interface{}{"type": "Available", "status": "False"}},
+	})
+	stale := newUnstructured(deploymentGvk, "widgets", "cache", nil, available)
+	stale.SetGeneration(2)
+	manualSubscription := newUnstructured(subscriptionGvk, "widgets", "manual-operator", nil, nil)
+	manualSubscription.Object["spec"] = map[string]interface{}{"installPlanApproval": "Manual"}
+
+	dc := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}:    "CustomResourceDefinitionList",
+		{Group: "operators.coreos.com", Version: "v1alpha1", Resource: "subscriptions"}:          "SubscriptionList",
+		{Group: "operators.coreos.com", Version: "v1alpha1", Resource: "clusterserviceversions"}: "ClusterServiceVersionList",
+		{Group: "apps", Version: "v1", Resource: "deployments"}:                                  "DeploymentList",
+	}, crd, subscription, csv, deployment, unavailable, stale, manualSubscription)
+
+	// Ready manifests, manifests of other kinds, Subscriptions with Manual approval and deleted manifests do not wait
+	timeoutAnn := map[string]string{common.ApplyWaveTimeoutAnn: "100ms"}
+	assert.NoError(t, WaitForManifestsReady(context.Background(), dc, mapper, []*unstructured.Unstructured{
+		crd, subscription, deployment,
+		newUnstructured(cmGvk, "widgets", "config", timeoutAnn, nil),
+		newUnstructured(subscriptionGvk, "widgets", "manual-operator", nil, nil),
+		newUnstructured(deploymentGvk, "widgets", "old", map[string]string{common.ApplyTypeAnnotation: common.ApplyTypeDelete}, nil),
+	}))
+
+	// The wave fails once its timeout expires
+	err := WaitForManifestsReady(context.Background(), dc, mapper, []*unstructured.Unstructured{
+		deployment,
+		newUnstructured(deploymentGvk, "widgets", "db", timeoutAnn, nil),
+		newUnstructured(deploymentGvk, "widgets", "cache", nil, nil),
+		newUnstructured(subscriptionGvk, "widgets", "missing", nil, nil),
+	})
+	assert.True(t, IsEMFailedError(err))
+	assert.EqualError(t, err, "manifests are not ready after 100ms: Deployment[db], Deployment[cache], Subscription[missing]")
+
+	// A zero timeout does not wait
+	assert.NoError(t, WaitForManifestsReady(context.Background(), dc, mapper, []*unstructured.Unstructured{
+		newUnstructured(deploymentGvk, "widgets", "db", map[string]string{common.ApplyWaveTimeoutAnn: "0s"}, nil),
+	}))
+}
+
+func TestApplyExtraManifestsTracksWaves(t *testing.T) {
+	deploymentGvk := deploymentGroupKind.WithVersion("v1")
+	cmGvk := schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}
+	deploymentGvr := schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+	cmGvr := schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(deploymentGvk, meta.RESTScopeNamespace)
+	mapper.Add(cmGvk, meta.RESTScopeNamespace)
+
+	dc := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		deploymentGvr: "DeploymentList",
+		cmGvr:         "ConfigMapList",
+	})
+	handler := &EMHandler{
+		Client:        fake.NewClientBuilder().WithScheme(testscheme).WithRESTMapper(mapper).Build(),
+		DynamicClient: dc,
+		Log:           ctrl.Log.WithName("ExtraManifest"),
+	}
+
+	writeWaves := func(dir string, waves ...*unstructured.Unstructured) {
+		for i, manifest := range waves {
+			waveDir := filepath.Join(dir, fmt.Sprintf("group%d", i+1))
+			assert.NoError(t, os.MkdirAll(waveDir, 0o700))
+			assert.NoError(t, utils.MarshalToFile(manifest.Object, filepath.Join(waveDir, "manifest.json")))
+		}
+	}
+
+	// The first wave is ready once applied, the second one waits for its Deployment to be Available
+	fromDir := filepath.Join(t.TempDir(), "policy-manifests")
+	writeWaves(fromDir,
+		newUnstructured(cmGvk, "widgets", "first", nil, nil),
+		newUnstructured(deploymentGvk, "widgets", "web", nil, nil),
+		newUnstructured(cmGvk, "widgets", "last", nil, nil),
+	)
+	applied, err := handler.ApplyExtraManifests(context.Background(), fromDir)
+	assert.NoError(t, err)
+	assert.False(t, applied)
+	state, err := readWaveState(fromDir)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, state.Wave)
+	_, err = dc.Resource(cmGvr).Namespace("widgets").Get(context.Background(), "last", metav1.GetOptions{})
+	assert.Error(t, err, "the next wave must not be applied before the Deployment is Available")
+
+	// The wave is not applied again on the next reconcile
+	appliedAt := state.AppliedAt
+	applied, err = handler.ApplyExtraManifests(context.Background(), fromDir)
+	assert.NoError(t, err)
+	assert.False(t, applied)
+	state, err = readWaveState(fromDir)
+	assert.NoError(t, err)
+	assert.Equal(t, appliedAt, state.AppliedAt)
+
+	web, err := dc.Resource(deploymentGvr).Namespace("widgets").Get(context.Background(), "web", metav1.GetOptions{})
+	assert.NoError(t, err)
+	web.Object["status"] = map[string]interface{}{
+		"conditions": []interface{}{map[string]interface{}{"type": "Available", "status": "True"}},
+	}
+	_, err = dc.Resource(deploymentGvr).Namespace("widgets").Update(context.Background(), web, metav1.UpdateOptions{})
+	assert.NoError(t, err)
+
+	applied, err = handler.ApplyExtraManifests(context.Background(), fromDir)
+	assert.NoError(t, err)
+	assert.True(t, applied)
+	_, err = dc.Resource(cmGvr).Namespace("widgets").Get(context.Background(), "last", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.NoDirExists(t, fromDir)
+	assert.NoFileExists(t, waveStatePath(fromDir))
+
+	// A wave that is not ready once its timeout expires fails
+	fromDir = filepath.Join(t.TempDir(), "extra-manifests")
+	writeWaves(fromDir, newUnstructured(deploymentGvk, "widgets", "db", map[string]string{common.ApplyWaveTimeoutAnn: "1h"}, nil))
+	applied, err = handler.ApplyExtraManifests(context.Background(), fromDir)
+	assert.NoError(t, err)
+	assert.False(t, applied)
+	assert.NoError(t, utils.MarshalToFile(waveState{Wave: 0, AppliedAt: time.Now().Add(-2 * time.Hour)}, waveStatePath(fromDir)))
+	_, err = handler.ApplyExtraManifests(context.Background(), fromDir)
+	assert.True(t, IsEMFailedError(err))
+	assert.EqualError(t, err, "manifests are not ready after 1h0m0s: Deployment[db]")
+}
@@ -0,0 +1,246 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package extramanifest
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/samber/lo"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// +kubebuilder:rbac:groups=cluster.open-cluster-management.io,resources=clusterclaims,verbs=get
+
+const (
+	templateStart    = "{{"
+	hubTemplateStart = "{{hub"
+)
+
+var (
+	clusterClaimGvk = schema.GroupVersionKind{Group: "cluster.open-cluster-management.io", Version: "v1alpha1", Kind: "ClusterClaim"}
+
+	// typedTemplateRegex matches the values that are a single template converted to an integer or a boolean, which are
+	// not strings once resolved
+	typedTemplateRegex = regexp.MustCompile(`^\s*\{\{[^{}]*\|\s*(toInt|toBool)\s*\}\}\s*$`)
+
+	// undefinedFuncRegex matches the parse error of a template calling a function that is not in the function map,
+	// such as the sprig functions or the policy functions not supported by LCA, e.g. copySecretData or indent
+	undefinedFuncRegex = regexp.MustCompile(`function "([^"]+)" not defined`)
+)
+
+// templateResolver resolves the managed cluster templates of the policies, e.g. {{ fromConfigMap "ns" "name" "key" }},
+// against the cluster, as the configuration policy controller would. Hub templates are resolved by the hub in the
+// policies replicated to the cluster, so the ones left are errors.
+type templateResolver struct {
+	ctx    context.Context
+	client client.Reader
+}
+
+// resolveTemplates resolves the templates in the string values of the object in place. The error names the field of
+// the object and the template function that failed.
+func resolveTemplates(ctx context.Context, c client.Reader, object *unstructured.Unstructured) error {
+	r := &templateResolver{ctx: ctx, client: c}
+	resolved, err := r.resolveValue(object.Object, "")
+	if err != nil {
+		return err
+	}
+	object.Object = resolved.(map[string]interface{})
+	return nil
+}
+
+func (r *templateResolver) resolveValue(value interface{}, path string) (interface{}, error) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		keys := lo.Keys(v)
+		sort.Strings(keys)
+		for _, key := range keys {
+			resolved, err := r.resolveValue(v[key], strings.TrimPrefix(path+"."+key, "."))
+			if err != nil {
+				return nil, err
+			}
+			v[key] = resolved
+		}
+	case []interface{}:
+		for i := range v {
+			resolved, err := r.resolveValue(v[i], fmt.Sprintf("%s[%d]", path, i))
+			if err != nil {
+				return nil, err
+			}
+			v[i] = resolved
+		}
+	case string:
+		if strings.Contains(v, templateStart) {
+			return r.resolveString(v, path)
+		}
+	}
+	return value, nil
+}
+
+func (r *templateResolver) resolveString(value, path string) (interface{}, error) {
+	if strings.Contains(value, hubTemplateStart) {
+		return nil, fmt.Errorf("%s: hub template is not resolved in the policy: %s", path, value)
+	}
+
+	tmpl, err := template.New(path).Option("missingkey=error").Funcs(r.funcMap()).Parse(value)
+	if err != nil {
+		if match := undefinedFuncRegex.FindStringSubmatch(err.Error()); match != nil {
+			return nil, fmt.Errorf("%s: template function %s is not supported: %s", path, match[1], value)
+		}
+		return nil, fmt.Errorf("%s: failed to parse template: %w", path, err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, nil); err != nil {
+		return nil, fmt.Errorf("%s: failed to resolve template: %w", path, err)
+	}
+
+	resolved := buf.String()
+	if match := typedTemplateRegex.FindStringSubmatch(value); match != nil {
+		if match[1] == "toInt" {
+			i, err := strconv.ParseInt(resolved, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("%s: failed to convert %q to an integer: %w", path, resolved, err)
+			}
+			return i, nil
+		}
+		b, err := strconv.ParseBool(resolved)
+		if err != nil {
+			return nil, fmt.Errorf("%s: failed to convert %q to a boolean: %w", path, resolved, err)
+		}
+		return b, nil
+	}
+	return resolved, nil
+}
+
+func (r *templateResolver) funcMap() template.FuncMap {
+	return template.FuncMap{
+		"fromConfigMap":    r.fromConfigMap,
+		"fromSecret":       r.fromSecret,
+		"fromClusterClaim": r.fromClusterClaim,
+		"lookup":           r.lookup,
+		"base64enc":        base64enc,
+		"base64dec":        base64dec,
+		"atoi":             strconv.Atoi,
+		"toInt":            toInt,
+		"toBool":           toBool,
+		// The values are resolved in place, so their indentation is already right
+		"autoindent": func(s string) string { return s },
+	}
+}
+
+func (r *templateResolver) fromConfigMap(namespace, name, key string) (string, error) {
+	cm := &corev1.ConfigMap{}
+	if err := r.client.Get(r.ctx, types.NamespacedName{Namespace: namespace, Name: name}, cm); err != nil {
+		return "", fmt.Errorf("failed to get configmap %s/%s: %w", namespace, name, err)
+	}
+	return cm.Data[key], nil
+}
+
+// fromSecret returns the base64 encoded value, as it is in the data of the secret
+func (r *templateResolver) fromSecret(namespace, name, key string) (string, error) {
+	secret := &corev1.Secret{}
+	if err := r.client.Get(r.ctx, types.NamespacedName{Namespace: namespace, Name: name}, secret); err != nil {
+		return "", fmt.Errorf("failed to get secret %s/%s: %w", namespace, name, err)
+	}
+	value, exists := secret.Data[key]
+	if !exists {
+		return "", nil
+	}
+	return base64.StdEncoding.EncodeToString(value), nil
+}
+
+func (r *templateResolver) fromClusterClaim(name string) (string, error) {
+	claim := &unstructured.Unstructured{}
+	claim.SetGroupVersionKind(clusterClaimGvk)
+	if err := r.client.Get(r.ctx, types.NamespacedName{Name: name}, claim); err != nil {
+		return "", fmt.Errorf("failed to get clusterclaim %s: %w", name, err)
+	}
+	value, _, _ := unstructured.NestedString(claim.Object, "spec", "value")
+	return value, nil
+}
+
+// lookup returns the object, or an empty map if it is not found
+func (r *templateResolver) lookup(apiVersion, kind, namespace, name string) (map[string]interface{}, error) {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion(apiVersion)
+	obj.SetKind(kind)
+	if err := r.client.Get(r.ctx, types.NamespacedName{Namespace: namespace, Name: name}, obj); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return map[string]interface{}{}, nil
+		}
+		return nil, fmt.Errorf("failed to get %s %s/%s: %w", kind, namespace, name, err)
+	}
+	return obj.Object, nil
+}
+
+func base64enc(s string) string {
+	return base64.StdEncoding.EncodeToString([]byte(s))
+}
+
+func base64dec(s string) (string, error) {
+	decoded, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode base64: %w", err)
+	}
+	return string(decoded), nil
+}
+
+func toInt(value interface{}) (int64, error) {
+	switch v := value.(type) {
+	case int:
+		return int64(v), nil
+	case int64:
+		return v, nil
+	case float64:
+		return int64(v), nil
+	case string:
+		i, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("failed to convert %q to an integer: %w", v, err)
+		}
+		return i, nil
+	default:
+		return 0, fmt.Errorf("failed to convert %v to an integer", value)
+	}
+}
+
+func toBool(value interface{}) (bool, error) {
+	switch v := value.(type) {
+	case bool:
+		return v, nil
+	case string:
+		b, err := strconv.ParseBool(strings.TrimSpace(v))
+		if err != nil {
+			return false, fmt.Errorf("failed to convert %q to a boolean: %w", v, err)
+		}
+		return b, nil
+	default:
+		return false, fmt.Errorf("failed to convert %v to a boolean", value)
+	}
+}
@@ -0,0 +1,214 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package extramanifest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestResolveTemplates(t *testing.T) {
+	s := runtime.NewScheme()
+	assert.NoError(t, corev1.AddToScheme(s))
+	s.AddKnownTypeWithName(clusterClaimGvk, &unstructured.Unstructured{})
+
+	claim := &unstructured.Unstructured{}
+	claim.SetGroupVersionKind(clusterClaimGvk)
+	claim.SetName("id.openshift.io")
+	assert.NoError(t, unstructured.SetNestedField(claim.Object, "1234-abcd", "spec", "value"))
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "site-data", Namespace: "ztp-site"},
+			Data:       map[string]string{"registry": "registry.example.com:5000", "replicas": "3", "debug": "true"},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "site-secret", Namespace: "ztp-site"},
+			Data:       map[string][]byte{"token": []byte("s3cr3t")},
+		},
+		claim,
+	).Build()
+
+	newObject := func(spec map[string]interface{}) *unstructured.Unstructured {
+		object := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+		object.SetAPIVersion("example.com/v1")
+		object.SetKind("Widget")
+		object.SetName("widget")
+		return object
+	}
+
+	object := newObject(map[string]interface{}{
+		"image":     `{{ fromConfigMap "ztp-site" "site-data" "registry" }}/widget:latest`,
+		"replicas":  `{{ fromConfigMap "ztp-site" "site-data" "replicas" | toInt }}`,
+		"debug":     `{{ fromConfigMap "ztp-site" "site-data" "debug" | toBool }}`,
+		"token":     `{{ fromSecret "ztp-site" "site-secret" "token" }}`,
+		"plain":     `{{ fromSecret "ztp-site" "site-secret" "token" | base64dec }}`,
+		"encoded":   `{{ "hello" | base64enc }}`,
+		"clusterID": `{{ fromClusterClaim "id.openshift.io" }}`,
+		"labels": []interface{}{
+			`{{ (lookup "v1" "ConfigMap" "ztp-site" "site-data").metadata.name }}`,
+			`{{ if lookup "v1" "ConfigMap" "ztp-site" "missing" }}found{{ else }}absent{{ end }}`,
+			"static",
+		},
+	})
+	assert.NoError(t, resolveTemplates(context.Background(), c, object))
+	assert.Equal(t, map[string]interface{}{
+		"image":     "registry.example.com:5000/widget:latest",
+		"replicas":  int64(3),
+		"debug":     true,
+		"token":     "czNjcjN0",
+		"plain":     "s3cr3t",
+		"encoded":   "aGVsbG8=",
+		"clusterID": "1234-abcd",
+		"labels":    []interface{}{"site-data", "absent", "static"},
+	}, object.Object["spec"])
+
+	// The failures name the field and the template function
+	err := resolveTemplates(context.Background(), c, newObject(map[string]interface{}{
+		"containers": []interface{}{map[string]interface{}{"image": `{{ fromConfigMap "ztp-site" "missing" "registry" }}`}},
+	}))
+	assert.ErrorContains(t, err, "spec.containers[0].image: failed to resolve template")
+	assert.ErrorContains(t, err, `error calling fromConfigMap: failed to get configmap ztp-site/missing`)
+
+	err = resolveTemplates(context.Background(), c, newObject(map[string]interface{}{
+		"image": `{{hub fromConfigMap "" "site-data" "registry" hub}}`,
+	}))
+	assert.ErrorContains(t, err, "spec.image: hub template is not resolved in the policy")
+
+	err = resolveTemplates(context.Background(), c, newObject(map[string]interface{}{
+		"image": `{{ fromConfigMap "ztp-site" "site-data" "registry" | toInt }}`,
+	}))
+	assert.ErrorContains(t, err, "spec.image: failed to resolve template")
+	assert.ErrorContains(t, err, "error calling toInt")
+
+	// The fields missing from a map are errors
+	err = resolveTemplates(context.Background(), c, newObject(map[string]interface{}{
+		"image": `{{ (lookup "v1" "ConfigMap" "ztp-site" "missing").metadata.name }}`,
+	}))
+	assert.ErrorContains(t, err, "spec.image: failed to resolve template")
+	assert.ErrorContains(t, err, `map has no entry for key "metadata"`)
+
+	// The functions that are not supported are named
+	for name, value := range map[string]string{
+		"default":        `{{ fromConfigMap "ztp-site" "site-data" "registry" | default "quay.io" }}`,
+		"copySecretData": `{{ copySecretData "ztp-site" "site-secret" }}`,
+		"indent":         `{{ fromConfigMap "ztp-site" "site-data" "registry" | indent 2 }}`,
+	} {
+		err = resolveTemplates(context.Background(), c, newObject(map[string]interface{}{"data": value}))
+		assert.ErrorContains(t, err, "spec.data: template function "+name+" is not supported")
+	}
+}
+
+const policyWithTemplates = `---
+kind: Policy
+apiVersion: policy.open-cluster-management.io/v1
+metadata:
+  name: ztp-common.p1
+  namespace: spoke
+  labels:
+    lca.openshift.io/target-ocp-version: "4.16.1"
+  annotations:
+    ran.openshift.io/ztp-deploy-wave: "1"
+spec:
+  disabled: false
+  policy-templates:
+    - objectDefinition:
+        apiVersion: policy.open-cluster-management.io/v1
+        kind: ConfigurationPolicy
+        metadata:
+          name: common-templates-config-policy-config
+        spec:
+          object-templates:
+            - complianceType: musthave
+              objectDefinition:
+                apiVersion: v1
+                kind: ConfigMap
+                metadata:
+                  name: widget-config
+                  namespace: widgets
+                data:
+                  registry: '{{ fromConfigMap "ztp-site" "site-data" "registry" }}'
+                  token: '{{ fromSecret "ztp-site" "site-secret" "token" }}'
+                  clusterID: '{{ fromClusterClaim "id.openshift.io" }}'
+                  site: '{{ (lookup "v1" "ConfigMap" "ztp-site" "site-data").metadata.name }}'
+          remediationAction: inform
+          severity: low
+  remediationAction: inform
+`
+
+func TestValidateAndExtractManifestFromPoliciesResolvesTemplatesWithNoncachedClient(t *testing.T) {
+	s := runtime.NewScheme()
+	assert.NoError(t, corev1.AddToScheme(s))
+	s.AddKnownTypeWithName(clusterClaimGvk, &unstructured.Unstructured{})
+
+	claim := &unstructured.Unstructured{}
+	claim.SetGroupVersionKind(clusterClaimGvk)
+	claim.SetName("id.openshift.io")
+	assert.NoError(t, unstructured.SetNestedField(claim.Object, "1234-abcd", "spec", "value"))
+	noncachedClient := fake.NewClientBuilder().WithScheme(s).WithObjects(
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "site-data", Namespace: "ztp-site"},
+			Data:       map[string]string{"registry": "registry.example.com:5000"},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "site-secret", Namespace: "ztp-site"},
+			Data:       map[string][]byte{"token": []byte("s3cr3t")},
+		},
+		claim,
+	).Build()
+
+	// The cached client only serves the policies, any other read would start an informer
+	cachedClient := fake.NewClientBuilder().WithScheme(testscheme).WithObjects(
+		&apiextensionsv1.CustomResourceDefinition{ObjectMeta: metav1.ObjectMeta{Name: "policies.policy.open-cluster-management.io"}},
+		mustConvertYamlStrToUnstructured(policyWithTemplates),
+	).WithInterceptorFuncs(interceptor.Funcs{
+		Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+			if _, ok := obj.(*apiextensionsv1.CustomResourceDefinition); !ok {
+				return fmt.Errorf("unexpected get of %T %s with the cached client", obj, key)
+			}
+			return c.Get(ctx, key, obj, opts...)
+		},
+	}).Build()
+
+	handler := &EMHandler{
+		Client:          cachedClient,
+		NoncachedClient: noncachedClient,
+		Log:             ctrl.Log.WithName("ExtraManifest"),
+	}
+	objects, _, err := handler.ValidateAndExtractManifestFromPolicies(context.Background(),
+		map[string]string{TargetOcpVersionLabel: "4.16.1"}, nil, nil)
+	assert.NoError(t, err)
+	if assert.Len(t, objects, 1) && assert.Len(t, objects[0], 1) {
+		assert.Equal(t, map[string]interface{}{
+			"registry":  "registry.example.com:5000",
+			"token":     "czNjcjN0",
+			"clusterID": "1234-abcd",
+			"site":      "site-data",
+		}, objects[0][0].Object["data"])
+	}
+}
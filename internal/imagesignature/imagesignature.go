@@ -0,0 +1,234 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package imagesignature verifies the cosign signatures of container images. The image manifest and its signature are
+// retrieved with skopeo, without pulling the image layers.
+package imagesignature
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/containers/image/v5/docker/reference"
+	"github.com/openshift-kni/lifecycle-agent/internal/common"
+	"github.com/openshift-kni/lifecycle-agent/lca-cli/ops"
+)
+
+const (
+	// SignatureAnnotation is the annotation of the signature manifest layers holding the base64 encoded signature
+	SignatureAnnotation = "dev.cosignproject.cosign/signature"
+	// SignatureType is the type of the cosign simple signing payload
+	SignatureType = "cosign container image signature"
+)
+
+// VerificationError is returned when the signature of an image cannot be verified with the trusted public keys
+type VerificationError struct {
+	Image  string
+	Reason string
+}
+
+func (e *VerificationError) Error() string {
+	return fmt.Sprintf("failed to verify the signature of image %s: %s", e.Image, e.Reason)
+}
+
+// IsVerificationError checks if the error, or any error it wraps, is a VerificationError
+func IsVerificationError(err error) bool {
+	var verificationErr *VerificationError
+	return errors.As(err, &verificationErr)
+}
+
+// payload is the cosign simple signing payload
+type payload struct {
+	Critical struct {
+		Image struct {
+			DockerManifestDigest string `json:"docker-manifest-digest"`
+		} `json:"image"`
+		Type string `json:"type"`
+	} `json:"critical"`
+}
+
+// manifest holds the fields of the signature OCI manifest used for the verification
+type manifest struct {
+	Layers []struct {
+		Digest      string            `json:"digest"`
+		Annotations map[string]string `json:"annotations"`
+	} `json:"layers"`
+}
+
+// ParsePublicKeys parses every PEM encoded public key found in the data
+func ParsePublicKeys(data []byte) ([]crypto.PublicKey, error) {
+	var keys []crypto.PublicKey
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != "PUBLIC KEY" {
+			continue
+		}
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse public key: %w", err)
+		}
+		keys = append(keys, key)
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no PEM encoded public key found")
+	}
+	return keys, nil
+}
+
+// Verify checks that the manifest the image points to is signed with one of the public keys and returns its digest.
+// The signature is retrieved with skopeo into a temporary directory created in workDir, which is a path on the host.
+// A VerificationError is returned if the image is not signed or if none of its signatures can be verified.
+func Verify(executor ops.Execute, image, authFile, workDir string, publicKeys []crypto.PublicKey) (string, error) {
+	named, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse image %s: %w", image, err)
+	}
+
+	digest, err := executor.Execute("skopeo", "inspect", "--retry-times", "10", "--authfile", authFile,
+		"--no-tags", "--format", "{{.Digest}}", "docker://"+image)
+	if err != nil || digest == "" {
+		return "", fmt.Errorf("failed to get the manifest digest of image %s: %w", image, err)
+	}
+	algorithm, hexDigest, found := strings.Cut(digest, ":")
+	if !found || algorithm != "sha256" {
+		return "", fmt.Errorf("unexpected manifest digest %s for image %s", digest, image)
+	}
+
+	tmpDir, err := os.MkdirTemp(common.PathOutsideChroot(workDir), "seed-signature-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create signature directory: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	signatureImage := fmt.Sprintf("%s:sha256-%s.sig", reference.TrimNamed(named).String(), hexDigest)
+	if output, err := executor.Execute("skopeo", "copy", "--retry-times", "10", "--authfile", authFile,
+		"docker://"+signatureImage, "dir:"+filepath.Join(workDir, filepath.Base(tmpDir))); err != nil {
+		if strings.Contains(output, "manifest unknown") {
+			return "", &VerificationError{Image: image, Reason: fmt.Sprintf("no signature found for digest %s", digest)}
+		}
+		return "", fmt.Errorf("failed to retrieve signature %s: %w", signatureImage, err)
+	}
+
+	if err := verifySignatures(tmpDir, digest, publicKeys); err != nil {
+		return "", &VerificationError{Image: image, Reason: err.Error()}
+	}
+	return digest, nil
+}
+
+// verifySignatures checks that one of the signatures copied into dir by skopeo is a valid signature of the digest
+func verifySignatures(dir, digest string, publicKeys []crypto.PublicKey) error {
+	manifestRaw, err := os.ReadFile(filepath.Join(dir, "manifest.json"))
+	if err != nil {
+		return fmt.Errorf("failed to read signature manifest: %w", err)
+	}
+	sigManifest := manifest{}
+	if err := json.Unmarshal(manifestRaw, &sigManifest); err != nil {
+		return fmt.Errorf("failed to unmarshal signature manifest: %w", err)
+	}
+
+	var failures []string
+	for _, layer := range sigManifest.Layers {
+		encoded, ok := layer.Annotations[SignatureAnnotation]
+		if !ok {
+			continue
+		}
+		if err := verifySignature(dir, layer.Digest, encoded, digest, publicKeys); err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %s", layer.Digest, err.Error()))
+			continue
+		}
+		return nil
+	}
+
+	if len(failures) == 0 {
+		return fmt.Errorf("no signature found for digest %s", digest)
+	}
+	return fmt.Errorf("no valid signature found for digest %s: %s", digest, strings.Join(failures, "; "))
+}
+
+// verifySignature checks a single signature and the payload it signs
+func verifySignature(dir, payloadDigest, encodedSignature, digest string, publicKeys []crypto.PublicKey) error {
+	algorithm, hexDigest, found := strings.Cut(payloadDigest, ":")
+	if !found || algorithm != "sha256" {
+		return fmt.Errorf("unexpected payload digest")
+	}
+	payloadRaw, err := os.ReadFile(filepath.Join(dir, hexDigest))
+	if err != nil {
+		return fmt.Errorf("failed to read payload: %w", err)
+	}
+	sum := sha256.Sum256(payloadRaw)
+	if hex.EncodeToString(sum[:]) != hexDigest {
+		return fmt.Errorf("payload does not match its digest")
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(encodedSignature)
+	if err != nil {
+		return fmt.Errorf("failed to decode signature: %w", err)
+	}
+	if !verifyWithAnyKey(publicKeys, payloadRaw, sum[:], signature) {
+		return fmt.Errorf("signature does not match any of the trusted public keys")
+	}
+
+	// The payload is only trusted once its signature is verified
+	signed := payload{}
+	if err := json.Unmarshal(payloadRaw, &signed); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", err)
+	}
+	if signed.Critical.Type != SignatureType {
+		return fmt.Errorf("unexpected payload type %q", signed.Critical.Type)
+	}
+	if signed.Critical.Image.DockerManifestDigest != digest {
+		return fmt.Errorf("signature is for digest %s", signed.Critical.Image.DockerManifestDigest)
+	}
+	return nil
+}
+
+// verifyWithAnyKey checks the signature of the payload with each of the public keys
+func verifyWithAnyKey(publicKeys []crypto.PublicKey, payloadRaw, hashed, signature []byte) bool {
+	for _, key := range publicKeys {
+		switch k := key.(type) {
+		case *ecdsa.PublicKey:
+			if ecdsa.VerifyASN1(k, hashed, signature) {
+				return true
+			}
+		case *rsa.PublicKey:
+			if rsa.VerifyPKCS1v15(k, crypto.SHA256, hashed, signature) == nil {
+				return true
+			}
+		case ed25519.PublicKey:
+			if ed25519.Verify(k, payloadRaw, signature) {
+				return true
+			}
+		}
+	}
+	return false
+}
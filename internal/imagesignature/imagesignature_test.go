@@ -0,0 +1,207 @@
+package imagesignature
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeRegistry stands in for a registry, serving the digest of its images and the content of their signatures to the
+// skopeo commands run by Verify
+type fakeRegistry struct {
+	digests    map[string]string
+	signatures map[string]map[string][]byte
+}
+
+func newFakeRegistry() *fakeRegistry {
+	return &fakeRegistry{digests: map[string]string{}, signatures: map[string]map[string][]byte{}}
+}
+
+func (f *fakeRegistry) Execute(command string, args ...string) (string, error) {
+	if command != "skopeo" || len(args) == 0 {
+		return "", fmt.Errorf("unexpected command %s %v", command, args)
+	}
+	switch args[0] {
+	case "inspect":
+		image := strings.TrimPrefix(args[len(args)-1], "docker://")
+		if digest, ok := f.digests[image]; ok {
+			return digest, nil
+		}
+		return "reading manifest: manifest unknown", fmt.Errorf("exit status 1")
+	case "copy":
+		image := strings.TrimPrefix(args[len(args)-2], "docker://")
+		dir := strings.TrimPrefix(args[len(args)-1], "dir:")
+		files, ok := f.signatures[image]
+		if !ok {
+			return "reading manifest: manifest unknown", fmt.Errorf("exit status 1")
+		}
+		for name, content := range files {
+			if err := os.WriteFile(filepath.Join(dir, name), content, 0o600); err != nil {
+				return "", err
+			}
+		}
+		return "", nil
+	}
+	return "", fmt.Errorf("unexpected skopeo command %v", args)
+}
+
+func (f *fakeRegistry) ExecuteWithLiveLogger(command string, args ...string) (string, error) {
+	return f.Execute(command, args...)
+}
+
+// push adds an image, signed with the keys if any
+func (f *fakeRegistry) push(t *testing.T, repository, tag string, signedDigest string, keys ...*ecdsa.PrivateKey) string {
+	sum := sha256.Sum256([]byte(repository + ":" + tag))
+	digest := "sha256:" + hex.EncodeToString(sum[:])
+	f.digests[repository+":"+tag] = digest
+	if len(keys) == 0 {
+		return digest
+	}
+	if signedDigest == "" {
+		signedDigest = digest
+	}
+
+	files := map[string][]byte{}
+	type layer struct {
+		Digest      string            `json:"digest"`
+		Annotations map[string]string `json:"annotations"`
+	}
+	var layers []layer
+	for i, key := range keys {
+		payloadRaw := []byte(fmt.Sprintf(`{"critical":{"identity":{"docker-reference":"%s"},"image":{"docker-manifest-digest":"%s"},"type":"%s"},"optional":{"index":"%d"}}`,
+			repository, signedDigest, SignatureType, i))
+		payloadSum := sha256.Sum256(payloadRaw)
+		signature, err := ecdsa.SignASN1(rand.Reader, key, payloadSum[:])
+		assert.NoError(t, err)
+		files[hex.EncodeToString(payloadSum[:])] = payloadRaw
+		layers = append(layers, layer{
+			Digest:      "sha256:" + hex.EncodeToString(payloadSum[:]),
+			Annotations: map[string]string{SignatureAnnotation: base64.StdEncoding.EncodeToString(signature)},
+		})
+	}
+	manifestRaw, err := json.Marshal(map[string]any{"schemaVersion": 2, "layers": layers})
+	assert.NoError(t, err)
+	files["manifest.json"] = manifestRaw
+	f.signatures[fmt.Sprintf("%s:sha256-%s.sig", repository, strings.TrimPrefix(digest, "sha256:"))] = files
+	return digest
+}
+
+func generateKey(t *testing.T) (*ecdsa.PrivateKey, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	assert.NoError(t, err)
+	return key, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+}
+
+func TestParsePublicKeys(t *testing.T) {
+	_, pem1 := generateKey(t)
+	_, pem2 := generateKey(t)
+
+	keys, err := ParsePublicKeys(append(pem1, pem2...))
+	assert.NoError(t, err)
+	assert.Len(t, keys, 2)
+
+	_, err = ParsePublicKeys([]byte("not a key"))
+	assert.Error(t, err)
+}
+
+func TestVerify(t *testing.T) {
+	trusted, trustedPem := generateKey(t)
+	untrusted, _ := generateKey(t)
+	publicKeys, err := ParsePublicKeys(trustedPem)
+	assert.NoError(t, err)
+
+	registry := newFakeRegistry()
+	signedDigest := registry.push(t, "quay.io/example/seed", "signed", "", trusted)
+	registry.push(t, "quay.io/example/seed", "unsigned", "")
+	registry.push(t, "quay.io/example/seed", "untrusted", "", untrusted)
+	multiDigest := registry.push(t, "quay.io/example/seed", "multi", "", untrusted, trusted)
+	registry.push(t, "quay.io/example/seed", "other-digest", signedDigest, trusted)
+
+	tests := []struct {
+		name               string
+		image              string
+		expectedDigest     string
+		expectVerification bool
+		expectedErr        string
+	}{
+		{
+			name:           "signed with a trusted key",
+			image:          "quay.io/example/seed:signed",
+			expectedDigest: signedDigest,
+		},
+		{
+			name:           "one of the signatures is from a trusted key",
+			image:          "quay.io/example/seed:multi",
+			expectedDigest: multiDigest,
+		},
+		{
+			name:               "not signed",
+			image:              "quay.io/example/seed:unsigned",
+			expectVerification: true,
+			expectedErr:        "no signature found",
+		},
+		{
+			name:               "signed with an untrusted key",
+			image:              "quay.io/example/seed:untrusted",
+			expectVerification: true,
+			expectedErr:        "signature does not match any of the trusted public keys",
+		},
+		{
+			name:               "signature of another digest",
+			image:              "quay.io/example/seed:other-digest",
+			expectVerification: true,
+			expectedErr:        "signature is for digest " + signedDigest,
+		},
+		{
+			name:        "image not found",
+			image:       "quay.io/example/seed:missing",
+			expectedErr: "failed to get the manifest digest",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			digest, err := Verify(registry, tc.image, "/var/lib/kubelet/config.json", t.TempDir(), publicKeys)
+			if tc.expectedErr != "" {
+				assert.ErrorContains(t, err, tc.expectedErr)
+				assert.Equal(t, tc.expectVerification, IsVerificationError(err))
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedDigest, digest)
+		})
+	}
+}
+
+func TestVerifyTamperedPayload(t *testing.T) {
+	trusted, trustedPem := generateKey(t)
+	publicKeys, err := ParsePublicKeys(trustedPem)
+	assert.NoError(t, err)
+
+	registry := newFakeRegistry()
+	digest := registry.push(t, "quay.io/example/seed", "signed", "", trusted)
+	files := registry.signatures["quay.io/example/seed:sha256-"+strings.TrimPrefix(digest, "sha256:")+".sig"]
+	for name := range files {
+		if name != "manifest.json" {
+			files[name] = []byte(`{}`)
+		}
+	}
+
+	_, err = Verify(registry, "quay.io/example/seed:signed", "/var/lib/kubelet/config.json", t.TempDir(), publicKeys)
+	assert.ErrorContains(t, err, "payload does not match its digest")
+	assert.True(t, IsVerificationError(err))
+}
@@ -0,0 +1,296 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inventory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/coreos/go-semver/semver"
+	ibuv1 "github.com/openshift-kni/lifecycle-agent/api/imagebasedupgrade/v1"
+	configv1 "github.com/openshift/api/config/v1"
+	operatorsv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"github.com/samber/lo"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// +kubebuilder:rbac:groups=operators.coreos.com,resources=clusterserviceversions,verbs=list;watch
+// +kubebuilder:rbac:groups=config.openshift.io,resources=clusteroperators,verbs=list;watch
+// +kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions,verbs=list;watch
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=list;watch
+// +kubebuilder:rbac:groups=apps,resources=deployments;statefulsets;daemonsets,verbs=list;watch
+
+const (
+	ChangeAdded   = "Added"
+	ChangeRemoved = "Removed"
+	ChangeChanged = "Changed"
+
+	// copiedCSVLabel is set by OLM on the copies of the CSVs of operators watching all namespaces
+	copiedCSVLabel = "olm.copiedFrom"
+)
+
+// Snapshot is the inventory of the cluster at a point in time
+type Snapshot struct {
+	// ClusterServiceVersions is keyed by the namespace and the name of the CSVs without their version
+	ClusterServiceVersions map[string]ClusterServiceVersion `json:"clusterServiceVersions"`
+	// ClusterOperators is keyed by name
+	ClusterOperators map[string]ClusterOperator `json:"clusterOperators"`
+	// CustomResourceDefinitions is the sorted list of CRD names
+	CustomResourceDefinitions []string `json:"customResourceDefinitions"`
+	// Namespaces is the sorted list of namespace names
+	Namespaces []string `json:"namespaces"`
+	// Workloads is keyed by namespace
+	Workloads map[string]Workloads `json:"workloads"`
+}
+
+type ClusterServiceVersion struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Phase   string `json:"phase"`
+}
+
+type ClusterOperator struct {
+	Version   string `json:"version"`
+	Available bool   `json:"available"`
+}
+
+// Workloads sums the Deployments, StatefulSets and DaemonSets of a namespace
+type Workloads struct {
+	Count    int32 `json:"count"`
+	Replicas int32 `json:"replicas"`
+	Ready    int32 `json:"ready"`
+}
+
+// Change is a difference between two snapshots
+type Change struct {
+	Category   ibuv1.InventoryCategory `json:"category"`
+	Type       string                  `json:"type"`
+	Name       string                  `json:"name"`
+	Before     string                  `json:"before,omitempty"`
+	After      string                  `json:"after,omitempty"`
+	Regression bool                    `json:"regression,omitempty"`
+}
+
+func (c Change) String() string {
+	msg := fmt.Sprintf("%s %s %s", c.Category, c.Name, strings.ToLower(c.Type))
+	switch {
+	case c.Before != "" && c.After != "":
+		msg += fmt.Sprintf(": %s -> %s", c.Before, c.After)
+	case c.Before != "":
+		msg += ": " + c.Before
+	case c.After != "":
+		msg += ": " + c.After
+	}
+	return msg
+}
+
+// TakeSnapshot lists the CSVs, ClusterOperators, CRDs, namespaces and workloads of the cluster
+func TakeSnapshot(ctx context.Context, c client.Reader) (*Snapshot, error) {
+	snapshot := &Snapshot{
+		ClusterServiceVersions: map[string]ClusterServiceVersion{},
+		ClusterOperators:       map[string]ClusterOperator{},
+		Workloads:              map[string]Workloads{},
+	}
+
+	csvs := &operatorsv1alpha1.ClusterServiceVersionList{}
+	if err := c.List(ctx, csvs); err != nil {
+		return nil, fmt.Errorf("failed to list ClusterServiceVersions: %w", err)
+	}
+	for _, csv := range csvs.Items {
+		if _, copied := csv.GetLabels()[copiedCSVLabel]; copied {
+			continue
+		}
+		version := csv.Spec.Version.String()
+		key := csv.Namespace + "/" + strings.TrimSuffix(csv.Name, ".v"+version)
+		snapshot.ClusterServiceVersions[key] = ClusterServiceVersion{
+			Name:    csv.Name,
+			Version: version,
+			Phase:   string(csv.Status.Phase),
+		}
+	}
+
+	clusterOperators := &configv1.ClusterOperatorList{}
+	if err := c.List(ctx, clusterOperators); err != nil {
+		return nil, fmt.Errorf("failed to list ClusterOperators: %w", err)
+	}
+	for _, co := range clusterOperators.Items {
+		operator := ClusterOperator{}
+		if version, found := lo.Find(co.Status.Versions, func(v configv1.OperandVersion) bool { return v.Name == "operator" }); found {
+			operator.Version = version.Version
+		}
+		_, operator.Available = lo.Find(co.Status.Conditions, func(c configv1.ClusterOperatorStatusCondition) bool {
+			return c.Type == configv1.OperatorAvailable && c.Status == configv1.ConditionTrue
+		})
+		snapshot.ClusterOperators[co.Name] = operator
+	}
+
+	crds := &apiextensionsv1.CustomResourceDefinitionList{}
+	if err := c.List(ctx, crds); err != nil {
+		return nil, fmt.Errorf("failed to list CustomResourceDefinitions: %w", err)
+	}
+	for _, crd := range crds.Items {
+		snapshot.CustomResourceDefinitions = append(snapshot.CustomResourceDefinitions, crd.Name)
+	}
+	sort.Strings(snapshot.CustomResourceDefinitions)
+
+	namespaces := &corev1.NamespaceList{}
+	if err := c.List(ctx, namespaces); err != nil {
+		return nil, fmt.Errorf("failed to list Namespaces: %w", err)
+	}
+	for _, ns := range namespaces.Items {
+		snapshot.Namespaces = append(snapshot.Namespaces, ns.Name)
+	}
+	sort.Strings(snapshot.Namespaces)
+
+	addWorkload := func(namespace string, replicas, ready int32) {
+		workloads := snapshot.Workloads[namespace]
+		workloads.Count++
+		workloads.Replicas += replicas
+		workloads.Ready += ready
+		snapshot.Workloads[namespace] = workloads
+	}
+	deployments := &appsv1.DeploymentList{}
+	if err := c.List(ctx, deployments); err != nil {
+		return nil, fmt.Errorf("failed to list Deployments: %w", err)
+	}
+	for _, d := range deployments.Items {
+		addWorkload(d.Namespace, lo.FromPtrOr(d.Spec.Replicas, 1), d.Status.ReadyReplicas)
+	}
+	statefulSets := &appsv1.StatefulSetList{}
+	if err := c.List(ctx, statefulSets); err != nil {
+		return nil, fmt.Errorf("failed to list StatefulSets: %w", err)
+	}
+	for _, s := range statefulSets.Items {
+		addWorkload(s.Namespace, lo.FromPtrOr(s.Spec.Replicas, 1), s.Status.ReadyReplicas)
+	}
+	daemonSets := &appsv1.DaemonSetList{}
+	if err := c.List(ctx, daemonSets); err != nil {
+		return nil, fmt.Errorf("failed to list DaemonSets: %w", err)
+	}
+	for _, d := range daemonSets.Items {
+		addWorkload(d.Namespace, d.Status.DesiredNumberScheduled, d.Status.NumberReady)
+	}
+
+	return snapshot, nil
+}
+
+// Compare returns the changes from the before snapshot to the after snapshot, grouped by category and sorted by name
+func Compare(before, after *Snapshot) []Change {
+	var changes []Change
+
+	changes = append(changes, compareMaps(ibuv1.InventoryCategories.ClusterServiceVersions,
+		before.ClusterServiceVersions, after.ClusterServiceVersions,
+		func(csv ClusterServiceVersion) string { return fmt.Sprintf("%s (%s)", csv.Name, csv.Phase) },
+		func(b, a ClusterServiceVersion) bool {
+			return isDowngrade(b.Version, a.Version) ||
+				b.Phase == string(operatorsv1alpha1.CSVPhaseSucceeded) && a.Phase != string(operatorsv1alpha1.CSVPhaseSucceeded)
+		})...)
+
+	changes = append(changes, compareMaps(ibuv1.InventoryCategories.ClusterOperators,
+		before.ClusterOperators, after.ClusterOperators,
+		func(co ClusterOperator) string {
+			if co.Available {
+				return co.Version + " (available)"
+			}
+			return co.Version + " (unavailable)"
+		},
+		func(b, a ClusterOperator) bool { return b.Available && !a.Available })...)
+
+	changes = append(changes, compareLists(ibuv1.InventoryCategories.CustomResourceDefinitions,
+		before.CustomResourceDefinitions, after.CustomResourceDefinitions)...)
+	changes = append(changes, compareLists(ibuv1.InventoryCategories.Namespaces, before.Namespaces, after.Namespaces)...)
+
+	changes = append(changes, compareMaps(ibuv1.InventoryCategories.Workloads,
+		before.Workloads, after.Workloads,
+		func(w Workloads) string {
+			return fmt.Sprintf("%d workloads, %d/%d replicas ready", w.Count, w.Ready, w.Replicas)
+		},
+		func(b, a Workloads) bool { return a.Ready < b.Ready })...)
+
+	return changes
+}
+
+// Regressions returns the regressions of the given categories
+func Regressions(changes []Change, categories []ibuv1.InventoryCategory) []Change {
+	return lo.Filter(changes, func(c Change, _ int) bool {
+		return c.Regression && lo.Contains(categories, c.Category)
+	})
+}
+
+// compareMaps compares the items of a category keyed by name. Removed items are regressions, and changed items are
+// regressions if regressed returns true.
+func compareMaps[T comparable](category ibuv1.InventoryCategory, before, after map[string]T, describe func(T) string,
+	regressed func(before, after T) bool) []Change {
+	var changes []Change
+	for _, name := range sortedKeys(before, after) {
+		b, inBefore := before[name]
+		a, inAfter := after[name]
+		switch {
+		case !inAfter:
+			changes = append(changes, Change{Category: category, Type: ChangeRemoved, Name: name, Before: describe(b), Regression: true})
+		case !inBefore:
+			changes = append(changes, Change{Category: category, Type: ChangeAdded, Name: name, After: describe(a)})
+		case a != b:
+			changes = append(changes, Change{Category: category, Type: ChangeChanged, Name: name,
+				Before: describe(b), After: describe(a), Regression: regressed(b, a)})
+		}
+	}
+	return changes
+}
+
+// compareLists compares the sorted names of a category. Removed names are regressions.
+func compareLists(category ibuv1.InventoryCategory, before, after []string) []Change {
+	var changes []Change
+	removed, added := lo.Difference(before, after)
+	for _, name := range removed {
+		changes = append(changes, Change{Category: category, Type: ChangeRemoved, Name: name, Regression: true})
+	}
+	for _, name := range added {
+		changes = append(changes, Change{Category: category, Type: ChangeAdded, Name: name})
+	}
+	sort.SliceStable(changes, func(i, j int) bool { return changes[i].Name < changes[j].Name })
+	return changes
+}
+
+func sortedKeys[T any](maps ...map[string]T) []string {
+	var keys []string
+	for _, m := range maps {
+		keys = append(keys, lo.Keys(m)...)
+	}
+	keys = lo.Uniq(keys)
+	sort.Strings(keys)
+	return keys
+}
+
+// isDowngrade returns whether the after version is lower than the before version. Versions that are not semantic
+// versions are never downgrades.
+func isDowngrade(before, after string) bool {
+	b, err := semver.NewVersion(before)
+	if err != nil {
+		return false
+	}
+	a, err := semver.NewVersion(after)
+	if err != nil {
+		return false
+	}
+	return a.LessThan(*b)
+}
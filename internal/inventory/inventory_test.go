@@ -0,0 +1,147 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inventory
+
+import (
+	"context"
+	"testing"
+
+	ibuv1 "github.com/openshift-kni/lifecycle-agent/api/imagebasedupgrade/v1"
+	configv1 "github.com/openshift/api/config/v1"
+	operatorsv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newTestScheme(t *testing.T) *runtime.Scheme {
+	s := runtime.NewScheme()
+	for _, addToScheme := range []func(*runtime.Scheme) error{
+		corev1.AddToScheme, appsv1.AddToScheme, apiextensionsv1.AddToScheme, configv1.AddToScheme, operatorsv1alpha1.AddToScheme,
+	} {
+		assert.NoError(t, addToScheme(s))
+	}
+	return s
+}
+
+func newCSV(namespace, name, csvVersion string, labels map[string]string) *operatorsv1alpha1.ClusterServiceVersion {
+	csv := &operatorsv1alpha1.ClusterServiceVersion{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: labels},
+		Status:     operatorsv1alpha1.ClusterServiceVersionStatus{Phase: operatorsv1alpha1.CSVPhaseSucceeded},
+	}
+	_ = csv.Spec.Version.UnmarshalJSON([]byte(`"` + csvVersion + `"`))
+	return csv
+}
+
+func TestTakeSnapshot(t *testing.T) {
+	c := fake.NewClientBuilder().WithScheme(newTestScheme(t)).WithObjects(
+		newCSV("openshift-storage", "lvms-operator.v4.16.2", "4.16.2", nil),
+		newCSV("my-app", "lvms-operator.v4.16.2", "4.16.2", map[string]string{copiedCSVLabel: "openshift-storage"}),
+		&configv1.ClusterOperator{
+			ObjectMeta: metav1.ObjectMeta{Name: "dns"},
+			Status: configv1.ClusterOperatorStatus{
+				Versions:   []configv1.OperandVersion{{Name: "operator", Version: "4.16.5"}},
+				Conditions: []configv1.ClusterOperatorStatusCondition{{Type: configv1.OperatorAvailable, Status: configv1.ConditionTrue}},
+			},
+		},
+		&apiextensionsv1.CustomResourceDefinition{ObjectMeta: metav1.ObjectMeta{Name: "widgets.example.com"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "my-app"}},
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "my-app"},
+			Spec:       appsv1.DeploymentSpec{Replicas: lo.ToPtr(int32(2))},
+			Status:     appsv1.DeploymentStatus{ReadyReplicas: 2},
+		},
+		&appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "my-app"},
+			Status:     appsv1.StatefulSetStatus{ReadyReplicas: 0},
+		},
+	).Build()
+
+	snapshot, err := TakeSnapshot(context.Background(), c)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]ClusterServiceVersion{
+		"openshift-storage/lvms-operator": {Name: "lvms-operator.v4.16.2", Version: "4.16.2", Phase: "Succeeded"},
+	}, snapshot.ClusterServiceVersions)
+	assert.Equal(t, map[string]ClusterOperator{"dns": {Version: "4.16.5", Available: true}}, snapshot.ClusterOperators)
+	assert.Equal(t, []string{"widgets.example.com"}, snapshot.CustomResourceDefinitions)
+	assert.Equal(t, []string{"my-app"}, snapshot.Namespaces)
+	assert.Equal(t, map[string]Workloads{"my-app": {Count: 2, Replicas: 3, Ready: 2}}, snapshot.Workloads)
+}
+
+func TestCompare(t *testing.T) {
+	before := &Snapshot{
+		ClusterServiceVersions: map[string]ClusterServiceVersion{
+			"openshift-storage/lvms-operator": {Name: "lvms-operator.v4.16.2", Version: "4.16.2", Phase: "Succeeded"},
+			"my-app/widget-operator":          {Name: "widget-operator.v1.2.0", Version: "1.2.0", Phase: "Succeeded"},
+			"my-app/gadget-operator":          {Name: "gadget-operator.v2.0.0", Version: "2.0.0", Phase: "Succeeded"},
+		},
+		ClusterOperators: map[string]ClusterOperator{
+			"dns":     {Version: "4.16.5", Available: true},
+			"console": {Version: "4.16.5", Available: true},
+		},
+		CustomResourceDefinitions: []string{"gadgets.example.com", "widgets.example.com"},
+		Namespaces:                []string{"my-app", "old-app"},
+		Workloads:                 map[string]Workloads{"my-app": {Count: 2, Replicas: 3, Ready: 3}},
+	}
+	after := &Snapshot{
+		ClusterServiceVersions: map[string]ClusterServiceVersion{
+			"openshift-storage/lvms-operator": {Name: "lvms-operator.v4.17.0", Version: "4.17.0", Phase: "Succeeded"},
+			"my-app/widget-operator":          {Name: "widget-operator.v1.1.0", Version: "1.1.0", Phase: "Succeeded"},
+		},
+		ClusterOperators: map[string]ClusterOperator{
+			"dns":     {Version: "4.17.1", Available: true},
+			"console": {Version: "4.17.1", Available: false},
+		},
+		CustomResourceDefinitions: []string{"gizmos.example.com", "widgets.example.com"},
+		Namespaces:                []string{"my-app"},
+		Workloads:                 map[string]Workloads{"my-app": {Count: 2, Replicas: 3, Ready: 1}},
+	}
+
+	changes := Compare(before, after)
+	assert.Equal(t, []string{
+		"ClusterServiceVersions my-app/gadget-operator removed: gadget-operator.v2.0.0 (Succeeded)",
+		"ClusterServiceVersions my-app/widget-operator changed: widget-operator.v1.2.0 (Succeeded) -> widget-operator.v1.1.0 (Succeeded)",
+		"ClusterServiceVersions openshift-storage/lvms-operator changed: lvms-operator.v4.16.2 (Succeeded) -> lvms-operator.v4.17.0 (Succeeded)",
+		"ClusterOperators console changed: 4.16.5 (available) -> 4.17.1 (unavailable)",
+		"ClusterOperators dns changed: 4.16.5 (available) -> 4.17.1 (available)",
+		"CustomResourceDefinitions gadgets.example.com removed",
+		"CustomResourceDefinitions gizmos.example.com added",
+		"Namespaces old-app removed",
+		"Workloads my-app changed: 2 workloads, 3/3 replicas ready -> 2 workloads, 1/3 replicas ready",
+	}, lo.Map(changes, func(c Change, _ int) string { return c.String() }))
+
+	regressions := Regressions(changes, []ibuv1.InventoryCategory{
+		ibuv1.InventoryCategories.ClusterServiceVersions, ibuv1.InventoryCategories.ClusterOperators,
+	})
+	assert.Equal(t, []string{
+		"ClusterServiceVersions my-app/gadget-operator removed: gadget-operator.v2.0.0 (Succeeded)",
+		"ClusterServiceVersions my-app/widget-operator changed: widget-operator.v1.2.0 (Succeeded) -> widget-operator.v1.1.0 (Succeeded)",
+		"ClusterOperators console changed: 4.16.5 (available) -> 4.17.1 (unavailable)",
+	}, lo.Map(regressions, func(c Change, _ int) string { return c.String() }))
+
+	regressions = Regressions(changes, []ibuv1.InventoryCategory{
+		ibuv1.InventoryCategories.CustomResourceDefinitions, ibuv1.InventoryCategories.Namespaces, ibuv1.InventoryCategories.Workloads,
+	})
+	assert.Len(t, regressions, 3)
+
+	assert.Empty(t, Compare(before, before))
+}
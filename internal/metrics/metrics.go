@@ -0,0 +1,177 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package metrics publishes the ImageBasedUpgrade and IPConfig progress on the manager's metrics endpoint
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	ibuv1 "github.com/openshift-kni/lifecycle-agent/api/imagebasedupgrade/v1"
+	ipcv1 "github.com/openshift-kni/lifecycle-agent/api/ipconfig/v1"
+)
+
+const (
+	namespace    = "lca"
+	subsystemIBU = "ibu"
+	subsystemIPC = "ipc"
+
+	labelStage  = "stage"
+	labelPhase  = "phase"
+	labelReason = "reason"
+)
+
+// durationBuckets covers durations from 30 seconds up to about 4 hours
+var durationBuckets = prometheus.ExponentialBuckets(30, 2, 10)
+
+var (
+	ibuCurrentStage = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: subsystemIBU,
+		Name:      "current_stage",
+		Help:      "The desired stage of the ImageBasedUpgrade, set to 1 for the current stage and 0 for the others.",
+	}, []string{labelStage})
+	ibuStageDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: subsystemIBU,
+		Name:      "stage_duration_seconds",
+		Help:      "Time taken by the ImageBasedUpgrade stages to complete successfully.",
+		Buckets:   durationBuckets,
+	}, []string{labelStage})
+	ibuPhaseDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: subsystemIBU,
+		Name:      "phase_duration_seconds",
+		Help:      "Time taken by the ImageBasedUpgrade stage phases to complete successfully.",
+		Buckets:   durationBuckets,
+	}, []string{labelStage, labelPhase})
+	ibuFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: subsystemIBU,
+		Name:      "failures_total",
+		Help:      "Number of ImageBasedUpgrade stage failures by condition reason.",
+	}, []string{labelStage, labelReason})
+	ibuRollbacks = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: subsystemIBU,
+		Name:      "rollbacks_total",
+		Help:      "Number of ImageBasedUpgrade rollbacks started.",
+	})
+
+	ipcCurrentStage = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: subsystemIPC,
+		Name:      "current_stage",
+		Help:      "The desired stage of the IPConfig, set to 1 for the current stage and 0 for the others.",
+	}, []string{labelStage})
+	ipcStageDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: subsystemIPC,
+		Name:      "stage_duration_seconds",
+		Help:      "Time taken by the IPConfig stages to complete successfully.",
+		Buckets:   durationBuckets,
+	}, []string{labelStage})
+	ipcPhaseDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: subsystemIPC,
+		Name:      "phase_duration_seconds",
+		Help:      "Time taken by the IPConfig stage phases to complete successfully.",
+		Buckets:   durationBuckets,
+	}, []string{labelStage, labelPhase})
+	ipcFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: subsystemIPC,
+		Name:      "failures_total",
+		Help:      "Number of IPConfig stage failures by condition reason.",
+	}, []string{labelStage, labelReason})
+	ipcRollbacks = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: subsystemIPC,
+		Name:      "rollbacks_total",
+		Help:      "Number of IPConfig rollbacks started.",
+	})
+)
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(
+		ibuCurrentStage, ibuStageDuration, ibuPhaseDuration, ibuFailures, ibuRollbacks,
+		ipcCurrentStage, ipcStageDuration, ipcPhaseDuration, ipcFailures, ipcRollbacks,
+	)
+}
+
+// RecordIBUStatus updates the IBU current stage from the status written to the cluster
+func RecordIBUStatus(ibu *ibuv1.ImageBasedUpgrade) {
+	for _, stage := range []ibuv1.ImageBasedUpgradeStage{ibuv1.Stages.Idle, ibuv1.Stages.Prep, ibuv1.Stages.Upgrade, ibuv1.Stages.Rollback} {
+		value := 0.0
+		if stage == ibu.Spec.Stage {
+			value = 1
+		}
+		ibuCurrentStage.WithLabelValues(string(stage)).Set(value)
+	}
+}
+
+// RecordIBUFailure counts an IBU stage failing with the given condition reason
+func RecordIBUFailure(stage ibuv1.ImageBasedUpgradeStage, reason string) {
+	ibuFailures.WithLabelValues(string(stage), reason).Inc()
+}
+
+// RecordIBURollback counts an IBU rollback being started
+func RecordIBURollback() {
+	ibuRollbacks.Inc()
+}
+
+// ObserveIBUStageDuration records the duration of a successfully completed IBU stage
+func ObserveIBUStageDuration(stage ibuv1.ImageBasedUpgradeStage, start, completion metav1.Time) {
+	ibuStageDuration.WithLabelValues(string(stage)).Observe(completion.Sub(start.Time).Seconds())
+}
+
+// ObserveIBUPhaseDuration records the duration of a successfully completed IBU stage phase
+func ObserveIBUPhaseDuration(stage ibuv1.ImageBasedUpgradeStage, phase string, start, completion metav1.Time) {
+	ibuPhaseDuration.WithLabelValues(string(stage), phase).Observe(completion.Sub(start.Time).Seconds())
+}
+
+// RecordIPCStatus updates the IPConfig current stage from the status written to the cluster
+func RecordIPCStatus(ipc *ipcv1.IPConfig) {
+	for _, stage := range []ipcv1.IPConfigStage{ipcv1.IPStages.Idle, ipcv1.IPStages.Config, ipcv1.IPStages.Rollback} {
+		value := 0.0
+		if stage == ipc.Spec.Stage {
+			value = 1
+		}
+		ipcCurrentStage.WithLabelValues(string(stage)).Set(value)
+	}
+}
+
+// RecordIPCFailure counts an IPConfig stage failing with the given condition reason
+func RecordIPCFailure(stage ipcv1.IPConfigStage, reason string) {
+	ipcFailures.WithLabelValues(string(stage), reason).Inc()
+}
+
+// RecordIPCRollback counts an IPConfig rollback being started
+func RecordIPCRollback() {
+	ipcRollbacks.Inc()
+}
+
+// ObserveIPCStageDuration records the duration of a successfully completed IPConfig stage
+func ObserveIPCStageDuration(stage ipcv1.IPConfigStage, start, completion metav1.Time) {
+	ipcStageDuration.WithLabelValues(string(stage)).Observe(completion.Sub(start.Time).Seconds())
+}
+
+// ObserveIPCPhaseDuration records the duration of a successfully completed IPConfig stage phase
+func ObserveIPCPhaseDuration(stage ipcv1.IPConfigStage, phase string, start, completion metav1.Time) {
+	ipcPhaseDuration.WithLabelValues(string(stage), phase).Observe(completion.Sub(start.Time).Seconds())
+}
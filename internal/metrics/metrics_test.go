@@ -0,0 +1,68 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ibuv1 "github.com/openshift-kni/lifecycle-agent/api/imagebasedupgrade/v1"
+	ipcv1 "github.com/openshift-kni/lifecycle-agent/api/ipconfig/v1"
+)
+
+func metricValue(t *testing.T, m prometheus.Metric) *dto.Metric {
+	out := &dto.Metric{}
+	assert.NoError(t, m.Write(out))
+	return out
+}
+
+func TestRecordIBUStatus(t *testing.T) {
+	ibu := &ibuv1.ImageBasedUpgrade{Spec: ibuv1.ImageBasedUpgradeSpec{Stage: ibuv1.Stages.Upgrade}}
+	RecordIBUStatus(ibu)
+	assert.Equal(t, 1.0, metricValue(t, ibuCurrentStage.WithLabelValues("Upgrade")).GetGauge().GetValue())
+	assert.Equal(t, 0.0, metricValue(t, ibuCurrentStage.WithLabelValues("Prep")).GetGauge().GetValue())
+
+	ibu.Spec.Stage = ibuv1.Stages.Rollback
+	RecordIBUStatus(ibu)
+	assert.Equal(t, 1.0, metricValue(t, ibuCurrentStage.WithLabelValues("Rollback")).GetGauge().GetValue())
+	assert.Equal(t, 0.0, metricValue(t, ibuCurrentStage.WithLabelValues("Upgrade")).GetGauge().GetValue())
+}
+
+func TestRecordIBUFailureAndRollback(t *testing.T) {
+	failures := ibuFailures.WithLabelValues("Upgrade", "Failed")
+	before := metricValue(t, failures).GetCounter().GetValue()
+	rollbacksBefore := metricValue(t, ibuRollbacks).GetCounter().GetValue()
+
+	RecordIBUFailure(ibuv1.Stages.Upgrade, "Failed")
+	RecordIBURollback()
+
+	assert.Equal(t, before+1, metricValue(t, failures).GetCounter().GetValue())
+	assert.Equal(t, rollbacksBefore+1, metricValue(t, ibuRollbacks).GetCounter().GetValue())
+}
+
+func TestRecordIPCStatus(t *testing.T) {
+	ipc := &ipcv1.IPConfig{Spec: ipcv1.IPConfigSpec{Stage: ipcv1.IPStages.Config}}
+	failures := ipcFailures.WithLabelValues("Config", "TimedOut")
+	before := metricValue(t, failures).GetCounter().GetValue()
+
+	RecordIPCStatus(ipc)
+	RecordIPCFailure(ipcv1.IPStages.Config, "TimedOut")
+
+	assert.Equal(t, before+1, metricValue(t, failures).GetCounter().GetValue())
+	assert.Equal(t, 1.0, metricValue(t, ipcCurrentStage.WithLabelValues("Config")).GetGauge().GetValue())
+}
+
+func TestObservePhaseDuration(t *testing.T) {
+	start := metav1.NewTime(time.Date(2024, time.June, 1, 3, 0, 0, 0, time.UTC))
+	completion := metav1.NewTime(start.Add(90 * time.Second))
+
+	ObserveIBUPhaseDuration(ibuv1.Stages.Prep, "Precache", start, completion)
+
+	histogram := ibuPhaseDuration.WithLabelValues("Prep", "Precache").(prometheus.Metric)
+	value := metricValue(t, histogram).GetHistogram()
+	assert.Equal(t, uint64(1), value.GetSampleCount())
+	assert.Equal(t, 90.0, value.GetSampleSum())
+}
@@ -24,6 +24,7 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
 	"k8s.io/apimachinery/pkg/runtime"
 
@@ -291,14 +292,38 @@ func removePrecacheFinalizer(ctx context.Context, c client.Client) error {
 	return nil
 }
 
+// readPrecacheStatusFile reads the progress persisted by the precaching job. It returns nil if there is no progress to
+// read yet.
+func readPrecacheStatusFile() (*Progress, error) {
+	data, err := os.ReadFile(common.PathOutsideChroot(StatusFile))
+	if err != nil {
+		return nil, nil
+	}
+	curP := &Progress{}
+	if err := json.Unmarshal(data, curP); err != nil {
+		return nil, fmt.Errorf("could not unmarshal precache status file: %w", err)
+	}
+	return curP, nil
+}
+
 // GetPrecacheStatusFileContent try to read the content for additional in progress status msg
 func GetPrecacheStatusFileContent() string {
-	if data, err := os.ReadFile(common.PathOutsideChroot(StatusFile)); err == nil {
-		curP := Progress{}
-		if err := json.Unmarshal(data, &curP); err != nil {
-			return "could not unmarshal precache status file"
-		}
-		return fmt.Sprintf("total: %d (pulled: %d, failed: %d)", curP.Total, curP.Pulled, curP.Failed)
+	curP, err := readPrecacheStatusFile()
+	if err != nil {
+		return "could not unmarshal precache status file"
+	}
+	if curP == nil {
+		return "No precache status file to read yet."
+	}
+	return fmt.Sprintf("total: %d (pulled: %d, failed: %d)", curP.Total, curP.Pulled, curP.Failed)
+}
+
+// GetPrecacheStatus returns the progress of the precaching job for the IBU status, or nil if the precaching job has
+// not reported any progress yet
+func GetPrecacheStatus(now time.Time) *ibuv1.PrecacheStatus {
+	curP, err := readPrecacheStatusFile()
+	if err != nil || curP == nil {
+		return nil
 	}
-	return "No precache status file to read yet."
+	return curP.ToStatus(now)
 }
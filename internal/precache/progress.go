@@ -19,25 +19,38 @@ package precache
 import (
 	"encoding/json"
 	"os"
+	"sort"
 	"sync"
+	"time"
 
+	ibuv1 "github.com/openshift-kni/lifecycle-agent/api/imagebasedupgrade/v1"
 	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// MaxPullErrorLength is the maximum length of the pull error recorded for each failed image
+const MaxPullErrorLength = 512
+
 // Progress represents the progress tracking data for the precaching job
 type Progress struct {
-	Total          int      `json:"total"`
-	Pulled         int      `json:"pulled"`
-	Failed         int      `json:"failed"`
-	FailedPullList []string `json:"failed_pulls"`
-	mux            sync.Mutex
+	Total            int               `json:"total"`
+	Pulled           int               `json:"pulled"`
+	Failed           int               `json:"failed"`
+	FailedPullList   []string          `json:"failed_pulls"`
+	FailedPullErrors map[string]string `json:"failed_pull_errors,omitempty"`
+	BytesDownloaded  int64             `json:"bytes_downloaded"`
+	StartTime        time.Time         `json:"start_time"`
+	mux              sync.Mutex
 }
 
-func (p *Progress) Update(success bool, image string) {
+// Update records the result of an image pull, along with the number of bytes downloaded so far
+func (p *Progress) Update(image string, bytesDownloaded int64, pullErr error) {
 	p.mux.Lock()
 	defer p.mux.Unlock()
 
-	if success {
+	// The images are pulled concurrently, keep the highest count in case the updates are not in order
+	p.BytesDownloaded = max(p.BytesDownloaded, bytesDownloaded)
+	if pullErr == nil {
 		p.Pulled++
 	} else {
 		p.Failed++
@@ -45,6 +58,14 @@ func (p *Progress) Update(success bool, image string) {
 			p.FailedPullList = []string{}
 		}
 		p.FailedPullList = append(p.FailedPullList, image)
+		if p.FailedPullErrors == nil {
+			p.FailedPullErrors = map[string]string{}
+		}
+		msg := pullErr.Error()
+		if len(msg) > MaxPullErrorLength {
+			msg = msg[:MaxPullErrorLength]
+		}
+		p.FailedPullErrors[image] = msg
 	}
 }
 
@@ -52,14 +73,48 @@ func (p *Progress) Log() {
 	logrus.Infof("Total Images: %d", p.Total)
 	logrus.Infof("Images Pulled Successfully: %d", p.Pulled)
 	logrus.Infof("Images Failed to Pull: %d", p.Failed)
+	logrus.Infof("Bytes Downloaded: %d", p.BytesDownloaded)
 	for _, img := range p.FailedPullList {
 		logrus.Infof("failed: %s", img)
 	}
 }
 
 func (p *Progress) Persist(filename string) {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
 	data, _ := json.Marshal(p)
 	if err := os.WriteFile(filename, data, 0o600); err != nil {
 		logrus.Errorf("Failed to update progress file for precaching, err: %v", err)
 	}
 }
+
+// ToStatus returns the progress as reported in the IBU status. The completion time is estimated from the average
+// time taken to process the images so far.
+func (p *Progress) ToStatus(now time.Time) *ibuv1.PrecacheStatus {
+	status := &ibuv1.PrecacheStatus{
+		Total:           p.Total,
+		Pulled:          p.Pulled,
+		Failed:          p.Failed,
+		BytesDownloaded: p.BytesDownloaded,
+	}
+
+	processed := p.Pulled + p.Failed
+	if processed > 0 && processed < p.Total && !p.StartTime.IsZero() {
+		perImage := now.Sub(p.StartTime) / time.Duration(processed)
+		eta := metav1.NewTime(now.Add(perImage * time.Duration(p.Total-processed)).Truncate(time.Second))
+		status.EstimatedCompletionTime = &eta
+	}
+
+	for _, image := range p.FailedPullList {
+		status.FailedImages = append(status.FailedImages, ibuv1.PrecacheFailedImage{
+			Image: image,
+			Error: p.FailedPullErrors[image],
+		})
+	}
+	sort.Slice(status.FailedImages, func(i, j int) bool {
+		return status.FailedImages[i].Image < status.FailedImages[j].Image
+	})
+
+	return status
+}
@@ -0,0 +1,59 @@
+/*
+ * Copyright 2023 Red Hat, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this inputFilePath except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package precache
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	ibuv1 "github.com/openshift-kni/lifecycle-agent/api/imagebasedupgrade/v1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestProgressToStatus(t *testing.T) {
+	start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	progress := &Progress{Total: 4, StartTime: start}
+
+	progress.Update("quay.io/b:1", 0, fmt.Errorf("manifest unknown"))
+	progress.Update("quay.io/ok:1", 1000, nil)
+	// An update from a pull that read the container storage earlier does not lower the count
+	progress.Update("quay.io/a:1", 400, fmt.Errorf("%s", strings.Repeat("x", MaxPullErrorLength+10)))
+
+	// 3 images processed in 3 minutes, 1 remaining
+	now := start.Add(3 * time.Minute)
+	eta := metav1.NewTime(now.Add(time.Minute))
+	assert.Equal(t, &ibuv1.PrecacheStatus{
+		Total:                   4,
+		Pulled:                  1,
+		Failed:                  2,
+		BytesDownloaded:         1000,
+		EstimatedCompletionTime: &eta,
+		FailedImages: []ibuv1.PrecacheFailedImage{
+			{Image: "quay.io/a:1", Error: strings.Repeat("x", MaxPullErrorLength)},
+			{Image: "quay.io/b:1", Error: "manifest unknown"},
+		},
+	}, progress.ToStatus(now))
+
+	// No estimate once every image is processed
+	progress.Update("quay.io/ok:2", 1500, nil)
+	status := progress.ToStatus(start.Add(4 * time.Minute))
+	assert.Nil(t, status.EstimatedCompletionTime)
+	assert.Equal(t, int64(1500), status.BytesDownloaded)
+}
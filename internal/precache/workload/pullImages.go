@@ -17,11 +17,13 @@
 package workload
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 
@@ -38,6 +40,10 @@ const (
 	DefaultAuthFile string = "/var/lib/kubelet/config.json"
 )
 
+// StorageLayersFile is the containers/storage file listing the layers in the container storage, along with the digest
+// and size of the compressed blob each layer was pulled from
+const StorageLayersFile string = "/var/lib/containers/storage/overlay-layers/layers.json"
+
 var (
 	logExec = &log.Logger{
 		Level: log.ErrorLevel, // reducing log level and only report if the exec calls fail
@@ -65,6 +71,70 @@ func podmanImgPull(image, authFile string) error {
 	return nil
 }
 
+// storageLayer is the part of a containers/storage layer record needed to count the bytes downloaded
+type storageLayer struct {
+	CompressedDigest string `json:"compressed-diff-digest,omitempty"`
+	CompressedSize   int64  `json:"compressed-size,omitempty"`
+}
+
+// readLayerSizes returns the compressed size of the layers in the container storage, keyed by the digest of the blob
+// they were pulled from. The layers not pulled from a registry have no compressed digest and are skipped.
+func readLayerSizes(layersFile string) (map[string]int64, error) {
+	data, err := os.ReadFile(layersFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read container storage layers: %w", err)
+	}
+	var layers []storageLayer
+	if err := json.Unmarshal(data, &layers); err != nil {
+		return nil, fmt.Errorf("failed to parse container storage layers from %s: %w", layersFile, err)
+	}
+	sizes := make(map[string]int64, len(layers))
+	for _, layer := range layers {
+		if layer.CompressedDigest != "" {
+			sizes[layer.CompressedDigest] = layer.CompressedSize
+		}
+	}
+	return sizes, nil
+}
+
+// layerTracker counts the bytes downloaded by the image pulls, from the layers added to the container storage since
+// it was created
+type layerTracker struct {
+	layersFile string
+	initial    map[string]int64
+}
+
+// newLayerTracker snapshots the layers in the container storage. It returns nil if they cannot be read, in which case
+// the bytes downloaded are not counted.
+func newLayerTracker(layersFile string) *layerTracker {
+	initial, err := readLayerSizes(layersFile)
+	if err != nil {
+		log.Errorf("Bytes downloaded will not be reported: %v", err)
+		return nil
+	}
+	return &layerTracker{layersFile: layersFile, initial: initial}
+}
+
+// bytesDownloaded returns the compressed size of the layers added to the container storage since the tracker was
+// created, or 0 if they cannot be read
+func (t *layerTracker) bytesDownloaded() int64 {
+	if t == nil {
+		return 0
+	}
+	current, err := readLayerSizes(t.layersFile)
+	if err != nil {
+		log.Errorf("failed to count bytes downloaded: %v", err)
+		return 0
+	}
+	var total int64
+	for digest, size := range current {
+		if _, found := t.initial[digest]; !found {
+			total += size
+		}
+	}
+	return total
+}
+
 func podmanImgExists(image string) bool {
 	args := []string{"image", "exists", image}
 	_, err := Executor.Execute("podman", args...)
@@ -75,7 +145,7 @@ func podmanImgExists(image string) bool {
 }
 
 // pullImage attempts to pull an image via podman CLI
-func pullImage(image, authFile string, progress *precache.Progress) error {
+func pullImage(image, authFile string, progress *precache.Progress, layers *layerTracker) error {
 
 	var err error
 	for i := 0; i < MaxRetries; i++ {
@@ -93,7 +163,7 @@ func pullImage(image, authFile string, progress *precache.Progress) error {
 		}
 	}
 	// update precache progress tracker
-	progress.Update(err == nil, image)
+	progress.Update(image, layers.bytesDownloaded(), err)
 
 	// persist progress to file
 	progress.Persist(precache.StatusFile)
@@ -123,12 +193,14 @@ func PullImages(precacheSpec []string, authFile string) *precache.Progress {
 
 	// Initialize progress tracking
 	progress := &precache.Progress{
-		Total:  len(precacheSpec),
-		Pulled: 0,
-		Failed: 0,
+		Total:     len(precacheSpec),
+		Pulled:    0,
+		Failed:    0,
+		StartTime: time.Now(),
 	}
 
 	log.Infof("Will attempt to pull %d images", len(precacheSpec))
+	layers := newLayerTracker(StorageLayersFile)
 
 	// Create wait group and pull images
 	var wg sync.WaitGroup
@@ -148,7 +220,7 @@ func PullImages(precacheSpec []string, authFile string) *precache.Progress {
 				<-threads
 				wg.Done()
 			}()
-			err := pullImage(image, authFile, progress)
+			err := pullImage(image, authFile, progress, layers)
 
 			if err != nil {
 				log.Errorf("Failed to pull image: %s, error: %v", image, err)
@@ -0,0 +1,55 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLayerTrackerBytesDownloaded(t *testing.T) {
+	layersFile := filepath.Join(t.TempDir(), "layers.json")
+	writeLayers := func(content string) {
+		assert.NoError(t, os.WriteFile(layersFile, []byte(content), 0o600))
+	}
+
+	writeLayers(`[{"id":"a","compressed-diff-digest":"sha256:a","compressed-size":100,"diff-size":300}]`)
+	layers := newLayerTracker(layersFile)
+	assert.NotNil(t, layers)
+	assert.Equal(t, int64(0), layers.bytesDownloaded())
+
+	// Only the layers pulled since the tracker was created are counted, the layers without a compressed digest
+	// were not downloaded
+	writeLayers(`[
+		{"id":"a","compressed-diff-digest":"sha256:a","compressed-size":100,"diff-size":300},
+		{"id":"b","compressed-diff-digest":"sha256:b","compressed-size":20,"diff-size":50},
+		{"id":"c","compressed-diff-digest":"sha256:c","compressed-size":5,"diff-size":10},
+		{"id":"d","diff-size":1000}
+	]`)
+	assert.Equal(t, int64(25), layers.bytesDownloaded())
+
+	writeLayers(`not json`)
+	assert.Equal(t, int64(0), layers.bytesDownloaded())
+
+	// Nothing is counted if the container storage could not be read initially
+	assert.Nil(t, newLayerTracker(filepath.Join(t.TempDir(), "missing.json")))
+	var missing *layerTracker
+	assert.Equal(t, int64(0), missing.bytesDownloaded())
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/openshift-kni/lifecycle-agent/lca-cli/ops"
 	rpmostreeclient "github.com/openshift-kni/lifecycle-agent/lca-cli/ostreeclient"
 	lcautils "github.com/openshift-kni/lifecycle-agent/utils"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 var (
@@ -227,6 +228,9 @@ func (c *IBURebootClient) InitiateRollback(msg string) error {
 	}
 
 	utils.SetUpgradeStatusFailed(savedIbu, msg)
+	// The rollback is counted by LCA once it restores the saved IBU in the original stateroot, as the metrics of the
+	// process starting it do not survive the reboot
+	metav1.SetMetaDataAnnotation(&savedIbu.ObjectMeta, utils.AutoRollbackStartedAnnotation, "")
 
 	if err := lcautils.MarshalToFile(savedIbu, filePath); err != nil {
 		return fmt.Errorf("unable to save updated ibu CR to %s: %w", filePath, err)
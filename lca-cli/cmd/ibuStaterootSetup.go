@@ -77,8 +77,14 @@ func ibuStaterootSetupRun() error {
 		return fmt.Errorf("failed get IBU cr: %w", err)
 	}
 
+	// The seed image is pinned to the digest resolved at the start of Prep
+	seedImage, err := controllers.GetPinnedSeedImage(ibu)
+	if err != nil {
+		return fmt.Errorf("failed to get seed image: %w", err)
+	}
+
 	logger.Info("Starting signal handler")
-	initStaterootSetupSigHandler(logger, opsClient, ibu.Spec.SeedImageRef.Image)
+	initStaterootSetupSigHandler(logger, opsClient, seedImage)
 
 	logger.Info("Pulling seed image")
 	if err := controllers.GetSeedImage(c, ctx, ibu, logger, hostCommandsExecutor); err != nil {
@@ -86,10 +92,15 @@ func ibuStaterootSetupRun() error {
 	}
 
 	logger.Info("Setting up stateroot")
-	if err := prep.SetupStateroot(logger, opsClient, ostreeClient, rpmOstreeClient, ibu.Spec.SeedImageRef.Image, ibu.Spec.SeedImageRef.Version, false); err != nil {
+	if err := prep.SetupStateroot(logger, opsClient, ostreeClient, rpmOstreeClient, seedImage, ibu.Spec.SeedImageRef.Version, false); err != nil {
 		return fmt.Errorf("failed to complete stateroot setup: %w", err)
 	}
 
+	logger.Info("Recording seed image digest in the new stateroot")
+	if err := os.WriteFile(common.GetStaterootSeedImageDigestFile(ibu), []byte(ibu.Status.SeedImageDigest), 0o600); err != nil {
+		return fmt.Errorf("failed to record seed image digest: %w", err)
+	}
+
 	logger.Info("Writing IBU AutoRollbackConfig file")
 	if err := reboot.WriteIBUAutoRollbackConfigFile(logger, ibu); err != nil {
 		return fmt.Errorf("failed to write auto-rollback config: %w", err)
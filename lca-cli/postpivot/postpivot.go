@@ -442,11 +442,13 @@ func (p *PostPivot) applyManifests(ctx context.Context, mPath string, dynamicCli
 		return nil
 	}
 
+	// Each file is a wave, the manifests it contains must be ready before the next file is applied
 	for _, mFile := range mFiles {
 		decoder, err := utils.GetYamlOrJsonDecoder(filepath.Join(mPath, mFile.Name()))
 		if err != nil {
 			return fmt.Errorf("failed to read manifest %s: %w", mFile.Name(), err)
 		}
+		var applied []*unstructured.Unstructured
 		for {
 			var obj map[string]interface{}
 			// decode until EOF since we might get multiple definitions in a single file
@@ -458,34 +460,42 @@ func (p *PostPivot) applyManifests(ctx context.Context, mPath string, dynamicCli
 			if err != nil {
 				return fmt.Errorf("failed to decode manifest %s: %w", mFile.Name(), err)
 			}
-			err = p.handleManifest(ctx, mPath, dynamicClient, restMapper, obj, mFile)
+			manifests, err := p.handleManifest(ctx, mPath, dynamicClient, restMapper, obj, mFile)
 			if err != nil {
 				return err
 			}
+			applied = append(applied, manifests...)
+		}
+
+		if err := extramanifest.WaitForManifestsReady(ctx, dynamicClient, restMapper, applied); err != nil {
+			return fmt.Errorf("failed to wait for manifests in %s to be ready: %w", mFile.Name(), err)
 		}
 	}
 	return nil
 }
 
-func (p *PostPivot) handleManifest(ctx context.Context, mPath string, dynamicClient dynamic.Interface, restMapper meta.RESTMapper, obj map[string]interface{}, mFile os.DirEntry) error {
+func (p *PostPivot) handleManifest(ctx context.Context, mPath string, dynamicClient dynamic.Interface, restMapper meta.RESTMapper, obj map[string]interface{}, mFile os.DirEntry) ([]*unstructured.Unstructured, error) {
+	var applied []*unstructured.Unstructured
 	if manifests, ok := obj["items"]; ok {
 		for _, m := range manifests.([]interface{}) {
 			manifest := unstructured.Unstructured{}
 			manifest.Object = m.(map[string]interface{})
 			if err := extramanifest.ApplyExtraManifest(ctx, dynamicClient, restMapper, &manifest, false); err != nil {
-				return fmt.Errorf("failed to apply manifest: %w", err)
+				return nil, fmt.Errorf("failed to apply manifest: %w", err)
 			}
+			applied = append(applied, &manifest)
 		}
 	} else {
 		manifest := unstructured.Unstructured{}
 		manifest.Object = obj
 		if err := extramanifest.ApplyExtraManifest(ctx, dynamicClient, restMapper, &manifest, false); err != nil {
-			return fmt.Errorf("failed to apply manifest: %w", err)
+			return nil, fmt.Errorf("failed to apply manifest: %w", err)
 		}
+		applied = append(applied, &manifest)
 	}
 
 	p.log.Infof("manifest applied: %s", filepath.Join(mPath, mFile.Name()))
-	return nil
+	return applied, nil
 }
 
 func (p *PostPivot) recoverLvmDevices() error {
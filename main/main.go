@@ -262,10 +262,8 @@ func main() {
 		os.Exit(1)
 	}
 
-	backupRestore := &backuprestore.BRHandler{
-		Client: mgr.GetClient(), DynamicClient: dynamicClient, Log: log.WithName("BackupRestore")}
 	extraManifest := &extramanifest.EMHandler{
-		Client: mgr.GetClient(), DynamicClient: dynamicClient, Log: log.WithName("ExtraManifest")}
+		Client: mgr.GetClient(), NoncachedClient: mgr.GetAPIReader(), DynamicClient: dynamicClient, Log: log.WithName("ExtraManifest")}
 
 	containerStorageMountpointTarget, err := chrootOp.GetContainerStorageTarget()
 	if err != nil {
@@ -289,6 +287,9 @@ func main() {
 		os.Exit(1)
 	}
 
+	backupRestore := &backuprestore.BRHandler{
+		Client: mgr.GetClient(), DynamicClient: dynamicClient, Discovery: clientset.Discovery(), Log: log.WithName("BackupRestore")}
+
 	if err = (&controllers.ImageBasedUpgradeReconciler{
 		Client:          mgr.GetClient(),
 		NoncachedClient: mgr.GetAPIReader(),
@@ -315,6 +316,7 @@ func main() {
 			RPMOstreeClient: rpmOstreeClient,
 			OstreeClient:    ostreeClient,
 			RebootClient:    ibuRebootClient,
+			Clientset:       clientset,
 		},
 		Mux:       mux,
 		Clientset: clientset,
@@ -139,7 +139,7 @@ func GetClusterInfo(ctx context.Context, client runtimeclient.Client) (*ClusterI
 		return nil, err
 	}
 
-	clusterNetworks, serviceNetworks, err := getClusterNetworks(ctx, client)
+	clusterNetworks, serviceNetworks, err := GetClusterNetworks(ctx, client)
 	if err != nil {
 		return nil, err
 	}
@@ -446,7 +446,8 @@ func getCommonNameFromCertificate(certPEM []byte) (string, error) {
 	return cert.Subject.CommonName, nil
 }
 
-func getClusterNetworks(ctx context.Context, client runtimeclient.Client) ([]string, []string, error) {
+// GetClusterNetworks returns the cluster network and service network CIDRs of the cluster
+func GetClusterNetworks(ctx context.Context, client runtimeclient.Client) ([]string, []string, error) {
 	// oc get network cluster -o yaml
 	network := &ocp_config_v1.Network{}
 	if err := client.Get(ctx,
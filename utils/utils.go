@@ -36,6 +36,7 @@ import (
 	ipcv1 "github.com/openshift-kni/lifecycle-agent/api/ipconfig/v1"
 	"github.com/openshift-kni/lifecycle-agent/controllers/utils"
 	"github.com/openshift-kni/lifecycle-agent/internal/common"
+	"github.com/openshift-kni/lifecycle-agent/internal/metrics"
 	mcfgv1 "github.com/openshift/api/machineconfiguration/v1"
 	cp "github.com/otiai10/copy"
 	"github.com/sirupsen/logrus"
@@ -346,6 +347,10 @@ func InitIBU(ctx context.Context, c runtimeclient.Client, log *logr.Logger) erro
 	// Strip the ResourceVersion, otherwise the restore fails
 	ibu.SetResourceVersion("")
 
+	// An automatic rollback started before the reboot is counted once the saved IBU is restored
+	_, autoRollback := ibu.GetAnnotations()[utils.AutoRollbackStartedAnnotation]
+	delete(ibu.Annotations, utils.AutoRollbackStartedAnnotation)
+
 	log.Info("Saved IBU CR found, restoring ...")
 	if err := c.Delete(ctx, ibu); err != nil {
 		if !k8serrors.IsNotFound(err) {
@@ -370,6 +375,9 @@ func InitIBU(ctx context.Context, c runtimeclient.Client, log *logr.Logger) erro
 	if err := os.Remove(filePath); err != nil {
 		return fmt.Errorf("failed to remove IBU in %s: %w", filePath, err)
 	}
+	if autoRollback {
+		metrics.RecordIBURollback()
+	}
 	log.Info("Restore successful and saved IBU CR removed")
 	return nil
 }